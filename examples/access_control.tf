@@ -0,0 +1,16 @@
+resource "humio_group" "example_group" {
+  name        = "ops"
+  lookup_name = "cn=ops,ou=groups,dc=example,dc=com"
+}
+
+resource "humio_role" "example_role" {
+  name             = "read-only"
+  view_permissions = ["ReadAccess"]
+}
+
+# The only way to grant the ops group access to the repository is through this resource.
+resource "humio_group_role_assignment" "example_assignment" {
+  repository = humio_repository.example_repo_all_fields_set.name
+  group_id   = humio_group.example_group.id
+  role_id    = humio_role.example_role.id
+}
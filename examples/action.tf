@@ -0,0 +1,33 @@
+resource "humio_action" "example_action_email" {
+  repository = "sandbox"
+  name       = "example_action_email"
+
+  email {
+    recipients       = ["ops@example.org"]
+    subject_template = "{alert_name} has alerted"
+  }
+}
+
+resource "humio_action" "example_action_webhook" {
+  repository = "sandbox"
+  name       = "example_action_webhook"
+
+  webhook {
+    url    = "https://example.org/hooks/humio"
+    method = "PUT"
+    headers = {
+      "Content-Type" = "application/json"
+    }
+    ignore_ssl = false
+    use_proxy  = true
+  }
+}
+
+resource "humio_action" "example_action_upload_file" {
+  repository = "sandbox"
+  name       = "example_action_upload_file"
+
+  upload_file {
+    file_name = "triggered_alerts.csv"
+  }
+}
@@ -0,0 +1,25 @@
+# The template is the YAML (or JSON) shown when exporting a dashboard from the Humio UI.
+resource "humio_dashboard" "example_dashboard" {
+  repository = "sandbox"
+  name       = "example_dashboard"
+  template   = file("${path.module}/dashboards/example_dashboard.yaml")
+}
+
+resource "humio_dashboard" "example_dashboard_inline" {
+  repository = "sandbox"
+  name       = "example_dashboard_inline"
+  template = yamlencode({
+    updateFrequency = "never"
+    widgets = {
+      errors = {
+        x             = 0
+        y             = 0
+        width         = 4
+        height        = 4
+        queryString   = "loglevel=ERROR | count()"
+        visualization = "single-value"
+        title         = "Errors"
+      }
+    }
+  })
+}
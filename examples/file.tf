@@ -0,0 +1,16 @@
+# Uploaded again whenever hosts.csv changes, and usable from queries with match(file="hosts.csv", ...).
+resource "humio_file" "example_file_source" {
+  repository = "sandbox"
+  name       = "hosts.csv"
+  source     = "${path.module}/files/hosts.csv"
+}
+
+resource "humio_file" "example_file_content" {
+  repository = "sandbox"
+  name       = "severities.csv"
+  content    = <<-EOT
+    level,severity
+    ERROR,high
+    WARN,medium
+  EOT
+}
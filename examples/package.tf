@@ -0,0 +1,9 @@
+# source is either a zip file or a directory with a manifest.yaml, as created with humioctl packages.
+resource "humio_package" "example_package" {
+  repository = "sandbox"
+  source     = "${path.module}/packages/myorg-accesslogs.zip"
+}
+
+output "example_package_version" {
+  value = humio_package.example_package.version
+}
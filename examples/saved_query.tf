@@ -0,0 +1,18 @@
+resource "humio_saved_query" "example_saved_query_minimal_fields_set" {
+  repository = "sandbox"
+  name       = "example_saved_query_minimal_fields_set"
+  query      = "loglevel=ERROR"
+}
+
+resource "humio_saved_query" "example_saved_query_all_fields_set" {
+  repository  = "sandbox"
+  name        = "example_saved_query_all_fields_set"
+  query       = "loglevel=ERROR | timechart(series=host)"
+  start       = "7d"
+  end         = "now"
+  is_live     = true
+  widget_type = "time-chart"
+  options = jsonencode({
+    interpolation = "monotone"
+  })
+}
@@ -0,0 +1,14 @@
+resource "humio_scheduled_search" "example_scheduled_search" {
+  repository     = humio_notifier.example_email.repository
+  name           = "example_scheduled_search"
+  description    = "Errors during the last week, sent every Monday morning"
+  query          = "loglevel=ERROR | groupby(host)"
+  start          = "7d"
+  end            = "now"
+  schedule       = "0 8 * * MON"
+  time_zone      = "UTC+01:00"
+  backfill_limit = 3
+
+  actions = [humio_notifier.example_email.notifier_id]
+  labels  = ["terraform", "report"]
+}
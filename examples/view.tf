@@ -0,0 +1,36 @@
+resource "humio_view" "example_view" {
+  name        = "example_view"
+  description = "Errors from all repositories"
+
+  repository_connection {
+    repository = humio_repository.example_repo_all_fields_set.name
+    filter     = "loglevel=ERROR"
+  }
+
+  repository_connection {
+    repository = "sandbox"
+  }
+}
+
+# Alerts and notifiers can live in views as well as repositories. Parsers and ingest tokens can only be created in
+# repositories.
+resource "humio_notifier" "example_view_email" {
+  repository = humio_view.example_view.name
+  name       = "example_view_email"
+  entity     = "EmailNotifier"
+
+  email {
+    recipients = ["ops@example.com"]
+  }
+}
+
+resource "humio_alert" "example_view_alert" {
+  repository = humio_view.example_view.name
+  name       = "example_view_alert"
+
+  notifiers = [humio_notifier.example_view_email.id]
+
+  throttle_time_millis = 300000
+  query                = "count()"
+  start                = "24h"
+}
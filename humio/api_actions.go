@@ -0,0 +1,165 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"context"
+	"fmt"
+)
+
+// Action types, named after their GraphQL types in the Humio schema.
+const (
+	actionTypeEmail            = "EmailAction"
+	actionTypeHumioRepo        = "HumioRepoAction"
+	actionTypeOpsGenie         = "OpsGenieAction"
+	actionTypePagerDuty        = "PagerDutyAction"
+	actionTypeSlack            = "SlackAction"
+	actionTypeSlackPostMessage = "SlackPostMessageAction"
+	actionTypeUploadFile       = "UploadFileAction"
+	actionTypeVictorOps        = "VictorOpsAction"
+	actionTypeWebhook          = "WebhookAction"
+)
+
+// action is a Humio action. Like humio.Notifier, the settings specific to each type of action are kept in Properties,
+// keyed by their GraphQL field names.
+type action struct {
+	ID         string
+	Name       string
+	Type       string
+	Properties map[string]interface{}
+}
+
+const actionsQuery = `query($repository: String!) {
+	searchDomain(name: $repository) {
+		actions {
+			__typename
+			id
+			name
+			... on EmailAction { recipients subjectTemplate bodyTemplate useProxy }
+			... on HumioRepoAction { ingestToken }
+			... on OpsGenieAction { apiUrl genieKey useProxy }
+			... on PagerDutyAction { severity routingKey useProxy }
+			... on SlackAction { url fields { fieldName value } useProxy }
+			... on SlackPostMessageAction { apiToken channels fields { fieldName value } useProxy }
+			... on UploadFileAction { fileName }
+			... on VictorOpsAction { messageType notifyUrl useProxy }
+			... on WebhookAction { method url headers { header value } bodyTemplate ignoreSSL useProxy }
+		}
+	}
+}`
+
+// listActions returns the actions in the repository. Each type of action has its own GraphQL type and mutations, so
+// actions are managed with graphQL rather than the GraphQL client, which needs a Go type per GraphQL type.
+func (c *apiClient) listActions(ctx context.Context, repository string) ([]action, error) {
+	v, err := c.cache.get(cacheActions, repository, func() (interface{}, error) {
+		var q struct {
+			SearchDomain struct {
+				Actions []map[string]interface{}
+			}
+		}
+		variables := map[string]interface{}{
+			"repository": repository,
+		}
+		err := c.graphQL(ctx, actionsQuery, variables, &q)
+		return q.SearchDomain.Actions, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var actions []action
+	for _, fields := range v.([]map[string]interface{}) {
+		a := action{
+			ID:         fmt.Sprint(fields["id"]),
+			Name:       fmt.Sprint(fields["name"]),
+			Type:       fmt.Sprint(fields["__typename"]),
+			Properties: map[string]interface{}{},
+		}
+		for key, value := range fields {
+			switch key {
+			case "__typename", "id", "name":
+			default:
+				a.Properties[key] = value
+			}
+		}
+		actions = append(actions, a)
+	}
+	return actions, nil
+}
+
+// getAction returns the action with the given name.
+func (c *apiClient) getAction(ctx context.Context, repository, name string) (*action, error) {
+	actions, err := c.listActions(ctx, repository)
+	if err != nil {
+		return nil, err
+	}
+	for i := range actions {
+		if actions[i].Name == name {
+			return &actions[i], nil
+		}
+	}
+	return nil, newNotFoundError("action %s in repository %s", name, repository)
+}
+
+// createAction creates the action and returns its ID.
+func (c *apiClient) createAction(ctx context.Context, repository string, a *action) (string, error) {
+	defer c.cache.invalidate(cacheActions, repository)
+
+	input := map[string]interface{}{
+		"viewName": repository,
+		"name":     a.Name,
+	}
+	for key, value := range a.Properties {
+		input[key] = value
+	}
+
+	var m map[string]struct {
+		ID string
+	}
+	query := fmt.Sprintf("mutation($input: Create%[1]s!) { create%[1]s(input: $input) { id } }", a.Type)
+	if err := c.graphQL(ctx, query, map[string]interface{}{"input": input}, &m); err != nil {
+		return "", err
+	}
+	return m["create"+a.Type].ID, nil
+}
+
+func (c *apiClient) updateAction(ctx context.Context, repository string, a *action) error {
+	defer c.cache.invalidate(cacheActions, repository)
+
+	input := map[string]interface{}{
+		"viewName": repository,
+		"id":       a.ID,
+		"name":     a.Name,
+	}
+	for key, value := range a.Properties {
+		input[key] = value
+	}
+
+	var m map[string]interface{}
+	query := fmt.Sprintf("mutation($input: Update%[1]s!) { update%[1]s(input: $input) { id } }", a.Type)
+	return c.graphQL(ctx, query, map[string]interface{}{"input": input}, &m)
+}
+
+func (c *apiClient) deleteAction(ctx context.Context, repository, id string) error {
+	defer c.cache.invalidate(cacheActions, repository)
+
+	input := map[string]interface{}{
+		"viewName": repository,
+		"id":       id,
+	}
+
+	var m map[string]interface{}
+	return c.graphQL(ctx, "mutation($input: DeleteAction!) { deleteAction(input: $input) }", map[string]interface{}{"input": input}, &m)
+}
@@ -0,0 +1,91 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	humio "github.com/humio/cli/api"
+)
+
+func alertsPath(repository string) string {
+	return fmt.Sprintf("/api/v1/repositories/%s/alerts", url.PathEscape(repository))
+}
+
+// listAlerts returns the alerts in the repository.
+func (c *apiClient) listAlerts(ctx context.Context, repository string) ([]humio.Alert, error) {
+	var alerts []humio.Alert
+	if err := c.restJSON(ctx, http.MethodGet, alertsPath(repository), nil, &alerts); err != nil {
+		return nil, err
+	}
+	return alerts, nil
+}
+
+// alertID returns the ID of the alert with the given name. The REST API addresses alerts by ID, while the provider
+// identifies them by name.
+func (c *apiClient) alertID(ctx context.Context, repository, name string) (string, error) {
+	alerts, err := c.listAlerts(ctx, repository)
+	if err != nil {
+		return "", err
+	}
+	for _, alert := range alerts {
+		if alert.Name == name {
+			return alert.ID, nil
+		}
+	}
+	return "", newNotFoundError("alert %s in repository %s", name, repository)
+}
+
+// createAlert creates the alert, failing if the repository already has an alert with the same name.
+func (c *apiClient) createAlert(ctx context.Context, repository string, alert *humio.Alert) error {
+	_, err := c.alertID(ctx, repository, alert.Name)
+	if err == nil {
+		return fmt.Errorf("alert with name %s already exists", alert.Name)
+	}
+	if !isNotFoundError(err) {
+		return err
+	}
+	return c.restJSON(ctx, http.MethodPost, alertsPath(repository)+"/", alertRequestBody(alert, ""), nil)
+}
+
+// updateAlert replaces the alert with the same name as alert.
+func (c *apiClient) updateAlert(ctx context.Context, repository string, alert *humio.Alert) error {
+	id, err := c.alertID(ctx, repository, alert.Name)
+	if err != nil {
+		return err
+	}
+	return c.restJSON(ctx, http.MethodPut, alertsPath(repository)+"/"+url.PathEscape(id), alertRequestBody(alert, id), nil)
+}
+
+// deleteAlert deletes the alert with the given name.
+func (c *apiClient) deleteAlert(ctx context.Context, repository, name string) error {
+	id, err := c.alertID(ctx, repository, name)
+	if err != nil {
+		return err
+	}
+	return c.restJSON(ctx, http.MethodDelete, alertsPath(repository)+"/"+url.PathEscape(id), nil, nil)
+}
+
+// alertRequestBody returns a copy of alert with the given ID. Humio requires the notifiers of an alert to be set, even
+// when it has none.
+func alertRequestBody(alert *humio.Alert, id string) *humio.Alert {
+	body := *alert
+	body.ID = id
+	body.Notifiers = nonNilStrings(body.Notifiers)
+	return &body
+}
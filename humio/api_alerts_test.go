@@ -0,0 +1,89 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httputil"
+	"net/url"
+	"sync/atomic"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	humio "github.com/humio/cli/api"
+)
+
+const badGatewayPage = `<html>
+<head><title>502 Bad Gateway</title></head>
+<body><center><h1>502 Bad Gateway</h1></center></body>
+</html>`
+
+// TestAlertReadRetriesBadGateway checks that the HTML page of a proxy answering 502 Bad Gateway while Humio restarts
+// is returned as a transient error and retried, rather than failing to decode as JSON.
+func TestAlertReadRetriesBadGateway(t *testing.T) {
+	server := newFakeHumioServer()
+	defer server.Close()
+	serverURL, _ := url.Parse(server.URL)
+
+	direct := newAPIClient(serverURL, fakeHumioToken, http.DefaultTransport)
+	alert := humio.Alert{Name: "errors", Query: humio.HumioQuery{QueryString: "loglevel=ERROR", Start: "1h"}}
+	if err := direct.createAlert(context.Background(), "sandbox", &alert); err != nil {
+		t.Fatal(err)
+	}
+
+	var failures int32
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v1/repositories/sandbox/alerts" && atomic.AddInt32(&failures, -1) >= 0 {
+			w.Header().Set("Content-Type", "text/html")
+			w.WriteHeader(http.StatusBadGateway)
+			_, _ = w.Write([]byte(badGatewayPage))
+			return
+		}
+		httputil.NewSingleHostReverseProxy(serverURL).ServeHTTP(w, r)
+	}))
+	defer proxy.Close()
+
+	p := Provider()
+	diags := p.Configure(context.Background(), terraform.NewResourceConfigRaw(map[string]interface{}{
+		"addr":               proxy.URL,
+		"api_token":          fakeHumioToken,
+		"retry_max_attempts": 3,
+		"retry_max_wait":     "1ms",
+	}))
+	if diags.HasError() {
+		t.Fatal(diagnosticsToString(diags))
+	}
+	client := p.Meta().(*apiClient)
+
+	atomic.StoreInt32(&failures, 1)
+	if _, err := client.listAlerts(context.Background(), "sandbox"); !isTransientError(err) {
+		t.Fatalf("expected a transient error, got %v", err)
+	}
+
+	atomic.StoreInt32(&failures, 2)
+	d := resourceAlert().Data(nil)
+	d.SetId("sandbox+errors")
+	if diags := resourceAlertRead(context.Background(), d, client); diags.HasError() {
+		t.Fatal(diagnosticsToString(diags))
+	}
+	if got := d.Get("query").(string); got != alert.Query.QueryString {
+		t.Errorf("read alert with query %q, want %q", got, alert.Query.QueryString)
+	}
+	if n := atomic.LoadInt32(&failures); n >= 0 {
+		t.Errorf("expected both 502 responses to be retried, %d left", n+1)
+	}
+}
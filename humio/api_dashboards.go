@@ -0,0 +1,140 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"context"
+
+	"github.com/shurcooL/graphql"
+)
+
+// dashboard is a Humio dashboard as described by its YAML template, the format used when exporting dashboards from the
+// Humio UI.
+type dashboard struct {
+	ID       string
+	Name     string
+	Template string
+}
+
+// CreateDashboardFromTemplateInput is the GraphQL input type for creating a dashboard from a template. The type name is
+// used by the GraphQL client when declaring query variables, so it must match the name in the Humio schema.
+type CreateDashboardFromTemplateInput struct {
+	SearchDomainName graphql.String `json:"searchDomainName"`
+	OverrideName     graphql.String `json:"overrideName"`
+	Template         graphql.String `json:"template"`
+}
+
+// DeleteDashboardInput is the GraphQL input type for deleting a dashboard.
+type DeleteDashboardInput struct {
+	ID graphql.String `json:"id"`
+}
+
+// listDashboards returns the dashboards in the repository.
+func (c *apiClient) listDashboards(ctx context.Context, repository string) ([]dashboard, error) {
+	var q struct {
+		SearchDomain struct {
+			Dashboards []struct {
+				ID           string
+				Name         string
+				TemplateYaml string
+			}
+		} `graphql:"searchDomain(name: $repository)"`
+	}
+
+	variables := map[string]interface{}{
+		"repository": graphql.String(repository),
+	}
+
+	if err := c.query(ctx, &q, variables); err != nil {
+		return nil, err
+	}
+
+	dashboards := make([]dashboard, len(q.SearchDomain.Dashboards))
+	for i, d := range q.SearchDomain.Dashboards {
+		dashboards[i] = dashboard{
+			ID:       d.ID,
+			Name:     d.Name,
+			Template: d.TemplateYaml,
+		}
+	}
+	return dashboards, nil
+}
+
+// getDashboard returns the dashboard with the given name. Dashboards are identified by name in Terraform, while Humio
+// identifies them by an ID that changes whenever the dashboard is recreated from a template.
+func (c *apiClient) getDashboard(ctx context.Context, repository, name string) (*dashboard, error) {
+	dashboards, err := c.listDashboards(ctx, repository)
+	if err != nil {
+		return nil, err
+	}
+	for i := range dashboards {
+		if dashboards[i].Name == name {
+			return &dashboards[i], nil
+		}
+	}
+	return nil, newNotFoundError("dashboard %s in repository %s", name, repository)
+}
+
+func (c *apiClient) createDashboard(ctx context.Context, repository string, d *dashboard) error {
+	var m struct {
+		CreateDashboardFromTemplate struct {
+			Type string `graphql:"__typename"`
+		} `graphql:"createDashboardFromTemplate(input: $input)"`
+	}
+
+	variables := map[string]interface{}{
+		"input": CreateDashboardFromTemplateInput{
+			SearchDomainName: graphql.String(repository),
+			OverrideName:     graphql.String(d.Name),
+			Template:         graphql.String(d.Template),
+		},
+	}
+
+	return c.mutate(ctx, &m, variables)
+}
+
+// updateDashboard changes the dashboard with the given ID to match the template in place, so it keeps its ID and links
+// to it keep working. The mutation only covers the keys in dashboardUpdatableKeys, changing the rest of the template
+// requires recreating the dashboard.
+func (c *apiClient) updateDashboard(ctx context.Context, id string, d *dashboard) error {
+	input, err := dashboardUpdateInput(id, d.Name, d.Template)
+	if err != nil {
+		return err
+	}
+
+	var m map[string]interface{}
+	return c.graphQL(
+		ctx,
+		"mutation($input: UpdateDashboardInput!) { updateDashboard(input: $input) { __typename } }",
+		map[string]interface{}{"input": input},
+		&m,
+	)
+}
+
+func (c *apiClient) deleteDashboard(ctx context.Context, id string) error {
+	var m struct {
+		DeleteDashboard struct {
+			Type string `graphql:"__typename"`
+		} `graphql:"deleteDashboard(input: $input)"`
+	}
+
+	variables := map[string]interface{}{
+		"input": DeleteDashboardInput{
+			ID: graphql.String(id),
+		},
+	}
+
+	return c.mutate(ctx, &m, variables)
+}
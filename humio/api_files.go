@@ -0,0 +1,104 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+
+	humio "github.com/humio/cli/api"
+	"github.com/shurcooL/graphql"
+)
+
+// uploadFile uploads a lookup file to a repository, replacing any existing file with the same name. Files can only be
+// uploaded through the REST API.
+func (c *apiClient) uploadFile(ctx context.Context, repository, name string, content io.Reader) error {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile("file", name)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(part, content); err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+
+	resp, err := c.do(
+		ctx,
+		http.MethodPost,
+		fmt.Sprintf("/api/v1/repositories/%s/files", url.PathEscape(repository)),
+		body,
+		writer.FormDataContentType(),
+	)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		b, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("non-200 OK status code: %v body: %q", resp.Status, b)
+	}
+	return nil
+}
+
+// downloadFile writes the content of a lookup file in the repository to w.
+func (c *apiClient) downloadFile(ctx context.Context, repository, name string, w io.Writer) error {
+	resp, err := c.do(
+		ctx,
+		http.MethodGet,
+		fmt.Sprintf("/api/v1/repositories/%s/files/%s", url.PathEscape(repository), url.PathEscape(name)),
+		nil,
+		humio.JSONContentType,
+	)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return newNotFoundError("file %s in repository %s", name, repository)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		b, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("non-200 OK status code: %v body: %q", resp.Status, b)
+	}
+	_, err = io.Copy(w, resp.Body)
+	return err
+}
+
+func (c *apiClient) removeFile(ctx context.Context, repository, name string) error {
+	var m struct {
+		RemoveFile struct {
+			Type string `graphql:"__typename"`
+		} `graphql:"removeFile(name: $repository, fileName: $name)"`
+	}
+
+	variables := map[string]interface{}{
+		"repository": graphql.String(repository),
+		"name":       graphql.String(name),
+	}
+
+	return c.mutate(ctx, &m, variables)
+}
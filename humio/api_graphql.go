@@ -0,0 +1,69 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	humio "github.com/humio/cli/api"
+)
+
+// graphQL sends query to the Humio GraphQL endpoint and decodes the data of the response into v. The GraphQL client
+// used by query and mutate builds queries from Go types and cannot decode fields of the JSON scalar type, so this is
+// used for queries returning such fields.
+func (c *apiClient) graphQL(ctx context.Context, query string, variables map[string]interface{}, v interface{}) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"query":     query,
+		"variables": variables,
+	})
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.do(ctx, http.MethodPost, "/graphql", bytes.NewReader(body), humio.JSONContentType)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		b, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("non-200 OK status code: %v body: %q", resp.Status, b)
+	}
+
+	var out struct {
+		Data   json.RawMessage
+		Errors []struct {
+			Message string
+		}
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return err
+	}
+	if len(out.Errors) > 0 {
+		messages := make([]string, len(out.Errors))
+		for i, e := range out.Errors {
+			messages[i] = e.Message
+		}
+		return fmt.Errorf("%s", strings.Join(messages, ", "))
+	}
+	return json.Unmarshal(out.Data, v)
+}
@@ -0,0 +1,77 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestGraphQL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Query     string
+			Variables map[string]interface{}
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Error(err)
+		}
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch req.Variables["repository"] {
+		case "sandbox":
+			_, _ = w.Write([]byte(`{"data": {"searchDomain": {"savedQueries": [{"name": "q", "options": {"columns": ["a"]}}]}}}`))
+		default:
+			_, _ = w.Write([]byte(`{"data": null, "errors": [{"message": "Could not find the view"}]}`))
+		}
+	}))
+	defer server.Close()
+
+	address, _ := url.Parse(server.URL)
+	c := newAPIClient(address, "secret", http.DefaultTransport)
+
+	var q struct {
+		SearchDomain struct {
+			SavedQueries []struct {
+				Name    string
+				Options json.RawMessage
+			}
+		}
+	}
+	err := c.graphQL(context.Background(), savedQueriesQuery, map[string]interface{}{"repository": "sandbox"}, &q)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(q.SearchDomain.SavedQueries[0].Options); got != `{"columns": ["a"]}` {
+		t.Errorf("unexpected options %s", got)
+	}
+
+	err = c.graphQL(context.Background(), savedQueriesQuery, map[string]interface{}{"repository": "missing"}, &q)
+	if !isNotFoundError(err) {
+		t.Errorf("expected not found error, got %v", err)
+	}
+
+	c = newAPIClient(address, "wrong", http.DefaultTransport)
+	err = c.graphQL(context.Background(), savedQueriesQuery, map[string]interface{}{"repository": "sandbox"}, &q)
+	if err == nil || isTransientError(err) {
+		t.Errorf("expected permanent error for a rejected token, got %v", err)
+	}
+}
@@ -0,0 +1,247 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"context"
+	"github.com/shurcooL/graphql"
+)
+
+// The Humio API client does not support groups, roles or role assignments, so the queries and mutations needed to
+// manage them live here and in api_roles.go.
+
+// group is a Humio group, optionally linked to an LDAP or SAML group through its lookup name.
+type group struct {
+	ID          string
+	DisplayName string
+	LookupName  string
+}
+
+// groupRole is a role granted to a group on a single repository or view.
+type groupRole struct {
+	RoleID     string
+	Repository string
+}
+
+// UpdateGroupInput is the GraphQL input type for updating a group. The type name is used by the GraphQL client when
+// declaring query variables, so it must match the name in the Humio schema.
+type UpdateGroupInput struct {
+	GroupID     graphql.String `json:"groupId"`
+	DisplayName graphql.String `json:"displayName"`
+	LookupName  graphql.String `json:"lookupName"`
+}
+
+// AssignRoleToGroupInput is the GraphQL input type for granting a role to a group on a repository or view.
+type AssignRoleToGroupInput struct {
+	ViewID  graphql.String `json:"viewId"`
+	GroupID graphql.String `json:"groupId"`
+	RoleID  graphql.String `json:"roleId"`
+}
+
+// RemoveRoleFromGroupInput is the GraphQL input type for revoking a role from a group on a repository or view.
+type RemoveRoleFromGroupInput struct {
+	ViewID  graphql.String `json:"viewId"`
+	GroupID graphql.String `json:"groupId"`
+	RoleID  graphql.String `json:"roleId"`
+}
+
+func (c *apiClient) getGroup(ctx context.Context, id string) (*group, error) {
+	var q struct {
+		Group struct {
+			ID          string
+			DisplayName string
+			LookupName  string
+		} `graphql:"group(groupId: $id)"`
+	}
+
+	variables := map[string]interface{}{
+		"id": graphql.String(id),
+	}
+
+	if err := c.query(ctx, &q, variables); err != nil {
+		return nil, err
+	}
+	if q.Group.ID == "" {
+		return nil, newNotFoundError("group %s", id)
+	}
+
+	return &group{
+		ID:          q.Group.ID,
+		DisplayName: q.Group.DisplayName,
+		LookupName:  q.Group.LookupName,
+	}, nil
+}
+
+func (c *apiClient) addGroup(ctx context.Context, g *group) (string, error) {
+	var m struct {
+		AddGroup struct {
+			Group struct {
+				ID string
+			}
+		} `graphql:"addGroup(displayName: $displayName, lookupName: $lookupName)"`
+	}
+
+	variables := map[string]interface{}{
+		"displayName": graphql.String(g.DisplayName),
+		"lookupName":  optionalString(g.LookupName),
+	}
+
+	if err := c.mutate(ctx, &m, variables); err != nil {
+		return "", err
+	}
+	return m.AddGroup.Group.ID, nil
+}
+
+func (c *apiClient) updateGroup(ctx context.Context, g *group) error {
+	var m struct {
+		UpdateGroup struct {
+			Group struct {
+				ID string
+			}
+		} `graphql:"updateGroup(input: $input)"`
+	}
+
+	variables := map[string]interface{}{
+		"input": UpdateGroupInput{
+			GroupID:     graphql.String(g.ID),
+			DisplayName: graphql.String(g.DisplayName),
+			LookupName:  graphql.String(g.LookupName),
+		},
+	}
+
+	return c.mutate(ctx, &m, variables)
+}
+
+func (c *apiClient) removeGroup(ctx context.Context, id string) error {
+	var m struct {
+		RemoveGroup struct {
+			Type string `graphql:"__typename"`
+		} `graphql:"removeGroup(groupId: $id)"`
+	}
+
+	variables := map[string]interface{}{
+		"id": graphql.String(id),
+	}
+
+	return c.mutate(ctx, &m, variables)
+}
+
+func (c *apiClient) getGroupRoles(ctx context.Context, groupID string) ([]groupRole, error) {
+	var q struct {
+		Group struct {
+			ID    string
+			Roles []struct {
+				Role         struct{ ID string }
+				SearchDomain struct{ Name string }
+			}
+		} `graphql:"group(groupId: $id)"`
+	}
+
+	variables := map[string]interface{}{
+		"id": graphql.String(groupID),
+	}
+
+	if err := c.query(ctx, &q, variables); err != nil {
+		return nil, err
+	}
+	if q.Group.ID == "" {
+		return nil, newNotFoundError("group %s", groupID)
+	}
+
+	roles := make([]groupRole, len(q.Group.Roles))
+	for i, role := range q.Group.Roles {
+		roles[i] = groupRole{
+			RoleID:     role.Role.ID,
+			Repository: role.SearchDomain.Name,
+		}
+	}
+	return roles, nil
+}
+
+func (c *apiClient) assignRoleToGroup(ctx context.Context, groupID string, role groupRole) error {
+	viewID, err := c.searchDomainID(ctx, role.Repository)
+	if err != nil {
+		return err
+	}
+
+	var m struct {
+		AssignRoleToGroup struct {
+			Type string `graphql:"__typename"`
+		} `graphql:"assignRoleToGroup(input: $input)"`
+	}
+
+	variables := map[string]interface{}{
+		"input": AssignRoleToGroupInput{
+			ViewID:  graphql.String(viewID),
+			GroupID: graphql.String(groupID),
+			RoleID:  graphql.String(role.RoleID),
+		},
+	}
+
+	return c.mutate(ctx, &m, variables)
+}
+
+func (c *apiClient) unassignRoleFromGroup(ctx context.Context, groupID string, role groupRole) error {
+	viewID, err := c.searchDomainID(ctx, role.Repository)
+	if err != nil {
+		return err
+	}
+
+	var m struct {
+		UnassignRoleFromGroup struct {
+			Type string `graphql:"__typename"`
+		} `graphql:"unassignRoleFromGroup(input: $input)"`
+	}
+
+	variables := map[string]interface{}{
+		"input": RemoveRoleFromGroupInput{
+			ViewID:  graphql.String(viewID),
+			GroupID: graphql.String(groupID),
+			RoleID:  graphql.String(role.RoleID),
+		},
+	}
+
+	return c.mutate(ctx, &m, variables)
+}
+
+// searchDomainID returns the internal ID of a repository or view, which role assignments are made against.
+func (c *apiClient) searchDomainID(ctx context.Context, name string) (string, error) {
+	var q struct {
+		SearchDomain struct {
+			ID string
+		} `graphql:"searchDomain(name: $name)"`
+	}
+
+	variables := map[string]interface{}{
+		"name": graphql.String(name),
+	}
+
+	if err := c.query(ctx, &q, variables); err != nil {
+		return "", err
+	}
+	if q.SearchDomain.ID == "" {
+		return "", newNotFoundError("repository or view %s", name)
+	}
+	return q.SearchDomain.ID, nil
+}
+
+// optionalString returns nil for an empty string, so the argument is sent as null rather than as an empty string.
+func optionalString(s string) *graphql.String {
+	if s == "" {
+		return nil
+	}
+	v := graphql.String(s)
+	return &v
+}
@@ -0,0 +1,115 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"context"
+
+	humio "github.com/humio/cli/api"
+	"github.com/shurcooL/graphql"
+)
+
+// ingestTokenData is an ingest token as returned by the GraphQL API, which has no parser if none is assigned.
+type ingestTokenData struct {
+	Name   string
+	Token  string
+	Parser *struct {
+		Name string
+	}
+}
+
+func (t ingestTokenData) ingestToken() humio.IngestToken {
+	token := humio.IngestToken{Name: t.Name, Token: t.Token}
+	if t.Parser != nil {
+		token.AssignedParser = t.Parser.Name
+	}
+	return token
+}
+
+// listIngestTokens returns the ingest tokens of the repository.
+func (c *apiClient) listIngestTokens(ctx context.Context, repository string) ([]humio.IngestToken, error) {
+	var q struct {
+		Repository struct {
+			IngestTokens []ingestTokenData
+		} `graphql:"repository(name: $repositoryName)"`
+	}
+
+	variables := map[string]interface{}{
+		"repositoryName": graphql.String(repository),
+	}
+
+	if err := c.query(ctx, &q, variables); err != nil {
+		return nil, err
+	}
+
+	tokens := make([]humio.IngestToken, len(q.Repository.IngestTokens))
+	for i, token := range q.Repository.IngestTokens {
+		tokens[i] = token.ingestToken()
+	}
+	return tokens, nil
+}
+
+// addIngestToken creates an ingest token assigned to the parser.
+func (c *apiClient) addIngestToken(ctx context.Context, repository, name, parser string) (*humio.IngestToken, error) {
+	var m struct {
+		AddIngestToken struct {
+			IngestToken ingestTokenData
+		} `graphql:"addIngestToken(repositoryName: $repositoryName, name: $tokenName, parser: $parserName)"`
+	}
+
+	variables := map[string]interface{}{
+		"tokenName":      graphql.String(name),
+		"repositoryName": graphql.String(repository),
+		"parserName":     graphql.String(parser),
+	}
+
+	if err := c.mutate(ctx, &m, variables); err != nil {
+		return nil, err
+	}
+	token := m.AddIngestToken.IngestToken.ingestToken()
+	return &token, nil
+}
+
+// assignIngestToken assigns the ingest token to the parser.
+func (c *apiClient) assignIngestToken(ctx context.Context, repository, name, parser string) error {
+	var m struct {
+		AssignIngestToken struct {
+			Type string `graphql:"__typename"`
+		} `graphql:"assignIngestToken(repositoryName: $repositoryName, tokenName: $tokenName, parserName: $parserName)"`
+	}
+
+	variables := map[string]interface{}{
+		"tokenName":      graphql.String(name),
+		"repositoryName": graphql.String(repository),
+		"parserName":     graphql.String(parser),
+	}
+
+	return c.mutate(ctx, &m, variables)
+}
+
+func (c *apiClient) removeIngestToken(ctx context.Context, repository, name string) error {
+	var m struct {
+		RemoveIngestToken struct {
+			Type string `graphql:"__typename"`
+		} `graphql:"removeIngestToken(repositoryName: $repositoryName, name: $tokenName)"`
+	}
+
+	variables := map[string]interface{}{
+		"tokenName":      graphql.String(name),
+		"repositoryName": graphql.String(repository),
+	}
+
+	return c.mutate(ctx, &m, variables)
+}
@@ -0,0 +1,86 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	humio "github.com/humio/cli/api"
+)
+
+func notifiersPath(repository string) string {
+	return fmt.Sprintf("/api/v1/repositories/%s/alertnotifiers", url.PathEscape(repository))
+}
+
+// listNotifiers returns the notifiers in the repository.
+func (c *apiClient) listNotifiers(ctx context.Context, repository string) ([]humio.Notifier, error) {
+	var notifiers []humio.Notifier
+	if err := c.restJSON(ctx, http.MethodGet, notifiersPath(repository), nil, &notifiers); err != nil {
+		return nil, err
+	}
+	return notifiers, nil
+}
+
+// notifierID returns the ID of the notifier with the given name. The REST API addresses notifiers by ID, while the
+// provider identifies them by name.
+func (c *apiClient) notifierID(ctx context.Context, repository, name string) (string, error) {
+	notifiers, err := c.listNotifiers(ctx, repository)
+	if err != nil {
+		return "", err
+	}
+	for _, notifier := range notifiers {
+		if notifier.Name == name {
+			return notifier.ID, nil
+		}
+	}
+	return "", newNotFoundError("notifier %s in repository %s", name, repository)
+}
+
+// createNotifier creates the notifier, failing if the repository already has a notifier with the same name.
+func (c *apiClient) createNotifier(ctx context.Context, repository string, notifier *humio.Notifier) error {
+	_, err := c.notifierID(ctx, repository, notifier.Name)
+	if err == nil {
+		return fmt.Errorf("notifier with name %s already exists", notifier.Name)
+	}
+	if !isNotFoundError(err) {
+		return err
+	}
+	body := *notifier
+	body.ID = ""
+	return c.restJSON(ctx, http.MethodPost, notifiersPath(repository)+"/", &body, nil)
+}
+
+// updateNotifier replaces the notifier with the same name as notifier.
+func (c *apiClient) updateNotifier(ctx context.Context, repository string, notifier *humio.Notifier) error {
+	id, err := c.notifierID(ctx, repository, notifier.Name)
+	if err != nil {
+		return err
+	}
+	body := *notifier
+	body.ID = id
+	return c.restJSON(ctx, http.MethodPut, notifiersPath(repository)+"/"+url.PathEscape(id), &body, nil)
+}
+
+// deleteNotifier deletes the notifier with the given name.
+func (c *apiClient) deleteNotifier(ctx context.Context, repository, name string) error {
+	id, err := c.notifierID(ctx, repository, name)
+	if err != nil {
+		return err
+	}
+	return c.restJSON(ctx, http.MethodDelete, notifiersPath(repository)+"/"+url.PathEscape(id), nil, nil)
+}
@@ -0,0 +1,170 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+
+	humio "github.com/humio/cli/api"
+	"github.com/shurcooL/graphql"
+)
+
+// installedPackage is a package installed in a repository, with the objects it installed, such as "parsers/accesslog"
+// or "files/hosts.csv".
+type installedPackage struct {
+	// Name is the unversioned package ID, e.g. myorg/mypackage.
+	Name    string
+	Version string
+	Objects []string
+}
+
+// packageTemplateKinds maps the fields of a package listing the templates of each kind of object to the directory
+// holding them in the package, which is used as the prefix of the installed objects.
+var packageTemplateKinds = []struct {
+	field     string
+	directory string
+}{
+	{"actionTemplates", "actions"},
+	{"alertTemplates", "alerts"},
+	{"dashboardTemplates", "dashboards"},
+	{"lookupFileTemplates", "files"},
+	{"parserTemplates", "parsers"},
+	{"savedQueryTemplates", "queries"},
+	{"scheduledSearchTemplates", "scheduled-searches"},
+}
+
+const installedPackagesQuery = `query($repository: String!) {
+	searchDomain(name: $repository) {
+		installedPackages {
+			id
+			package {
+				actionTemplates { name }
+				alertTemplates { name }
+				dashboardTemplates { name }
+				lookupFileTemplates { name }
+				parserTemplates { name }
+				savedQueryTemplates { name }
+				scheduledSearchTemplates { name }
+			}
+		}
+	}
+}`
+
+// listInstalledPackages returns the packages installed in the repository. The GraphQL client of the Humio API client
+// only lists their IDs, so the objects they installed are queried with graphQL.
+func (c *apiClient) listInstalledPackages(ctx context.Context, repository string) ([]installedPackage, error) {
+	var q struct {
+		SearchDomain struct {
+			InstalledPackages []struct {
+				ID      string
+				Package map[string][]struct {
+					Name string
+				}
+			}
+		}
+	}
+
+	variables := map[string]interface{}{
+		"repository": repository,
+	}
+
+	if err := c.graphQL(ctx, installedPackagesQuery, variables, &q); err != nil {
+		return nil, err
+	}
+
+	packages := make([]installedPackage, len(q.SearchDomain.InstalledPackages))
+	for i, p := range q.SearchDomain.InstalledPackages {
+		name, version := splitPackageID(p.ID)
+		objects := []string{}
+		for _, kind := range packageTemplateKinds {
+			for _, template := range p.Package[kind.field] {
+				objects = append(objects, kind.directory+"/"+template.Name)
+			}
+		}
+		sort.Strings(objects)
+		packages[i] = installedPackage{
+			Name:    name,
+			Version: version,
+			Objects: objects,
+		}
+	}
+	return packages, nil
+}
+
+// getInstalledPackage returns the installed package with the given name.
+func (c *apiClient) getInstalledPackage(ctx context.Context, repository, name string) (*installedPackage, error) {
+	packages, err := c.listInstalledPackages(ctx, repository)
+	if err != nil {
+		return nil, err
+	}
+	for i := range packages {
+		if packages[i].Name == name {
+			return &packages[i], nil
+		}
+	}
+	return nil, newNotFoundError("package %s in repository %s", name, repository)
+}
+
+// installPackage installs the package in the zip file archive in the repository, upgrading the package if it is
+// already installed. Errors in the package are returned in the report rather than as an error.
+func (c *apiClient) installPackage(ctx context.Context, repository string, archive []byte) (*humio.ValidationResponse, error) {
+	resp, err := c.do(
+		ctx,
+		http.MethodPost,
+		"/api/v1/packages/install?view="+url.QueryEscape(repository),
+		bytes.NewReader(archive),
+		humio.ZIPContentType,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("non-200 OK status code: %v body: %q", resp.Status, b)
+	}
+	var report humio.ValidationResponse
+	if err := json.Unmarshal(b, &report); err != nil {
+		return nil, fmt.Errorf("could not decode response: %s", err)
+	}
+	return &report, nil
+}
+
+// uninstallPackage uninstalls the package with the given unversioned ID, such as myorg/mypackage, from the repository.
+func (c *apiClient) uninstallPackage(ctx context.Context, repository, name string) error {
+	var m struct {
+		UninstallPackage struct {
+			Type string `graphql:"__typename"`
+		} `graphql:"uninstallPackage(packageId: $packageId, viewName: $viewName)"`
+	}
+
+	variables := map[string]interface{}{
+		"packageId": humio.UnversionedPackageSpecifier(name),
+		"viewName":  graphql.String(repository),
+	}
+
+	return c.mutate(ctx, &m, variables)
+}
@@ -0,0 +1,126 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"context"
+	"strings"
+
+	humio "github.com/humio/cli/api"
+	"github.com/shurcooL/graphql"
+)
+
+// listParsers returns the parsers in the repository, including the built-in ones.
+func (c *apiClient) listParsers(ctx context.Context, repository string) ([]humio.ParserListItem, error) {
+	var q struct {
+		Repository struct {
+			Parsers []humio.ParserListItem
+		} `graphql:"repository(name: $repositoryName)"`
+	}
+
+	variables := map[string]interface{}{
+		"repositoryName": graphql.String(repository),
+	}
+
+	if err := c.query(ctx, &q, variables); err != nil {
+		return nil, err
+	}
+	return q.Repository.Parsers, nil
+}
+
+// getParser returns the parser with the given name. A parser without a name is returned if it does not exist.
+func (c *apiClient) getParser(ctx context.Context, repository, name string) (*humio.Parser, error) {
+	var q struct {
+		Repository struct {
+			Parser struct {
+				Name       string
+				SourceCode string
+				TestData   []string
+				TagFields  []string
+			} `graphql:"parser(name: $parserName)"`
+		} `graphql:"repository(name: $repositoryName)"`
+	}
+
+	variables := map[string]interface{}{
+		"parserName":     graphql.String(name),
+		"repositoryName": graphql.String(repository),
+	}
+
+	if err := c.query(ctx, &q, variables); err != nil {
+		return nil, err
+	}
+
+	parser := q.Repository.Parser
+	tests := make([]humio.ParserTestCase, len(parser.TestData))
+	for i, input := range parser.TestData {
+		tests[i] = humio.ParserTestCase{Input: input, Output: map[string]string{}}
+	}
+	return &humio.Parser{
+		Name:      parser.Name,
+		Tests:     tests,
+		Script:    parser.SourceCode,
+		TagFields: parser.TagFields,
+	}, nil
+}
+
+// addParser creates the parser, replacing an existing parser with the same name if force is true.
+func (c *apiClient) addParser(ctx context.Context, repository string, parser *humio.Parser, force bool) error {
+	var m struct {
+		CreateParser struct {
+			Type string `graphql:"__typename"`
+		} `graphql:"createParser(input: { name: $name, repositoryName: $repositoryName, testData: $testData, tagFields: $tagFields, sourceCode: $sourceCode, force: $force})"`
+	}
+
+	tagFields := make([]graphql.String, len(parser.TagFields))
+	for i, field := range parser.TagFields {
+		tagFields[i] = graphql.String(field)
+	}
+
+	// The test data holds the inputs of the test cases followed by the lines of the example.
+	testData := make([]graphql.String, 0, len(parser.Tests))
+	for _, test := range parser.Tests {
+		testData = append(testData, graphql.String(test.Input))
+	}
+	lines := strings.Split(parser.Example, "\n")
+	for _, line := range lines[:len(lines)-1] {
+		testData = append(testData, graphql.String(line))
+	}
+
+	variables := map[string]interface{}{
+		"name":           graphql.String(parser.Name),
+		"sourceCode":     graphql.String(parser.Script),
+		"repositoryName": graphql.String(repository),
+		"testData":       testData,
+		"tagFields":      tagFields,
+		"force":          graphql.Boolean(force),
+	}
+
+	return c.mutate(ctx, &m, variables)
+}
+
+func (c *apiClient) removeParser(ctx context.Context, repository, name string) error {
+	var m struct {
+		RemoveParser struct {
+			Type string `graphql:"__typename"`
+		} `graphql:"removeParser(input: { name: $name, repositoryName: $repositoryName })"`
+	}
+
+	variables := map[string]interface{}{
+		"repositoryName": graphql.String(repository),
+		"name":           graphql.String(name),
+	}
+
+	return c.mutate(ctx, &m, variables)
+}
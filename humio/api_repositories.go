@@ -0,0 +1,169 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"context"
+	"fmt"
+
+	humio "github.com/humio/cli/api"
+	"github.com/shurcooL/graphql"
+)
+
+// listRepositories returns the names of the repositories in the cluster.
+func (c *apiClient) listRepositories(ctx context.Context) ([]humio.RepoListItem, error) {
+	var q struct {
+		Repositories []humio.RepoListItem `graphql:"repositories"`
+	}
+
+	if err := c.query(ctx, &q, nil); err != nil {
+		return nil, err
+	}
+	return q.Repositories, nil
+}
+
+// getRepository returns the repository with the given name. A repository without a name is returned if it does not
+// exist.
+func (c *apiClient) getRepository(ctx context.Context, name string) (humio.Repository, error) {
+	var q struct {
+		Repository humio.Repository `graphql:"repository(name: $name)"`
+	}
+
+	variables := map[string]interface{}{
+		"name": graphql.String(name),
+	}
+
+	err := c.query(ctx, &q, variables)
+	return q.Repository, err
+}
+
+func (c *apiClient) createRepository(ctx context.Context, name string) error {
+	var m struct {
+		CreateRepository struct {
+			Repository struct {
+				Name string
+			}
+		} `graphql:"createRepository(name: $name)"`
+	}
+
+	variables := map[string]interface{}{
+		"name": graphql.String(name),
+	}
+
+	if err := c.mutate(ctx, &m, variables); err != nil {
+		// The GraphQL error is vague if the repository already exists, so add a hint.
+		return fmt.Errorf("%s. Does the repository already exist?", err)
+	}
+	return nil
+}
+
+// deleteRepository deletes the repository, unless it holds data and allowDataDeletion is false.
+func (c *apiClient) deleteRepository(ctx context.Context, name, reason string, allowDataDeletion bool) error {
+	existing, err := c.getRepository(ctx, name)
+	if err != nil {
+		return err
+	}
+	if !allowDataDeletion && existing.SpaceUsed > 0 {
+		return fmt.Errorf("repository contains data and data deletion not allowed")
+	}
+
+	var m struct {
+		DeleteSearchDomain struct {
+			Type string `graphql:"__typename"`
+		} `graphql:"deleteSearchDomain(name: $name, deleteMessage: $reason)"`
+	}
+
+	variables := map[string]interface{}{
+		"name":   graphql.String(name),
+		"reason": graphql.String(reason),
+	}
+
+	return c.mutate(ctx, &m, variables)
+}
+
+// updateSearchDomainDescription sets the description of a repository or view.
+func (c *apiClient) updateSearchDomainDescription(ctx context.Context, name, description string) error {
+	var m struct {
+		UpdateDescription struct {
+			Type string `graphql:"__typename"`
+		} `graphql:"updateDescriptionForSearchDomain(name: $name, newDescription: $description)"`
+	}
+
+	variables := map[string]interface{}{
+		"name":        graphql.String(name),
+		"description": graphql.String(description),
+	}
+
+	return c.mutate(ctx, &m, variables)
+}
+
+// Arguments of the updateRetention mutation, one for each kind of retention.
+const (
+	timeBasedRetention        = "timeBasedRetention"
+	ingestSizeBasedRetention  = "ingestSizeBasedRetention"
+	storageSizeBasedRetention = "storageSizeBasedRetention"
+)
+
+// updateRetention sets one kind of retention of the repository, where 0 removes it. Lowering the retention or adding
+// one deletes data, which is refused unless allowDataDeletion is true or the repository is empty.
+func (c *apiClient) updateRetention(ctx context.Context, name, kind string, value float64, allowDataDeletion bool) error {
+	existing, err := c.getRepository(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	variables := map[string]interface{}{
+		"name":  graphql.String(name),
+		"value": (*graphql.Float)(nil),
+	}
+	if value > 0 {
+		current := map[string]float64{
+			timeBasedRetention:        existing.RetentionDays,
+			ingestSizeBasedRetention:  existing.IngestRetentionSizeGB,
+			storageSizeBasedRetention: existing.StorageRetentionSizeGB,
+		}[kind]
+		if (value < current || current == 0) && !allowDataDeletion && existing.SpaceUsed > 0 {
+			return fmt.Errorf("repository contains data and data deletion not allowed")
+		}
+		variables["value"] = graphql.Float(value)
+	}
+
+	// The argument name is part of the query, which the GraphQL client builds from struct tags, so each kind of
+	// retention needs its own type.
+	switch kind {
+	case timeBasedRetention:
+		var m struct {
+			UpdateRetention struct {
+				Type string `graphql:"__typename"`
+			} `graphql:"updateRetention(repositoryName: $name, timeBasedRetention: $value)"`
+		}
+		return c.mutate(ctx, &m, variables)
+	case ingestSizeBasedRetention:
+		var m struct {
+			UpdateRetention struct {
+				Type string `graphql:"__typename"`
+			} `graphql:"updateRetention(repositoryName: $name, ingestSizeBasedRetention: $value)"`
+		}
+		return c.mutate(ctx, &m, variables)
+	case storageSizeBasedRetention:
+		var m struct {
+			UpdateRetention struct {
+				Type string `graphql:"__typename"`
+			} `graphql:"updateRetention(repositoryName: $name, storageSizeBasedRetention: $value)"`
+		}
+		return c.mutate(ctx, &m, variables)
+	}
+	return fmt.Errorf("unknown retention %s", kind)
+}
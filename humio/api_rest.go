@@ -0,0 +1,62 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	humio "github.com/humio/cli/api"
+)
+
+// restJSON sends a request to the Humio REST API with ctx, encoding body as JSON unless it is nil, and decodes the JSON
+// response into v unless it is nil. The REST methods of the Humio API client exit the process when a request fails or
+// the response is not JSON, such as the HTML page of a proxy returning 502 Bad Gateway, so REST requests are sent with
+// this instead, which returns an error that retry can recognise as transient.
+func (c *apiClient) restJSON(ctx context.Context, method, path string, body interface{}, v interface{}) error {
+	var reqBody io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewReader(b)
+	}
+
+	resp, err := c.do(ctx, method, path, reqBody, humio.JSONContentType)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("non-200 OK status code: %v body: %q", resp.Status, b)
+	}
+	if v == nil {
+		return nil
+	}
+	if err := json.Unmarshal(b, v); err != nil {
+		return fmt.Errorf("could not decode response to %s %s: %s", method, path, err)
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"context"
+	"github.com/shurcooL/graphql"
+)
+
+// role is a Humio role. View permissions apply to the repositories and views the role is assigned on, while
+// organization and system permissions apply cluster-wide.
+type role struct {
+	ID                      string
+	DisplayName             string
+	ViewPermissions         []string
+	OrganizationPermissions []string
+	SystemPermissions       []string
+}
+
+// AddRoleInput is the GraphQL input type for creating a role. The type name is used by the GraphQL client when
+// declaring query variables, so it must match the name in the Humio schema.
+type AddRoleInput struct {
+	DisplayName             graphql.String `json:"displayName"`
+	ViewPermissions         []string       `json:"viewPermissions"`
+	OrganizationPermissions []string       `json:"organizationPermissions"`
+	SystemPermissions       []string       `json:"systemPermissions"`
+}
+
+// UpdateRoleInput is the GraphQL input type for updating a role.
+type UpdateRoleInput struct {
+	RoleID                  graphql.String `json:"roleId"`
+	DisplayName             graphql.String `json:"displayName"`
+	ViewPermissions         []string       `json:"viewPermissions"`
+	OrganizationPermissions []string       `json:"organizationPermissions"`
+	SystemPermissions       []string       `json:"systemPermissions"`
+}
+
+// listRoles returns every role in the cluster.
+func (c *apiClient) listRoles(ctx context.Context) ([]role, error) {
+	var q struct {
+		Roles []struct {
+			ID                      string
+			DisplayName             string
+			ViewPermissions         []string
+			OrganizationPermissions []string
+			SystemPermissions       []string
+		}
+	}
+
+	if err := c.query(ctx, &q, nil); err != nil {
+		return nil, err
+	}
+
+	roles := make([]role, len(q.Roles))
+	for i, r := range q.Roles {
+		roles[i] = role{
+			ID:                      r.ID,
+			DisplayName:             r.DisplayName,
+			ViewPermissions:         r.ViewPermissions,
+			OrganizationPermissions: r.OrganizationPermissions,
+			SystemPermissions:       r.SystemPermissions,
+		}
+	}
+	return roles, nil
+}
+
+// getRole looks the role up in the list of all roles, as not every Humio version can query a single role by ID.
+func (c *apiClient) getRole(ctx context.Context, id string) (*role, error) {
+	roles, err := c.listRoles(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range roles {
+		if roles[i].ID == id {
+			return &roles[i], nil
+		}
+	}
+	return nil, newNotFoundError("role %s", id)
+}
+
+func (c *apiClient) createRole(ctx context.Context, r *role) (string, error) {
+	var m struct {
+		CreateRole struct {
+			Role struct {
+				ID string
+			}
+		} `graphql:"createRole(input: $input)"`
+	}
+
+	variables := map[string]interface{}{
+		"input": AddRoleInput{
+			DisplayName:             graphql.String(r.DisplayName),
+			ViewPermissions:         nonNilStrings(r.ViewPermissions),
+			OrganizationPermissions: nonNilStrings(r.OrganizationPermissions),
+			SystemPermissions:       nonNilStrings(r.SystemPermissions),
+		},
+	}
+
+	if err := c.mutate(ctx, &m, variables); err != nil {
+		return "", err
+	}
+	return m.CreateRole.Role.ID, nil
+}
+
+func (c *apiClient) updateRole(ctx context.Context, r *role) error {
+	var m struct {
+		UpdateRole struct {
+			Role struct {
+				ID string
+			}
+		} `graphql:"updateRole(input: $input)"`
+	}
+
+	variables := map[string]interface{}{
+		"input": UpdateRoleInput{
+			RoleID:                  graphql.String(r.ID),
+			DisplayName:             graphql.String(r.DisplayName),
+			ViewPermissions:         nonNilStrings(r.ViewPermissions),
+			OrganizationPermissions: nonNilStrings(r.OrganizationPermissions),
+			SystemPermissions:       nonNilStrings(r.SystemPermissions),
+		},
+	}
+
+	return c.mutate(ctx, &m, variables)
+}
+
+func (c *apiClient) removeRole(ctx context.Context, id string) error {
+	var m struct {
+		RemoveRole struct {
+			Type string `graphql:"__typename"`
+		} `graphql:"removeRole(roleId: $id)"`
+	}
+
+	variables := map[string]interface{}{
+		"id": graphql.String(id),
+	}
+
+	return c.mutate(ctx, &m, variables)
+}
+
+// nonNilStrings makes sure an empty list is sent as [] rather than null, which Humio rejects for permission lists.
+func nonNilStrings(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
@@ -0,0 +1,150 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/shurcooL/graphql"
+
+	humio "github.com/humio/cli/api"
+)
+
+// savedQuery is a Humio saved query. Options holds the JSON encoded settings of the widget used to show the results.
+type savedQuery struct {
+	ID         string
+	Name       string
+	Query      humio.HumioQuery
+	WidgetType string
+	Options    string
+}
+
+// savedQueryInput holds the fields of the GraphQL input types for creating and updating a saved query. The ID is left
+// out when creating one.
+type savedQueryInput struct {
+	ID          string          `json:"id,omitempty"`
+	ViewName    string          `json:"viewName"`
+	Name        string          `json:"name"`
+	QueryString string          `json:"queryString"`
+	Start       string          `json:"start"`
+	End         string          `json:"end"`
+	IsLive      bool            `json:"isLive"`
+	WidgetType  *graphql.String `json:"widgetType"`
+	Options     string          `json:"options"`
+}
+
+func newSavedQueryInput(repository string, s *savedQuery) savedQueryInput {
+	return savedQueryInput{
+		ID:          s.ID,
+		ViewName:    repository,
+		Name:        s.Name,
+		QueryString: s.Query.QueryString,
+		Start:       s.Query.Start,
+		End:         s.Query.End,
+		IsLive:      s.Query.IsLive,
+		WidgetType:  optionalString(s.WidgetType),
+		Options:     s.Options,
+	}
+}
+
+const savedQueriesQuery = `query($repository: String!) {
+	searchDomain(name: $repository) {
+		savedQueries {
+			id
+			name
+			query { queryString start end isLive }
+			widgetType
+			options
+		}
+	}
+}`
+
+// listSavedQueries returns the saved queries in the repository. The options of a saved query are a JSON object, so the
+// query is sent with graphQL rather than the GraphQL client.
+func (c *apiClient) listSavedQueries(ctx context.Context, repository string) ([]savedQuery, error) {
+	var q struct {
+		SearchDomain struct {
+			SavedQueries []struct {
+				ID         string
+				Name       string
+				Query      humio.HumioQuery
+				WidgetType string
+				Options    json.RawMessage
+			}
+		}
+	}
+
+	variables := map[string]interface{}{
+		"repository": repository,
+	}
+
+	if err := c.graphQL(ctx, savedQueriesQuery, variables, &q); err != nil {
+		return nil, err
+	}
+
+	savedQueries := make([]savedQuery, len(q.SearchDomain.SavedQueries))
+	for i, s := range q.SearchDomain.SavedQueries {
+		options := string(s.Options)
+		if options == "" || options == "null" {
+			options = "{}"
+		}
+		savedQueries[i] = savedQuery{
+			ID:         s.ID,
+			Name:       s.Name,
+			Query:      s.Query,
+			WidgetType: s.WidgetType,
+			Options:    options,
+		}
+	}
+	return savedQueries, nil
+}
+
+// getSavedQuery returns the saved query with the given name.
+func (c *apiClient) getSavedQuery(ctx context.Context, repository, name string) (*savedQuery, error) {
+	savedQueries, err := c.listSavedQueries(ctx, repository)
+	if err != nil {
+		return nil, err
+	}
+	for i := range savedQueries {
+		if savedQueries[i].Name == name {
+			return &savedQueries[i], nil
+		}
+	}
+	return nil, newNotFoundError("saved query %s in repository %s", name, repository)
+}
+
+func (c *apiClient) createSavedQuery(ctx context.Context, repository string, s *savedQuery) error {
+	input := newSavedQueryInput(repository, s)
+	input.ID = ""
+
+	var m map[string]interface{}
+	return c.graphQL(ctx, "mutation($input: CreateSavedQueryInput!) { createSavedQuery(input: $input) { __typename } }", map[string]interface{}{"input": input}, &m)
+}
+
+func (c *apiClient) updateSavedQuery(ctx context.Context, repository string, s *savedQuery) error {
+	var m map[string]interface{}
+	return c.graphQL(ctx, "mutation($input: UpdateSavedQueryInput!) { updateSavedQuery(input: $input) { __typename } }", map[string]interface{}{"input": newSavedQueryInput(repository, s)}, &m)
+}
+
+func (c *apiClient) deleteSavedQuery(ctx context.Context, repository, id string) error {
+	input := map[string]interface{}{
+		"id":       id,
+		"viewName": repository,
+	}
+
+	var m map[string]interface{}
+	return c.graphQL(ctx, "mutation($input: DeleteSavedQueryInput!) { deleteSavedQuery(input: $input) { __typename } }", map[string]interface{}{"input": input}, &m)
+}
@@ -0,0 +1,178 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"context"
+)
+
+// scheduledSearch is a query that Humio runs over a fixed time window on a cron schedule, triggering its actions when
+// the query returns results.
+type scheduledSearch struct {
+	ID            string
+	Name          string
+	Description   string
+	QueryString   string
+	Start         string
+	End           string
+	Schedule      string
+	TimeZone      string
+	BackfillLimit int
+	Enabled       bool
+	Actions       []string
+	Labels        []string
+}
+
+// scheduledSearchInput holds the fields of the GraphQL input types for creating and updating a scheduled search. The
+// ID is left out when creating one.
+type scheduledSearchInput struct {
+	ViewName      string   `json:"viewName"`
+	ID            string   `json:"id,omitempty"`
+	Name          string   `json:"name"`
+	Description   string   `json:"description"`
+	QueryString   string   `json:"queryString"`
+	QueryStart    string   `json:"queryStart"`
+	QueryEnd      string   `json:"queryEnd"`
+	Schedule      string   `json:"schedule"`
+	TimeZone      string   `json:"timeZone"`
+	BackfillLimit int      `json:"backfillLimit"`
+	Enabled       bool     `json:"enabled"`
+	Actions       []string `json:"actions"`
+	Labels        []string `json:"labels"`
+}
+
+func newScheduledSearchInput(repository string, s *scheduledSearch) scheduledSearchInput {
+	return scheduledSearchInput{
+		ViewName:      repository,
+		ID:            s.ID,
+		Name:          s.Name,
+		Description:   s.Description,
+		QueryString:   s.QueryString,
+		QueryStart:    s.Start,
+		QueryEnd:      s.End,
+		Schedule:      s.Schedule,
+		TimeZone:      s.TimeZone,
+		BackfillLimit: s.BackfillLimit,
+		Enabled:       s.Enabled,
+		Actions:       nonNilStrings(s.Actions),
+		Labels:        nonNilStrings(s.Labels),
+	}
+}
+
+const scheduledSearchesQuery = `query($repository: String!) {
+	searchDomain(name: $repository) {
+		scheduledSearches {
+			id
+			name
+			description
+			queryString
+			start
+			end
+			schedule
+			timeZone
+			backfillLimit
+			enabled
+			actions
+			labels
+		}
+	}
+}`
+
+// listScheduledSearches returns the scheduled searches in the repository. Scheduled searches are managed with graphQL,
+// so their requests are sent with the context of the resource operation.
+func (c *apiClient) listScheduledSearches(ctx context.Context, repository string) ([]scheduledSearch, error) {
+	var q struct {
+		SearchDomain struct {
+			ScheduledSearches []struct {
+				ID            string
+				Name          string
+				Description   string
+				QueryString   string
+				Start         string
+				End           string
+				Schedule      string
+				TimeZone      string
+				BackfillLimit int
+				Enabled       bool
+				Actions       []string
+				Labels        []string
+			}
+		}
+	}
+
+	variables := map[string]interface{}{
+		"repository": repository,
+	}
+
+	if err := c.graphQL(ctx, scheduledSearchesQuery, variables, &q); err != nil {
+		return nil, err
+	}
+
+	searches := make([]scheduledSearch, len(q.SearchDomain.ScheduledSearches))
+	for i, s := range q.SearchDomain.ScheduledSearches {
+		searches[i] = scheduledSearch{
+			ID:            s.ID,
+			Name:          s.Name,
+			Description:   s.Description,
+			QueryString:   s.QueryString,
+			Start:         s.Start,
+			End:           s.End,
+			Schedule:      s.Schedule,
+			TimeZone:      s.TimeZone,
+			BackfillLimit: s.BackfillLimit,
+			Enabled:       s.Enabled,
+			Actions:       s.Actions,
+			Labels:        s.Labels,
+		}
+	}
+	return searches, nil
+}
+
+func (c *apiClient) getScheduledSearch(ctx context.Context, repository, name string) (*scheduledSearch, error) {
+	searches, err := c.listScheduledSearches(ctx, repository)
+	if err != nil {
+		return nil, err
+	}
+	for i := range searches {
+		if searches[i].Name == name {
+			return &searches[i], nil
+		}
+	}
+	return nil, newNotFoundError("scheduled search %s in repository %s", name, repository)
+}
+
+func (c *apiClient) createScheduledSearch(ctx context.Context, repository string, s *scheduledSearch) error {
+	input := newScheduledSearchInput(repository, s)
+	input.ID = ""
+
+	var m map[string]interface{}
+	return c.graphQL(ctx, "mutation($input: CreateScheduledSearch!) { createScheduledSearch(input: $input) { id } }", map[string]interface{}{"input": input}, &m)
+}
+
+func (c *apiClient) updateScheduledSearch(ctx context.Context, repository string, s *scheduledSearch) error {
+	var m map[string]interface{}
+	return c.graphQL(ctx, "mutation($input: UpdateScheduledSearch!) { updateScheduledSearch(input: $input) { id } }", map[string]interface{}{"input": newScheduledSearchInput(repository, s)}, &m)
+}
+
+func (c *apiClient) deleteScheduledSearch(ctx context.Context, repository, id string) error {
+	var m map[string]interface{}
+
+	variables := map[string]interface{}{
+		"repository": repository,
+		"id":         id,
+	}
+
+	return c.graphQL(ctx, "mutation($repository: String!, $id: String!) { deleteScheduledSearch(viewName: $repository, id: $id) }", variables, &m)
+}
@@ -0,0 +1,88 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// apiTokenFromResourceData returns the API token from whichever of api_token, api_token_file and api_token_command is
+// set, which is enforced to be at most one of them by the provider schema. If none of them is set, the token is taken
+// from HUMIO_API_TOKEN, or read from the file given by HUMIO_API_TOKEN_FILE, so the environment never overrides the
+// configuration. Without any of them the token is empty, which works for clusters without authentication.
+func apiTokenFromResourceData(ctx context.Context, r *schema.ResourceData) (string, error) {
+	if token, ok := r.GetOk("api_token"); ok {
+		return token.(string), nil
+	}
+	if path, ok := r.GetOk("api_token_file"); ok {
+		return apiTokenFromFile(path.(string))
+	}
+	if command, ok := r.GetOk("api_token_command"); ok {
+		var args []string
+		for _, arg := range command.([]interface{}) {
+			args = append(args, arg.(string))
+		}
+		return apiTokenFromCommand(ctx, args)
+	}
+	if token := os.Getenv("HUMIO_API_TOKEN"); token != "" {
+		return token, nil
+	}
+	if path := os.Getenv("HUMIO_API_TOKEN_FILE"); path != "" {
+		return apiTokenFromFile(path)
+	}
+	return "", nil
+}
+
+// apiTokenFromFile reads the API token from a file, ignoring surrounding whitespace such as a trailing newline.
+func apiTokenFromFile(path string) (string, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("could not read api_token_file: %w", err)
+	}
+	token := strings.TrimSpace(string(content))
+	if token == "" {
+		return "", fmt.Errorf("api_token_file %s is empty", path)
+	}
+	return token, nil
+}
+
+// apiTokenFromCommand runs the command, given as the program followed by its arguments, and returns its output as the
+// API token.
+func apiTokenFromCommand(ctx context.Context, command []string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, command[0], command[1:]...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			return "", fmt.Errorf("api_token_command %s failed: %w", command[0], err)
+		}
+		return "", fmt.Errorf("api_token_command %s failed: %w: %s", command[0], err, msg)
+	}
+	token := strings.TrimSpace(stdout.String())
+	if token == "" {
+		return "", fmt.Errorf("api_token_command %s did not output a token", command[0])
+	}
+	return token, nil
+}
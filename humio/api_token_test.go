@@ -0,0 +1,157 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAPITokenFromFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "humio-token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "token")
+	if err := ioutil.WriteFile(path, []byte("secret\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	token, err := apiTokenFromFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token != "secret" {
+		t.Errorf("unexpected token %q", token)
+	}
+
+	empty := filepath.Join(dir, "empty")
+	if err := ioutil.WriteFile(empty, []byte("\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := apiTokenFromFile(empty); err == nil {
+		t.Error("expected an error for an empty token file")
+	}
+	if _, err := apiTokenFromFile(filepath.Join(dir, "missing")); err == nil {
+		t.Error("expected an error for a missing token file")
+	}
+}
+
+func TestAPITokenFromCommand(t *testing.T) {
+	token, err := apiTokenFromCommand(context.Background(), []string{"sh", "-c", "echo secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token != "secret" {
+		t.Errorf("unexpected token %q", token)
+	}
+
+	_, err = apiTokenFromCommand(context.Background(), []string{"sh", "-c", "echo permission denied >&2; exit 1"})
+	if err == nil || !strings.Contains(err.Error(), "permission denied") {
+		t.Errorf("expected an error with the output of the command, got %v", err)
+	}
+	if _, err := apiTokenFromCommand(context.Background(), []string{"true"}); err == nil {
+		t.Error("expected an error for a command without output")
+	}
+}
+
+func TestProviderAPITokenCommand(t *testing.T) {
+	// The configuration takes precedence over the environment.
+	defer setTestEnv("HUMIO_API_TOKEN", "from-environment")()
+
+	p := Provider()
+	diags := p.Configure(context.Background(), terraform.NewResourceConfigRaw(map[string]interface{}{
+		"api_token_command":  []interface{}{"sh", "-c", "echo secret"},
+		"skip_version_check": true,
+	}))
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	if token := clientToken(p.Meta().(*apiClient)); token != "secret" {
+		t.Errorf("unexpected token %q", token)
+	}
+}
+
+func TestProviderAPITokenFromEnvironment(t *testing.T) {
+	dir, err := ioutil.TempDir("", "humio-token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "token")
+	if err := ioutil.WriteFile(path, []byte("from-file\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		config    map[string]interface{}
+		token     string
+		tokenFile string
+		want      string
+	}{
+		{name: "token", token: "from-environment", tokenFile: path, want: "from-environment"},
+		{name: "token file", tokenFile: path, want: "from-file"},
+		{name: "configured token file", config: map[string]interface{}{"api_token_file": path}, token: "from-environment", want: "from-file"},
+		{name: "none", want: ""},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			defer setTestEnv("HUMIO_API_TOKEN", test.token)()
+			defer setTestEnv("HUMIO_API_TOKEN_FILE", test.tokenFile)()
+
+			config := map[string]interface{}{"skip_version_check": true}
+			for key, value := range test.config {
+				config[key] = value
+			}
+			p := Provider()
+			if diags := p.Configure(context.Background(), terraform.NewResourceConfigRaw(config)); diags.HasError() {
+				t.Fatal(diags)
+			}
+			if token := clientToken(p.Meta().(*apiClient)); token != test.want {
+				t.Errorf("got token %q, want %q", token, test.want)
+			}
+		})
+	}
+}
+
+func TestProviderAPITokenConflicts(t *testing.T) {
+	diags := Provider().Validate(terraform.NewResourceConfigRaw(map[string]interface{}{
+		"api_token":      "secret",
+		"api_token_file": "/run/secrets/humio",
+	}))
+	if !diags.HasError() {
+		t.Error("expected api_token and api_token_file to conflict")
+	}
+	diags = Provider().Validate(terraform.NewResourceConfigRaw(map[string]interface{}{
+		"api_token_file":    "/run/secrets/humio",
+		"api_token_command": []interface{}{"cat", "/run/secrets/humio"},
+	}))
+	if !diags.HasError() {
+		t.Error("expected api_token_file and api_token_command to conflict")
+	}
+}
+
+// clientToken returns the token the client adds to its requests.
+func clientToken(c *apiClient) string {
+	return c.httpClient.Transport.(*tokenTransport).token
+}
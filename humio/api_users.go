@@ -0,0 +1,96 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"context"
+
+	humio "github.com/humio/cli/api"
+	"github.com/shurcooL/graphql"
+)
+
+// getUser returns the user with the given name. A user without a name is returned if it does not exist.
+func (c *apiClient) getUser(ctx context.Context, username string) (humio.User, error) {
+	var q struct {
+		User humio.User `graphql:"account(username: $username)"`
+	}
+
+	variables := map[string]interface{}{
+		"username": graphql.String(username),
+	}
+
+	err := c.query(ctx, &q, variables)
+	return q.User, err
+}
+
+func (c *apiClient) addUser(ctx context.Context, username string, changeset humio.UserChangeSet) error {
+	var m struct {
+		AddUser struct {
+			User humio.User
+		} `graphql:"addUser(input: {username: $username, isRoot: $isRoot, fullName: $fullName, company: $company, countryCode: $countryCode, email: $email, picture: $picture})"`
+	}
+
+	return c.mutate(ctx, &m, userChangeSetVariables(username, changeset))
+}
+
+func (c *apiClient) updateUser(ctx context.Context, username string, changeset humio.UserChangeSet) error {
+	var m struct {
+		UpdateUser struct {
+			User humio.User
+		} `graphql:"updateUser(input: {username: $username, isRoot: $isRoot, fullName: $fullName, company: $company, countryCode: $countryCode, email: $email, picture: $picture})"`
+	}
+
+	return c.mutate(ctx, &m, userChangeSetVariables(username, changeset))
+}
+
+func (c *apiClient) removeUser(ctx context.Context, username string) error {
+	var m struct {
+		RemoveUser struct {
+			User humio.User
+		} `graphql:"removeUser(input: {username: $username})"`
+	}
+
+	variables := map[string]interface{}{
+		"username": graphql.String(username),
+	}
+
+	return c.mutate(ctx, &m, variables)
+}
+
+// userChangeSetVariables returns the variables of the addUser and updateUser mutations. Fields left nil in changeset
+// are sent as null, which leaves them unchanged.
+func userChangeSetVariables(username string, changeset humio.UserChangeSet) map[string]interface{} {
+	optional := func(s *string) *graphql.String {
+		if s == nil {
+			return nil
+		}
+		return graphql.NewString(graphql.String(*s))
+	}
+
+	var isRoot *graphql.Boolean
+	if changeset.IsRoot != nil {
+		isRoot = graphql.NewBoolean(graphql.Boolean(*changeset.IsRoot))
+	}
+
+	return map[string]interface{}{
+		"username":    graphql.String(username),
+		"isRoot":      isRoot,
+		"fullName":    optional(changeset.FullName),
+		"company":     optional(changeset.Company),
+		"countryCode": optional(changeset.CountryCode),
+		"email":       optional(changeset.Email),
+		"picture":     optional(changeset.Picture),
+	}
+}
@@ -0,0 +1,140 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/shurcooL/graphql"
+
+	humio "github.com/humio/cli/api"
+)
+
+// The Humio API client only supports reading views, so the mutations needed to manage them live here.
+
+// view is a Humio view along with its description, which humio.View does not include.
+type view struct {
+	Name        string
+	Description string
+	Connections []humio.ViewConnection
+}
+
+// ViewConnectionInput is the GraphQL input type for a view connection. The type name is used by the GraphQL client
+// when declaring query variables, so it must match the name in the Humio schema.
+type ViewConnectionInput struct {
+	RepositoryName graphql.String `json:"repositoryName"`
+	Filter         graphql.String `json:"filter"`
+}
+
+func (c *apiClient) getView(ctx context.Context, name string) (*view, error) {
+	var q struct {
+		SearchDomain struct {
+			Typename    string `graphql:"__typename"`
+			Name        string
+			Description string
+			ViewInfo    struct {
+				Connections []struct {
+					Repository struct{ Name string }
+					Filter     string
+				}
+			} `graphql:"... on View"`
+		} `graphql:"searchDomain(name: $name)"`
+	}
+
+	variables := map[string]interface{}{
+		"name": graphql.String(name),
+	}
+
+	if err := c.query(ctx, &q, variables); err != nil {
+		return nil, err
+	}
+	if q.SearchDomain.Name == "" {
+		return nil, newNotFoundError("view %s", name)
+	}
+	if q.SearchDomain.Typename != "View" {
+		return nil, fmt.Errorf("%s is a %s, not a view", name, q.SearchDomain.Typename)
+	}
+
+	v := view{
+		Name:        q.SearchDomain.Name,
+		Description: q.SearchDomain.Description,
+		Connections: make([]humio.ViewConnection, len(q.SearchDomain.ViewInfo.Connections)),
+	}
+	for i, connection := range q.SearchDomain.ViewInfo.Connections {
+		v.Connections[i] = humio.ViewConnection{
+			RepoName: connection.Repository.Name,
+			Filter:   connection.Filter,
+		}
+	}
+	return &v, nil
+}
+
+func (c *apiClient) createView(ctx context.Context, v *view) error {
+	var m struct {
+		CreateView struct {
+			Name string
+		} `graphql:"createView(name: $name, description: $description, connections: $connections)"`
+	}
+
+	variables := map[string]interface{}{
+		"name":        graphql.String(v.Name),
+		"description": graphql.String(v.Description),
+		"connections": viewConnectionsInput(v.Connections),
+	}
+
+	return c.mutate(ctx, &m, variables)
+}
+
+func (c *apiClient) updateViewConnections(ctx context.Context, name string, connections []humio.ViewConnection) error {
+	var m struct {
+		UpdateViewConnections struct {
+			Name string
+		} `graphql:"updateViewConnections(viewName: $name, connections: $connections)"`
+	}
+
+	variables := map[string]interface{}{
+		"name":        graphql.String(name),
+		"connections": viewConnectionsInput(connections),
+	}
+
+	return c.mutate(ctx, &m, variables)
+}
+
+func (c *apiClient) deleteView(ctx context.Context, name, reason string) error {
+	var m struct {
+		DeleteSearchDomain struct {
+			Type string `graphql:"__typename"`
+		} `graphql:"deleteSearchDomain(name: $name, deleteMessage: $reason)"`
+	}
+
+	variables := map[string]interface{}{
+		"name":   graphql.String(name),
+		"reason": graphql.String(reason),
+	}
+
+	return c.mutate(ctx, &m, variables)
+}
+
+func viewConnectionsInput(connections []humio.ViewConnection) []ViewConnectionInput {
+	input := make([]ViewConnectionInput, len(connections))
+	for i, connection := range connections {
+		input[i] = ViewConnectionInput{
+			RepositoryName: graphql.String(connection.RepoName),
+			Filter:         graphql.String(connection.Filter),
+		}
+	}
+	return input
+}
@@ -0,0 +1,173 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"context"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	humio "github.com/humio/cli/api"
+)
+
+// Object types with cached listings.
+const (
+	cacheActions      = "actions"
+	cacheAlerts       = "alerts"
+	cacheIngestTokens = "ingest tokens"
+	cacheNotifiers    = "notifiers"
+)
+
+// listCache holds the listings of objects in a repository. The Humio API looks up alerts, notifiers and ingest tokens
+// by listing every object of the type in the repository, so reading many of them in a refresh is much faster when the
+// resources share a single listing. Listings are kept for the lifetime of the provider, which is a single Terraform
+// operation, and must be invalidated whenever an object of the type is written.
+type listCache struct {
+	mu      sync.Mutex
+	entries map[listCacheKey]*listCacheEntry
+}
+
+type listCacheKey struct {
+	objectType string
+	repository string
+}
+
+type listCacheEntry struct {
+	// ready is closed once value and err are set.
+	ready chan struct{}
+	value interface{}
+	err   error
+}
+
+// get returns the cached listing of objectType in repository, calling list if there is none. Concurrent calls for the
+// same listing wait for a single call to list. Failed listings are not cached. If c is nil, list is always called.
+func (c *listCache) get(objectType, repository string, list func() (interface{}, error)) (interface{}, error) {
+	if c == nil {
+		return list()
+	}
+
+	key := listCacheKey{objectType: objectType, repository: repository}
+	c.mu.Lock()
+	if c.entries == nil {
+		c.entries = map[listCacheKey]*listCacheEntry{}
+	}
+	entry, ok := c.entries[key]
+	if !ok {
+		entry = &listCacheEntry{ready: make(chan struct{})}
+		c.entries[key] = entry
+	}
+	c.mu.Unlock()
+
+	if ok {
+		<-entry.ready
+		return entry.value, entry.err
+	}
+
+	entry.value, entry.err = list()
+	close(entry.ready)
+	if entry.err != nil {
+		c.remove(key, entry)
+	}
+	return entry.value, entry.err
+}
+
+// invalidate drops the cached listing of objectType in repository.
+func (c *listCache) invalidate(objectType, repository string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, listCacheKey{objectType: objectType, repository: repository})
+}
+
+// invalidateRepository drops every cached listing in repository.
+func (c *listCache) invalidateRepository(repository string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key := range c.entries {
+		if key.repository == repository {
+			delete(c.entries, key)
+		}
+	}
+}
+
+func (c *listCache) remove(key listCacheKey, entry *listCacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.entries[key] == entry {
+		delete(c.entries, key)
+	}
+}
+
+// invalidateCachedListing drops the cached listing of objectType in the repository of d, both before and after the
+// change being applied. It must be called after every write of an object of the type.
+func invalidateCachedListing(d *schema.ResourceData, client interface{}, objectType string) {
+	old, new := d.GetChange("repository")
+	client.(*apiClient).cache.invalidate(objectType, old.(string))
+	client.(*apiClient).cache.invalidate(objectType, new.(string))
+}
+
+// getNotifier returns the notifier with the given name from the cached listing of notifiers in the repository.
+func (c *apiClient) getNotifier(ctx context.Context, repository, name string) (*humio.Notifier, error) {
+	v, err := c.cache.get(cacheNotifiers, repository, func() (interface{}, error) {
+		return c.listNotifiers(ctx, repository)
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, notifier := range v.([]humio.Notifier) {
+		if notifier.Name == name {
+			return &notifier, nil
+		}
+	}
+	return nil, newNotFoundError("notifier %s in repository %s", name, repository)
+}
+
+// getAlert returns the alert with the given name from the cached listing of alerts in the repository.
+func (c *apiClient) getAlert(ctx context.Context, repository, name string) (*humio.Alert, error) {
+	v, err := c.cache.get(cacheAlerts, repository, func() (interface{}, error) {
+		return c.listAlerts(ctx, repository)
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, alert := range v.([]humio.Alert) {
+		if alert.Name == name {
+			return &alert, nil
+		}
+	}
+	return nil, newNotFoundError("alert %s in repository %s", name, repository)
+}
+
+// getIngestToken returns the ingest token with the given name from the cached listing of ingest tokens in the
+// repository.
+func (c *apiClient) getIngestToken(ctx context.Context, repository, name string) (*humio.IngestToken, error) {
+	v, err := c.cache.get(cacheIngestTokens, repository, func() (interface{}, error) {
+		return c.listIngestTokens(ctx, repository)
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, token := range v.([]humio.IngestToken) {
+		if token.Name == name {
+			return &token, nil
+		}
+	}
+	return nil, newNotFoundError("ingest token %s in repository %s", name, repository)
+}
@@ -0,0 +1,136 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestListCache(t *testing.T) {
+	var calls int32
+	list := func() (interface{}, error) {
+		atomic.AddInt32(&calls, 1)
+		return []string{"a"}, nil
+	}
+
+	cache := &listCache{}
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := cache.get(cacheNotifiers, "sandbox", list); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	if calls != 1 {
+		t.Errorf("expected concurrent reads to share 1 listing, got %d", calls)
+	}
+
+	_, _ = cache.get(cacheAlerts, "sandbox", list)
+	_, _ = cache.get(cacheNotifiers, "humio", list)
+	if calls != 3 {
+		t.Errorf("expected a listing per object type and repository, got %d", calls)
+	}
+
+	cache.invalidate(cacheNotifiers, "sandbox")
+	_, _ = cache.get(cacheNotifiers, "sandbox", list)
+	_, _ = cache.get(cacheAlerts, "sandbox", list)
+	if calls != 4 {
+		t.Errorf("expected only the invalidated listing to be listed again, got %d", calls)
+	}
+
+	cache.invalidateRepository("sandbox")
+	_, _ = cache.get(cacheNotifiers, "sandbox", list)
+	_, _ = cache.get(cacheAlerts, "sandbox", list)
+	_, _ = cache.get(cacheNotifiers, "humio", list)
+	if calls != 6 {
+		t.Errorf("expected the listings in the repository to be listed again, got %d", calls)
+	}
+}
+
+func TestListCacheErrorsAreNotCached(t *testing.T) {
+	var calls int
+	list := func() (interface{}, error) {
+		calls++
+		if calls == 1 {
+			return nil, errors.New("service unavailable")
+		}
+		return []string{"a"}, nil
+	}
+
+	cache := &listCache{}
+	if _, err := cache.get(cacheNotifiers, "sandbox", list); err == nil {
+		t.Fatal("expected an error")
+	}
+	if _, err := cache.get(cacheNotifiers, "sandbox", list); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 2 {
+		t.Errorf("expected the failed listing to be retried, got %d calls", calls)
+	}
+
+	var nilCache *listCache
+	_, _ = nilCache.get(cacheNotifiers, "sandbox", list)
+	_, _ = nilCache.get(cacheNotifiers, "sandbox", list)
+	if calls != 4 {
+		t.Errorf("expected a nil cache to always list, got %d calls", calls)
+	}
+}
+
+func TestGetNotifierSharesListing(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		if r.URL.Path != "/api/v1/repositories/sandbox/alertnotifiers" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`[
+			{"id": "1", "name": "email", "entity": "EmailNotifier", "properties": {"recipients": ["ops@example.org"]}},
+			{"id": "2", "name": "slack", "entity": "SlackNotifier", "properties": {"url": "https://hooks.slack.com/x"}}
+		]`))
+	}))
+	defer server.Close()
+
+	address, _ := url.Parse(server.URL)
+	c := newAPIClient(address, "", http.DefaultTransport)
+	c.cache = &listCache{}
+
+	for _, name := range []string{"email", "slack"} {
+		notifier, err := c.getNotifier(context.Background(), "sandbox", name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if notifier.Name != name {
+			t.Errorf("expected notifier %s, got %s", name, notifier.Name)
+		}
+	}
+	if _, err := c.getNotifier(context.Background(), "sandbox", "missing"); !isNotFoundError(err) {
+		t.Errorf("expected not found error, got %v", err)
+	}
+	if requests != 1 {
+		t.Errorf("expected 1 listing of notifiers, got %d requests", requests)
+	}
+}
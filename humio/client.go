@@ -0,0 +1,179 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"context"
+	"io"
+	"log"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/shurcooL/graphql"
+)
+
+// retryBaseWait is the wait before the first retry. It doubles for every following attempt, up to retryMaxWait.
+const retryBaseWait = 500 * time.Millisecond
+
+// rxTransientStatusCode matches the status codes of transient errors. 429 Too Many Requests is not one of them, as it
+// is retried by limitedTransport, which also holds back the requests of other resources meanwhile.
+var rxTransientStatusCode = regexp.MustCompile(`status code: (502|503|504)\b`)
+
+// transientErrorMessages are fragments of errors returned while a Humio node is restarting or unreachable.
+var transientErrorMessages = []string{
+	"bad gateway",
+	"service unavailable",
+	"gateway timeout",
+	"connection refused",
+	"connection reset",
+	"tls handshake timeout",
+	"i/o timeout",
+	"unexpected eof",
+}
+
+// apiClient is the meta value handed to every resource. It sends requests to Humio with its own HTTP client, together
+// with the provider-level settings used when talking to Humio. The Humio API client is only used for its types, as it
+// sends requests without a context and with a transport of its own.
+type apiClient struct {
+	// address is the address of the Humio cluster.
+	address *url.URL
+	// httpClient sends requests to Humio, adding the API token to each of them.
+	httpClient *http.Client
+
+	retryMaxAttempts int
+	retryMaxWait     time.Duration
+
+	// limiter limits the requests sent to Humio by every resource sharing this client.
+	limiter *requestLimiter
+	// cache holds listings shared by the resources reading objects from the same repository.
+	cache *listCache
+
+	// version is the version of the Humio cluster, detected when the provider is configured. It is the zero value if
+	// the version check is skipped.
+	version serverVersion
+}
+
+// newAPIClient returns a client sending requests to the Humio cluster at address with transport, authenticated with
+// token.
+func newAPIClient(address *url.URL, token string, transport http.RoundTripper) *apiClient {
+	return &apiClient{
+		address:    address,
+		httpClient: &http.Client{Transport: &tokenTransport{base: transport, token: token}},
+	}
+}
+
+// do sends a request to path, relative to the address of the Humio cluster, with ctx.
+func (c *apiClient) do(ctx context.Context, method, path string, body io.Reader, contentType string) (*http.Response, error) {
+	address, err := c.address.Parse(path)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, method, address.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", contentType)
+	return c.httpClient.Do(req)
+}
+
+// call runs f, which talks to Humio, unless ctx is already done. f must send its requests with ctx, so they are
+// cancelled once the resource operation times out and f returns before Terraform reports the timeout.
+func (c *apiClient) call(ctx context.Context, f func() error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return f()
+}
+
+// query sends the GraphQL query built from q with ctx.
+func (c *apiClient) query(ctx context.Context, q interface{}, variables map[string]interface{}) error {
+	return c.graphQLClient().Query(ctx, q, variables)
+}
+
+// mutate sends the GraphQL mutation built from m with ctx.
+func (c *apiClient) mutate(ctx context.Context, m interface{}, variables map[string]interface{}) error {
+	return c.graphQLClient().Mutate(ctx, m, variables)
+}
+
+func (c *apiClient) graphQLClient() *graphql.Client {
+	address, _ := c.address.Parse("/graphql")
+	return graphql.NewClient(address.String(), c.httpClient)
+}
+
+// tokenTransport adds the API token to every request.
+type tokenTransport struct {
+	base  http.RoundTripper
+	token string
+}
+
+func (t *tokenTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+t.token)
+	return t.base.RoundTrip(req)
+}
+
+// retry calls f until it succeeds, fails with an error that is not transient or retryMaxAttempts attempts have been
+// made. Only use it for reads and updates that are safe to send more than once.
+func (c *apiClient) retry(ctx context.Context, operation string, f func() error) error {
+	for attempt := 1; ; attempt++ {
+		log.Printf("[DEBUG] %s: attempt %d of %d", operation, attempt, c.retryMaxAttempts)
+		err := c.call(ctx, f)
+		if err == nil || !isTransientError(err) || attempt >= c.retryMaxAttempts {
+			return err
+		}
+
+		wait := retryBackoff(attempt, c.retryMaxWait)
+		log.Printf("[WARN] %s failed on attempt %d of %d, retrying in %s: %s", operation, attempt, c.retryMaxAttempts, wait, err)
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(wait):
+		}
+	}
+}
+
+// retryBackoff returns the wait after the given attempt: exponential backoff capped at maxWait, where a random half
+// of the wait is dropped so concurrent operations do not retry in lockstep.
+func retryBackoff(attempt int, maxWait time.Duration) time.Duration {
+	wait := retryBaseWait << uint(attempt-1)
+	if wait > maxWait || wait <= 0 {
+		wait = maxWait
+	}
+	if wait < 2 {
+		return wait
+	}
+	return wait/2 + time.Duration(rand.Int63n(int64(wait/2)))
+}
+
+// isTransientError reports whether err is likely to go away by itself, such as a 502/503 during a rolling restart.
+func isTransientError(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := strings.ToLower(err.Error())
+	if rxTransientStatusCode.MatchString(msg) {
+		return true
+	}
+	for _, fragment := range transientErrorMessages {
+		if strings.Contains(msg, fragment) {
+			return true
+		}
+	}
+	return false
+}
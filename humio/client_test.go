@@ -0,0 +1,123 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestIsTransientError(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{nil, false},
+		{errors.New("non-200 OK status code: 502 Bad Gateway body: \"\""), true},
+		{errors.New("non-200 OK status code: 503 Service Unavailable body: \"\""), true},
+		{errors.New("dial tcp 127.0.0.1:8080: connect: connection refused"), true},
+		{errors.New("non-200 OK status code: 401 Unauthorized body: \"\""), false},
+		{errors.New("non-200 OK status code: 429 Too Many Requests body: \"\""), false},
+		{errors.New("could not find a notifier in view sandbox with name: n"), false},
+	}
+
+	for _, tt := range tests {
+		if got := isTransientError(tt.err); got != tt.want {
+			t.Errorf("isTransientError(%v) = %t, want %t", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestRetry(t *testing.T) {
+	c := &apiClient{retryMaxAttempts: 3, retryMaxWait: time.Millisecond}
+	transient := errors.New("non-200 OK status code: 503 Service Unavailable body: \"\"")
+
+	attempts := 0
+	err := c.retry(context.Background(), "test", func() error {
+		attempts++
+		if attempts < 3 {
+			return transient
+		}
+		return nil
+	})
+	if err != nil || attempts != 3 {
+		t.Errorf("expected success after 3 attempts, got %d attempts and error %v", attempts, err)
+	}
+
+	attempts = 0
+	err = c.retry(context.Background(), "test", func() error {
+		attempts++
+		return transient
+	})
+	if err != transient || attempts != 3 {
+		t.Errorf("expected transient error after 3 attempts, got %d attempts and error %v", attempts, err)
+	}
+
+	attempts = 0
+	permanent := errors.New("non-200 OK status code: 401 Unauthorized body: \"\"")
+	err = c.retry(context.Background(), "test", func() error {
+		attempts++
+		return permanent
+	})
+	if err != permanent || attempts != 1 {
+		t.Errorf("expected permanent error after 1 attempt, got %d attempts and error %v", attempts, err)
+	}
+}
+
+func TestRetryBackoff(t *testing.T) {
+	maxWait := 4 * time.Second
+	for attempt := 1; attempt <= 10; attempt++ {
+		want := retryBaseWait << uint(attempt-1)
+		if want > maxWait {
+			want = maxWait
+		}
+		got := retryBackoff(attempt, maxWait)
+		if got < want/2 || got > want {
+			t.Errorf("retryBackoff(%d) = %s, want between %s and %s", attempt, got, want/2, want)
+		}
+	}
+}
+
+func TestCallHonoursContextDeadline(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+	address, _ := url.Parse(server.URL)
+	c := newAPIClient(address, "", http.DefaultTransport)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err := c.call(ctx, func() error {
+		return c.restJSON(ctx, http.MethodGet, "/api/v1/status", nil, nil)
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline exceeded, got %v", err)
+	}
+
+	called := false
+	err = c.call(ctx, func() error {
+		called = true
+		return nil
+	})
+	if called || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline exceeded without calling f, got %v", err)
+	}
+}
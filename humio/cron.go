@@ -0,0 +1,116 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+)
+
+// cronField describes one of the five fields of a cron expression as accepted by Humio scheduled searches.
+type cronField struct {
+	name     string
+	min, max int
+	names    []string // names[i] is an alias for min+i
+}
+
+var cronFields = []cronField{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12, names: []string{"JAN", "FEB", "MAR", "APR", "MAY", "JUN", "JUL", "AUG", "SEP", "OCT", "NOV", "DEC"}},
+	{name: "day of week", min: 0, max: 7, names: []string{"SUN", "MON", "TUE", "WED", "THU", "FRI", "SAT"}},
+}
+
+// parseCronExpression returns an error describing the first problem found in a five field cron expression, such as
+// "0 8 * * MON-FRI". Each field is a comma separated list of values, ranges or *, each optionally followed by a step.
+func parseCronExpression(expr string) error {
+	fields := strings.Fields(expr)
+	if len(fields) != len(cronFields) {
+		return fmt.Errorf("expected %d fields separated by spaces (minute hour day-of-month month day-of-week), got %d", len(cronFields), len(fields))
+	}
+	for i, field := range fields {
+		for _, item := range strings.Split(field, ",") {
+			if err := cronFields[i].parseItem(item); err != nil {
+				return fmt.Errorf("invalid %s %q: %s", cronFields[i].name, field, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (f cronField) parseItem(item string) error {
+	rng, step := item, ""
+	if i := strings.Index(item, "/"); i >= 0 {
+		rng, step = item[:i], item[i+1:]
+		n, err := strconv.Atoi(step)
+		if err != nil || n <= 0 {
+			return fmt.Errorf("step %q must be a positive number", step)
+		}
+	}
+	if rng == "*" {
+		return nil
+	}
+
+	bounds := strings.SplitN(rng, "-", 2)
+	low, err := f.parseValue(bounds[0])
+	if err != nil {
+		return err
+	}
+	if len(bounds) == 2 {
+		high, err := f.parseValue(bounds[1])
+		if err != nil {
+			return err
+		}
+		if low > high {
+			return fmt.Errorf("range %q starts after it ends", rng)
+		}
+	}
+	return nil
+}
+
+func (f cronField) parseValue(s string) (int, error) {
+	for i, name := range f.names {
+		if strings.EqualFold(s, name) {
+			return f.min + i, nil
+		}
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a number", s)
+	}
+	if n < f.min || n > f.max {
+		return 0, fmt.Errorf("%d is outside %d-%d", n, f.min, f.max)
+	}
+	return n, nil
+}
+
+func validateCronExpression(val interface{}, key cty.Path) diag.Diagnostics {
+	var diagnostics diag.Diagnostics
+	v := val.(string)
+	if err := parseCronExpression(v); err != nil {
+		diagnostics = append(diagnostics, diag.Diagnostic{
+			Severity:      diag.Error,
+			Summary:       "Invalid cron expression",
+			Detail:        fmt.Sprintf("%s is not a valid cron expression: %s", v, err),
+			AttributePath: key,
+		})
+	}
+	return diagnostics
+}
@@ -0,0 +1,52 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"testing"
+)
+
+func TestParseCronExpression(t *testing.T) {
+	tests := []struct {
+		expr  string
+		valid bool
+	}{
+		{"* * * * *", true},
+		{"0 8 * * MON-FRI", true},
+		{"*/15 0-6,18-23 1,15 jan-jun sun", true},
+		{"30 2 * * 7", true},
+		{"5/10 * * * *", true},
+		{"", false},
+		{"* * * *", false},
+		{"* * * * * *", false},
+		{"60 * * * *", false},
+		{"* 24 * * *", false},
+		{"* * 0 * *", false},
+		{"* * * 13 *", false},
+		{"* * * * 8", false},
+		{"*/0 * * * *", false},
+		{"*/x * * * *", false},
+		{"10-5 * * * *", false},
+		{"* * * * MON-XYZ", false},
+		{"@daily", false},
+	}
+
+	for _, tt := range tests {
+		err := parseCronExpression(tt.expr)
+		if (err == nil) != tt.valid {
+			t.Errorf("parseCronExpression(%q) = %v, want valid %t", tt.expr, err, tt.valid)
+		}
+	}
+}
@@ -0,0 +1,314 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"gopkg.in/yaml.v2"
+)
+
+// Humio adds and rewrites parts of a dashboard template when storing it: widgets and sections are keyed by IDs
+// generated by the server, and the name given to the dashboard replaces the one in the template. Templates are
+// therefore compared after normalizing them, so only changes to the dashboard itself show up in a plan.
+
+// normalizeDashboardTemplate parses a YAML or JSON dashboard template into a value that is independent of key order,
+// formatting, the dashboard name and widget and section IDs.
+func normalizeDashboardTemplate(template string) (interface{}, error) {
+	var raw interface{}
+	if err := yaml.Unmarshal([]byte(template), &raw); err != nil {
+		return nil, err
+	}
+	t, ok := stringKeys(raw).(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected a map at the top level, got %T", raw)
+	}
+	delete(t, "name")
+
+	// Sections refer to widgets by ID, so the IDs are replaced by the widgets themselves before they are dropped.
+	widgets, _ := t["widgets"].(map[string]interface{})
+	for _, widget := range widgets {
+		if w, ok := widget.(map[string]interface{}); ok {
+			delete(w, "id")
+		}
+	}
+	if sections, ok := t["sections"]; ok {
+		list := unorderedList(sections)
+		for _, section := range list {
+			s, ok := section.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			delete(s, "id")
+			if ids, ok := s["widgetIds"].([]interface{}); ok {
+				for i, id := range ids {
+					if w, ok := widgets[fmt.Sprint(id)]; ok {
+						ids[i] = w
+					}
+				}
+			}
+		}
+		t["sections"] = list
+	}
+	if widgets != nil {
+		t["widgets"] = unorderedList(widgets)
+	}
+	return t, nil
+}
+
+// unorderedList returns the values of a map or the elements of a list in a canonical order.
+func unorderedList(v interface{}) []interface{} {
+	var list []interface{}
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for _, value := range v {
+			list = append(list, value)
+		}
+	case []interface{}:
+		list = append(list, v...)
+	}
+	sort.Slice(list, func(i, j int) bool { return canonicalJSON(list[i]) < canonicalJSON(list[j]) })
+	return list
+}
+
+// stringKeys converts the map[interface{}]interface{} values produced by the YAML parser into map[string]interface{}.
+func stringKeys(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			m[fmt.Sprint(key)] = stringKeys(value)
+		}
+		return m
+	case []interface{}:
+		for i, value := range v {
+			v[i] = stringKeys(value)
+		}
+	}
+	return v
+}
+
+// canonicalJSON encodes v with sorted map keys, so it can be used to order values.
+func canonicalJSON(v interface{}) string {
+	b, _ := json.Marshal(v)
+	return string(b)
+}
+
+func dashboardTemplatesEqual(a, b string) bool {
+	na, err := normalizeDashboardTemplate(a)
+	if err != nil {
+		return false
+	}
+	nb, err := normalizeDashboardTemplate(b)
+	if err != nil {
+		return false
+	}
+	return reflect.DeepEqual(na, nb)
+}
+
+func suppressEquivalentDashboardTemplate(k, old, new string, d *schema.ResourceData) bool {
+	return dashboardTemplatesEqual(old, new)
+}
+
+func validateDashboardTemplate(val interface{}, key cty.Path) diag.Diagnostics {
+	var diagnostics diag.Diagnostics
+	if _, err := normalizeDashboardTemplate(val.(string)); err != nil {
+		diagnostics = append(diagnostics, diag.Diagnostic{
+			Severity:      diag.Error,
+			Summary:       "Invalid dashboard template",
+			Detail:        fmt.Sprintf("the template must be a dashboard exported from Humio as YAML or JSON: %s", err),
+			AttributePath: key,
+		})
+	}
+	return diagnostics
+}
+
+// dashboardUpdatableKeys are the top-level keys of a dashboard template that the updateDashboard mutation can change.
+// Changing any other key, such as updateFrequency or parameters, replaces the dashboard.
+var dashboardUpdatableKeys = []string{"description", "labels", "widgets", "sections"}
+
+// dashboardTemplateRequiresReplacement reports whether changing a dashboard template from old to new touches anything
+// besides the keys in dashboardUpdatableKeys, so the dashboard has to be recreated from the new template.
+func dashboardTemplateRequiresReplacement(old, new string) bool {
+	no, err := normalizeDashboardTemplate(old)
+	if err != nil {
+		return true
+	}
+	nn, err := normalizeDashboardTemplate(new)
+	if err != nil {
+		return true
+	}
+	for _, key := range dashboardUpdatableKeys {
+		delete(no.(map[string]interface{}), key)
+		delete(nn.(map[string]interface{}), key)
+	}
+	return !reflect.DeepEqual(no, nn)
+}
+
+func customizeDashboardDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() == "" || !d.HasChange("template") {
+		return nil
+	}
+	old, new := d.GetChange("template")
+	if dashboardTemplateRequiresReplacement(old.(string), new.(string)) {
+		return d.ForceNew("template")
+	}
+	return nil
+}
+
+// dashboardUpdateInput converts a dashboard template into the input of the updateDashboard mutation, which describes
+// widgets and sections as lists rather than maps keyed by their IDs.
+func dashboardUpdateInput(id, name, template string) (map[string]interface{}, error) {
+	var raw interface{}
+	if err := yaml.Unmarshal([]byte(template), &raw); err != nil {
+		return nil, err
+	}
+	t, ok := stringKeys(raw).(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected a map at the top level, got %T", raw)
+	}
+
+	input := map[string]interface{}{
+		"id":          id,
+		"name":        name,
+		"description": templateString(t, "description"),
+		"labels":      []string{},
+		"widgets":     []map[string]interface{}{},
+		"sections":    []map[string]interface{}{},
+	}
+	if labels, ok := t["labels"].([]interface{}); ok {
+		list := make([]string, len(labels))
+		for i, label := range labels {
+			list[i] = fmt.Sprint(label)
+		}
+		input["labels"] = list
+	}
+
+	widgets, _ := t["widgets"].(map[string]interface{})
+	for _, widgetID := range sortedKeys(widgets) {
+		w, ok := widgets[widgetID].(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("widget %s: expected a map, got %T", widgetID, widgets[widgetID])
+		}
+		widget, err := dashboardWidgetInput(widgetID, w)
+		if err != nil {
+			return nil, fmt.Errorf("widget %s: %s", widgetID, err)
+		}
+		input["widgets"] = append(input["widgets"].([]map[string]interface{}), widget)
+	}
+
+	sections, _ := t["sections"].(map[string]interface{})
+	for i, sectionID := range sortedKeys(sections) {
+		s, ok := sections[sectionID].(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("section %s: expected a map, got %T", sectionID, sections[sectionID])
+		}
+		widgetIDs := []string{}
+		if ids, ok := s["widgetIds"].([]interface{}); ok {
+			for _, widgetID := range ids {
+				widgetIDs = append(widgetIDs, fmt.Sprint(widgetID))
+			}
+		}
+		order := i
+		if o, ok := templateInt(s, "order"); ok {
+			order = o
+		}
+		collapsed, _ := s["collapsed"].(bool)
+		input["sections"] = append(input["sections"].([]map[string]interface{}), map[string]interface{}{
+			"id":          sectionID,
+			"title":       templateString(s, "title"),
+			"description": templateString(s, "description"),
+			"collapsed":   collapsed,
+			"widgetIds":   widgetIDs,
+			"order":       order,
+		})
+	}
+	return input, nil
+}
+
+// dashboardWidgetInput converts a widget of a dashboard template into the input of the updateDashboard mutation. Note
+// widgets carry their text, every other widget is a query widget.
+func dashboardWidgetInput(id string, w map[string]interface{}) (map[string]interface{}, error) {
+	widget := map[string]interface{}{
+		"id":          id,
+		"title":       templateString(w, "title"),
+		"description": templateString(w, "description"),
+	}
+	// YAML 1.1 reads the key y as the boolean true, which stringKeys turns into "true".
+	if _, ok := w["y"]; !ok {
+		w["y"] = w["true"]
+	}
+	for _, key := range []string{"x", "y", "width", "height"} {
+		v, ok := templateInt(w, key)
+		if !ok {
+			return nil, fmt.Errorf("%s must be a number", key)
+		}
+		widget[key] = v
+	}
+
+	if templateString(w, "type") == "note" {
+		note := map[string]interface{}{
+			"text": templateString(w, "text"),
+		}
+		for _, key := range []string{"backgroundColor", "textColor"} {
+			if v, ok := w[key]; ok {
+				note[key] = fmt.Sprint(v)
+			}
+		}
+		widget["noteOptions"] = note
+		return widget, nil
+	}
+
+	query := map[string]interface{}{
+		"queryString": templateString(w, "queryString"),
+		"start":       templateString(w, "start"),
+		"end":         templateString(w, "end"),
+		"isLive":      w["isLive"] == true,
+		"widgetType":  templateString(w, "visualization"),
+	}
+	if options, ok := w["options"]; ok {
+		b, err := json.Marshal(options)
+		if err != nil {
+			return nil, fmt.Errorf("options: %s", err)
+		}
+		query["options"] = string(b)
+	}
+	widget["queryOptions"] = query
+	return widget, nil
+}
+
+func templateString(m map[string]interface{}, key string) string {
+	if v, ok := m[key]; ok && v != nil {
+		return fmt.Sprint(v)
+	}
+	return ""
+}
+
+func templateInt(m map[string]interface{}, key string) (int, bool) {
+	switch v := m[key].(type) {
+	case int:
+		return v, true
+	case float64:
+		return int(v), true
+	}
+	return 0, false
+}
@@ -0,0 +1,285 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const dashboardTemplateYAML = `
+name: Errors
+updateFrequency: never
+widgets:
+  1a2b3c4d:
+    x: 0
+    y: 0
+    width: 4
+    height: 4
+    queryString: loglevel=ERROR | timechart()
+    visualization: time-chart
+    title: Errors over time
+  5e6f7a8b:
+    x: 4
+    y: 0
+    width: 4
+    height: 4
+    queryString: loglevel=ERROR | count()
+    visualization: single-value
+    title: Error count
+sections:
+  9c0d1e2f:
+    title: Overview
+    collapsed: false
+    widgetIds:
+    - 1a2b3c4d
+    - 5e6f7a8b
+`
+
+func TestDashboardTemplatesEqual(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want bool
+	}{
+		{
+			name: "identical",
+			a:    dashboardTemplateYAML,
+			b:    dashboardTemplateYAML,
+			want: true,
+		},
+		{
+			name: "json and yaml",
+			a:    `{"name": "Errors", "updateFrequency": "never"}`,
+			b:    "updateFrequency: never\nname: Errors\n",
+			want: true,
+		},
+		{
+			name: "key order and whitespace",
+			a:    "updateFrequency: never\nname: Errors\n",
+			b:    "name:    Errors\n\n\nupdateFrequency:   never\n",
+			want: true,
+		},
+		{
+			name: "dashboard name is ignored",
+			a:    "name: Errors\nupdateFrequency: never\n",
+			b:    "name: Renamed\nupdateFrequency: never\n",
+			want: true,
+		},
+		{
+			name: "server generated widget and section ids",
+			a:    dashboardTemplateYAML,
+			b: `
+name: Errors
+updateFrequency: never
+widgets:
+  ffff0001:
+    id: ffff0001
+    x: 4
+    y: 0
+    width: 4
+    height: 4
+    queryString: loglevel=ERROR | count()
+    visualization: single-value
+    title: Error count
+  ffff0002:
+    id: ffff0002
+    x: 0
+    y: 0
+    width: 4
+    height: 4
+    queryString: loglevel=ERROR | timechart()
+    visualization: time-chart
+    title: Errors over time
+sections:
+  ffff0003:
+    title: Overview
+    collapsed: false
+    widgetIds:
+    - ffff0002
+    - ffff0001
+`,
+			want: true,
+		},
+		{
+			name: "widget moved to another section position",
+			a:    dashboardTemplateYAML,
+			b: `
+name: Errors
+updateFrequency: never
+widgets:
+  1a2b3c4d:
+    x: 0
+    y: 0
+    width: 4
+    height: 4
+    queryString: loglevel=ERROR | timechart()
+    visualization: time-chart
+    title: Errors over time
+  5e6f7a8b:
+    x: 4
+    y: 0
+    width: 4
+    height: 4
+    queryString: loglevel=ERROR | count()
+    visualization: single-value
+    title: Error count
+sections:
+  9c0d1e2f:
+    title: Overview
+    collapsed: false
+    widgetIds:
+    - 5e6f7a8b
+    - 1a2b3c4d
+`,
+			want: false,
+		},
+		{
+			name: "changed query",
+			a:    "name: Errors\nwidgets:\n  a:\n    queryString: count()\n",
+			b:    "name: Errors\nwidgets:\n  a:\n    queryString: count(field=x)\n",
+			want: false,
+		},
+		{
+			name: "invalid template",
+			a:    "name: Errors\n",
+			b:    "- not a dashboard",
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := dashboardTemplatesEqual(tt.a, tt.b); got != tt.want {
+				t.Errorf("dashboardTemplatesEqual() = %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateDashboardTemplate(t *testing.T) {
+	if diags := validateDashboardTemplate(dashboardTemplateYAML, nil); diags.HasError() {
+		t.Errorf("expected template to be valid, got %v", diags)
+	}
+	if diags := validateDashboardTemplate("name: [unterminated", nil); !diags.HasError() {
+		t.Error("expected invalid YAML to be rejected")
+	}
+	if diags := validateDashboardTemplate("just a string", nil); !diags.HasError() {
+		t.Error("expected a template that is not a map to be rejected")
+	}
+}
+
+func TestDashboardTemplateRequiresReplacement(t *testing.T) {
+	tests := []struct {
+		name     string
+		old, new string
+		want     bool
+	}{
+		{
+			name: "changed query",
+			old:  "name: Errors\nupdateFrequency: never\nwidgets:\n  a:\n    queryString: count()\n",
+			new:  "name: Errors\nupdateFrequency: never\nwidgets:\n  a:\n    queryString: count(field=x)\n",
+			want: false,
+		},
+		{
+			name: "changed labels and description",
+			old:  "name: Errors\nlabels: [a]\n",
+			new:  "name: Errors\nlabels: [a, b]\ndescription: All errors\n",
+			want: false,
+		},
+		{
+			name: "changed update frequency",
+			old:  "name: Errors\nupdateFrequency: never\n",
+			new:  "name: Errors\nupdateFrequency: realtime\n",
+			want: true,
+		},
+		{
+			name: "added parameters",
+			old:  "name: Errors\n",
+			new:  "name: Errors\nparameters:\n  host:\n    label: Host\n",
+			want: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := dashboardTemplateRequiresReplacement(tt.old, tt.new); got != tt.want {
+				t.Errorf("dashboardTemplateRequiresReplacement() = %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDashboardUpdateInput(t *testing.T) {
+	template := dashboardTemplateYAML + `
+labels:
+- errors
+description: All errors
+`
+	template = strings.Replace(template, "widgets:\n", `widgets:
+  0note:
+    type: note
+    x: 8
+    y: 0
+    width: 2
+    height: 2
+    title: About
+    text: Errors logged by every service
+`, 1)
+
+	input, err := dashboardUpdateInput("dashboard-id", "Renamed", template)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]interface{}{
+		"id":          "dashboard-id",
+		"name":        "Renamed",
+		"description": "All errors",
+		"labels":      []string{"errors"},
+		"widgets": []map[string]interface{}{
+			{
+				"id": "0note", "title": "About", "description": "", "x": 8, "y": 0, "width": 2, "height": 2,
+				"noteOptions": map[string]interface{}{"text": "Errors logged by every service"},
+			},
+			{
+				"id": "1a2b3c4d", "title": "Errors over time", "description": "", "x": 0, "y": 0, "width": 4, "height": 4,
+				"queryOptions": map[string]interface{}{
+					"queryString": "loglevel=ERROR | timechart()", "start": "", "end": "", "isLive": false, "widgetType": "time-chart",
+				},
+			},
+			{
+				"id": "5e6f7a8b", "title": "Error count", "description": "", "x": 4, "y": 0, "width": 4, "height": 4,
+				"queryOptions": map[string]interface{}{
+					"queryString": "loglevel=ERROR | count()", "start": "", "end": "", "isLive": false, "widgetType": "single-value",
+				},
+			},
+		},
+		"sections": []map[string]interface{}{
+			{
+				"id": "9c0d1e2f", "title": "Overview", "description": "", "collapsed": false, "order": 0,
+				"widgetIds": []string{"1a2b3c4d", "5e6f7a8b"},
+			},
+		},
+	}
+	if !reflect.DeepEqual(input, want) {
+		t.Errorf("dashboardUpdateInput() = %#v, want %#v", input, want)
+	}
+
+	if _, err := dashboardUpdateInput("dashboard-id", "Errors", "widgets:\n  a:\n    x: left\n"); err == nil {
+		t.Error("expected a widget without a numeric position to be rejected")
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// errNotFound is used for objects the Humio API reports as missing without returning an error, e.g. when a parser
+// lookup comes back as an empty struct.
+var errNotFound = errors.New("not found")
+
+// notFoundMessages are the fragments the Humio API uses in its error messages when an object does not exist. The
+// REST endpoints used for alerts and notifiers and the ingest token lookups produce "could not find ...", while the
+// GraphQL API reports missing entities with "... not found".
+var notFoundMessages = []string{
+	"could not find",
+	"not found",
+	"does not exist",
+}
+
+// isNotFoundError reports whether err means the requested object does not exist in Humio. Transport, authentication
+// and server errors are not classified as not found, so they still fail the operation.
+func isNotFoundError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, errNotFound) {
+		return true
+	}
+	msg := strings.ToLower(err.Error())
+	// A 404 on the GraphQL endpoint itself means the provider is pointed at the wrong address.
+	if strings.Contains(msg, "non-200 ok status code") {
+		return false
+	}
+	for _, fragment := range notFoundMessages {
+		if strings.Contains(msg, fragment) {
+			return true
+		}
+	}
+	return false
+}
+
+// newNotFoundError returns an error classified as not found by isNotFoundError.
+func newNotFoundError(format string, a ...interface{}) error {
+	return fmt.Errorf("%w: %s", errNotFound, fmt.Sprintf(format, a...))
+}
+
+// removeFromStateIfNotFound clears the ID of d when err says the object was deleted outside of Terraform, which makes
+// Terraform plan to create it again. It returns true if the resource was removed from state. Objects that were just
+// created are never removed, as a missing object at that point is an error.
+func removeFromStateIfNotFound(d *schema.ResourceData, resourceType string, err error) bool {
+	if d.IsNewResource() || !isNotFoundError(err) {
+		return false
+	}
+	log.Printf("[WARN] %s %s not found, removing from state: %s", resourceType, d.Id(), err)
+	d.SetId("")
+	return true
+}
@@ -0,0 +1,62 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestIsNotFoundError(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{nil, false},
+		{newNotFoundError("parser %s in repository %s", "p", "sandbox"), true},
+		{fmt.Errorf("could not get parser: %w", newNotFoundError("parser p")), true},
+		{errors.New("could not find a notifier in view sandbox with name: n"), true},
+		{errors.New("could not find an ingest token with name 't' in repo 'sandbox'"), true},
+		{errors.New("Repository with name 'x' was not found. Does the repo already exist?"), true},
+		{errors.New("non-200 OK status code: 404 Not Found body: \"\""), false},
+		{errors.New("non-200 OK status code: 401 Unauthorized body: \"\""), false},
+		{errors.New("dial tcp 127.0.0.1:8080: connect: connection refused"), false},
+	}
+
+	for _, tt := range tests {
+		if got := isNotFoundError(tt.err); got != tt.want {
+			t.Errorf("isNotFoundError(%v) = %t, want %t", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestRemoveFromStateIfNotFound(t *testing.T) {
+	d := resourceParser().TestResourceData()
+	d.SetId("sandbox+parser")
+
+	if removeFromStateIfNotFound(d, "humio_parser", errors.New("non-200 OK status code: 503 Service Unavailable")) {
+		t.Fatal("resource removed from state on a server error")
+	}
+	if d.Id() != "sandbox+parser" {
+		t.Fatalf("expected ID to be kept, got %q", d.Id())
+	}
+	if !removeFromStateIfNotFound(d, "humio_parser", newNotFoundError("parser parser")) {
+		t.Fatal("resource not removed from state")
+	}
+	if d.Id() != "" {
+		t.Fatalf("expected ID to be cleared, got %q", d.Id())
+	}
+}
@@ -0,0 +1,356 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"reflect"
+	"regexp"
+	"sort"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/zclconf/go-cty/cty"
+)
+
+var rxInvalidLabelChars = regexp.MustCompile(`[^a-zA-Z0-9_-]+`)
+
+// Export configures the provider from the environment (HUMIO_ADDR, HUMIO_API_TOKEN, ...) and writes HCL for every
+// repository, parser, ingest token, notifier and alert found in the cluster to w, together with an import block for
+// each of them. If repositories is non-empty, only those repositories are exported.
+func Export(ctx context.Context, w io.Writer, repositories []string) error {
+	provider := Provider()
+	if diags := provider.Configure(ctx, terraform.NewResourceConfigRaw(map[string]interface{}{})); diags.HasError() {
+		return fmt.Errorf("could not configure provider: %s", diagnosticsToString(diags))
+	}
+
+	f, err := exportCluster(ctx, provider.Meta().(*apiClient), repositories)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(hclwrite.Format(f.Bytes()))
+	return err
+}
+
+// exporter keeps track of the resource labels handed out so far, so every generated resource address is unique.
+type exporter struct {
+	file   *hclwrite.File
+	labels map[string]bool
+}
+
+func newExporter() *exporter {
+	return &exporter{
+		file:   hclwrite.NewEmptyFile(),
+		labels: map[string]bool{},
+	}
+}
+
+func exportCluster(ctx context.Context, client *apiClient, repositories []string) (*hclwrite.File, error) {
+	e := newExporter()
+
+	if len(repositories) == 0 {
+		repoList, err := client.listRepositories(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("could not list repositories: %s", err)
+		}
+		for _, repo := range repoList {
+			repositories = append(repositories, repo.Name)
+		}
+		sort.Strings(repositories)
+	}
+
+	for _, repositoryName := range repositories {
+		if err := e.exportRepository(ctx, client, repositoryName); err != nil {
+			return nil, err
+		}
+	}
+	return e.file, nil
+}
+
+func (e *exporter) exportRepository(ctx context.Context, client *apiClient, repositoryName string) error {
+	repository, err := client.getRepository(ctx, repositoryName)
+	if err != nil {
+		return fmt.Errorf("could not get repository %s: %s", repositoryName, err)
+	}
+	d := resourceRepository().Data(nil)
+	if diags := resourceDataFromRepository(&repository, d); diags.HasError() {
+		return fmt.Errorf("could not export repository %s: %s", repositoryName, diagnosticsToString(diags))
+	}
+	e.writeResource("humio_repository", repositoryName, repositoryName, resourceRepository(), d)
+
+	parsers, err := client.listParsers(ctx, repositoryName)
+	if err != nil {
+		return fmt.Errorf("could not list parsers in repository %s: %s", repositoryName, err)
+	}
+	for _, p := range parsers {
+		if p.IsBuiltIn {
+			continue
+		}
+		parser, err := client.getParser(ctx, repositoryName, p.Name)
+		if err != nil {
+			return fmt.Errorf("could not get parser %s in repository %s: %s", p.Name, repositoryName, err)
+		}
+		d, err := e.scopedResourceData(resourceParser(), repositoryName)
+		if err != nil {
+			return err
+		}
+		if diags := resourceDataFromParser(parser, d); diags.HasError() {
+			return fmt.Errorf("could not export parser %s: %s", p.Name, diagnosticsToString(diags))
+		}
+		e.writeResource("humio_parser", repositoryName+"_"+parser.Name, fmt.Sprintf("%s+%s", repositoryName, parser.Name), resourceParser(), d)
+	}
+
+	ingestTokens, err := client.listIngestTokens(ctx, repositoryName)
+	if err != nil {
+		return fmt.Errorf("could not list ingest tokens in repository %s: %s", repositoryName, err)
+	}
+	for i := range ingestTokens {
+		d, err := e.scopedResourceData(resourceIngestToken(), repositoryName)
+		if err != nil {
+			return err
+		}
+		if diags := resourceDataFromIngestToken(&ingestTokens[i], d); diags.HasError() {
+			return fmt.Errorf("could not export ingest token %s: %s", ingestTokens[i].Name, diagnosticsToString(diags))
+		}
+		e.writeResource("humio_ingest_token", repositoryName+"_"+ingestTokens[i].Name, fmt.Sprintf("%s+%s", repositoryName, ingestTokens[i].Name), resourceIngestToken(), d)
+	}
+
+	notifiers, err := client.listNotifiers(ctx, repositoryName)
+	if err != nil {
+		return fmt.Errorf("could not list notifiers in repository %s: %s", repositoryName, err)
+	}
+	// Alerts refer to notifiers by ID, which is replaced by a reference to the exported notifier.
+	notifierLabels := map[string]string{}
+	for i := range notifiers {
+		d, err := e.scopedResourceData(resourceNotifier(), repositoryName)
+		if err != nil {
+			return err
+		}
+		if diags := resourceDataFromNotifier(&notifiers[i], d); diags.HasError() {
+			return fmt.Errorf("could not export notifier %s: %s", notifiers[i].Name, diagnosticsToString(diags))
+		}
+		block := e.writeResource("humio_notifier", repositoryName+"_"+notifiers[i].Name, fmt.Sprintf("%s+%s", repositoryName, notifiers[i].Name), resourceNotifier(), d)
+		notifierLabels[notifiers[i].ID] = block.Labels()[1]
+	}
+
+	alerts, err := client.listAlerts(ctx, repositoryName)
+	if err != nil {
+		return fmt.Errorf("could not list alerts in repository %s: %s", repositoryName, err)
+	}
+	for i := range alerts {
+		d, err := e.scopedResourceData(resourceAlert(), repositoryName)
+		if err != nil {
+			return err
+		}
+		if diags := resourceDataFromAlert(&alerts[i], d); diags.HasError() {
+			return fmt.Errorf("could not export alert %s: %s", alerts[i].Name, diagnosticsToString(diags))
+		}
+		block := e.writeResource("humio_alert", repositoryName+"_"+alerts[i].Name, fmt.Sprintf("%s+%s", repositoryName, alerts[i].Name), resourceAlert(), d)
+		if len(alerts[i].Notifiers) > 0 {
+			block.Body().SetAttributeRaw("notifiers", notifierReferences(alerts[i].Notifiers, notifierLabels))
+		}
+	}
+
+	return nil
+}
+
+// scopedResourceData returns an empty resource data object for a resource living inside a repository. The
+// resourceDataFrom* functions leave "repository" alone as Read normally gets it from the configuration or the ID.
+func (e *exporter) scopedResourceData(r *schema.Resource, repositoryName string) (*schema.ResourceData, error) {
+	d := r.Data(nil)
+	if err := d.Set("repository", repositoryName); err != nil {
+		return nil, fmt.Errorf("error setting repository %s: %s", repositoryName, err)
+	}
+	return d, nil
+}
+
+// notifierReferences returns a list of references to the notifier_id of the exported notifiers with the given IDs.
+// IDs of notifiers that were not exported are kept as they are.
+func notifierReferences(ids []string, labels map[string]string) hclwrite.Tokens {
+	tokens := hclwrite.Tokens{{Type: hclsyntax.TokenOBrack, Bytes: []byte("[")}}
+	for i, id := range ids {
+		if i > 0 {
+			tokens = append(tokens, &hclwrite.Token{Type: hclsyntax.TokenComma, Bytes: []byte(",")})
+		}
+		label, ok := labels[id]
+		if !ok {
+			tokens = append(tokens, hclwrite.TokensForValue(cty.StringVal(id))...)
+			continue
+		}
+		tokens = append(tokens, hclwrite.TokensForTraversal(hcl.Traversal{
+			hcl.TraverseRoot{Name: "humio_notifier"},
+			hcl.TraverseAttr{Name: label},
+			hcl.TraverseAttr{Name: "notifier_id"},
+		})...)
+	}
+	return append(tokens, &hclwrite.Token{Type: hclsyntax.TokenCBrack, Bytes: []byte("]")})
+}
+
+// writeResource appends a resource block and the matching import block to the generated file, and returns the
+// resource block.
+func (e *exporter) writeResource(resourceType, name, importID string, r *schema.Resource, d *schema.ResourceData) *hclwrite.Block {
+	label := e.uniqueLabel(name)
+
+	block := e.file.Body().AppendNewBlock("resource", []string{resourceType, label})
+	writeAttributes(block.Body(), r.Schema, d.Get, false)
+	e.file.Body().AppendNewline()
+
+	importBlock := e.file.Body().AppendNewBlock("import", nil)
+	importBlock.Body().SetAttributeTraversal("to", hcl.Traversal{
+		hcl.TraverseRoot{Name: resourceType},
+		hcl.TraverseAttr{Name: label},
+	})
+	importBlock.Body().SetAttributeValue("id", cty.StringVal(importID))
+	e.file.Body().AppendNewline()
+	return block
+}
+
+// uniqueLabel turns name into a valid Terraform resource name, adding a numeric suffix if it has been used before.
+// The suffixed label is checked too, as it may be the label of a resource whose name already ends in a number.
+func (e *exporter) uniqueLabel(name string) string {
+	base := rxInvalidLabelChars.ReplaceAllString(name, "_")
+	if base == "" || (base[0] >= '0' && base[0] <= '9') || base[0] == '-' {
+		base = "_" + base
+	}
+	label := base
+	for n := 2; e.labels[label]; n++ {
+		label = fmt.Sprintf("%s_%d", base, n)
+	}
+	e.labels[label] = true
+	return label
+}
+
+// writeAttributes writes the configurable attributes of s to body, skipping computed-only attributes and, unless all is
+// set, values that match what Terraform would use anyway when the attribute is left out. It returns the number of
+// attributes and blocks written.
+func writeAttributes(body *hclwrite.Body, s map[string]*schema.Schema, get func(string) interface{}, all bool) int {
+	keys := make([]string, 0, len(s))
+	for k := range s {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	written := 0
+	for _, k := range keys {
+		attr := s[k]
+		if attr.Computed && !attr.Optional && !attr.Required {
+			continue
+		}
+		value := get(k)
+		if set, ok := value.(*schema.Set); ok {
+			value = set.List()
+		}
+
+		if nested, ok := attr.Elem.(*schema.Resource); ok {
+			items, _ := value.([]interface{})
+			for _, item := range items {
+				m, _ := item.(tfMap)
+				block := body.AppendNewBlock(k, nil)
+				get := func(key string) interface{} { return m[key] }
+				// A block holding only default values, such as the retention of a repository without retention
+				// settings, would be written as an empty block, so its values are written out instead.
+				if writeAttributes(block.Body(), nested.Schema, get, false) == 0 {
+					writeAttributes(block.Body(), nested.Schema, get, true)
+				}
+				written++
+			}
+			continue
+		}
+
+		if !all && !attr.Required && isDefaultValue(attr, value) {
+			continue
+		}
+		body.SetAttributeValue(k, ctyValue(value))
+		written++
+	}
+	return written
+}
+
+func isDefaultValue(attr *schema.Schema, value interface{}) bool {
+	if attr.Default != nil {
+		return reflect.DeepEqual(attr.Default, value)
+	}
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case int:
+		return v == 0
+	case float64:
+		return v == 0
+	case bool:
+		return !v
+	case []interface{}:
+		return len(v) == 0
+	case tfMap:
+		return len(v) == 0
+	case []string:
+		return len(v) == 0
+	}
+	return false
+}
+
+func ctyValue(value interface{}) cty.Value {
+	switch v := value.(type) {
+	case string:
+		return cty.StringVal(v)
+	case int:
+		return cty.NumberIntVal(int64(v))
+	case float64:
+		return cty.NumberFloatVal(v)
+	case bool:
+		return cty.BoolVal(v)
+	case []string:
+		values := make([]cty.Value, len(v))
+		for i := range v {
+			values[i] = cty.StringVal(v[i])
+		}
+		return cty.TupleVal(values)
+	case []interface{}:
+		values := make([]cty.Value, len(v))
+		for i := range v {
+			values[i] = ctyValue(v[i])
+		}
+		return cty.TupleVal(values)
+	case tfMap:
+		values := make(map[string]cty.Value, len(v))
+		for key := range v {
+			values[key] = ctyValue(v[key])
+		}
+		return cty.ObjectVal(values)
+	}
+	return cty.StringVal(fmt.Sprintf("%v", value))
+}
+
+func diagnosticsToString(diags diag.Diagnostics) string {
+	var s string
+	for i, d := range diags {
+		if i > 0 {
+			s += "; "
+		}
+		s += d.Summary
+		if d.Detail != "" {
+			s += ": " + d.Detail
+		}
+	}
+	return s
+}
@@ -0,0 +1,380 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/zclconf/go-cty/cty"
+
+	humio "github.com/humio/cli/api"
+)
+
+func TestExportResources(t *testing.T) {
+	e := newExporter()
+
+	repository := resourceRepository().Data(nil)
+	resourceDataFromRepository(&humio.Repository{Name: "sandbox", RetentionDays: 30}, repository)
+	e.writeResource("humio_repository", "sandbox", "sandbox", resourceRepository(), repository)
+
+	notifier, err := e.scopedResourceData(resourceNotifier(), "sandbox")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resourceDataFromNotifier(&humio.Notifier{
+		ID:     "abc",
+		Entity: humio.NotifierTypeSlack,
+		Name:   "ops slack",
+		Properties: map[string]interface{}{
+			"fields": map[string]interface{}{"Events String": "{events_str}"},
+			"url":    "https://hooks.slack.com/services/XXXXXXXXX/YYYYYYYYY/ZZZZZZZZZZZZZZZZZZZZZZZZ",
+		},
+	}, notifier)
+	e.writeResource("humio_notifier", "sandbox_ops slack", "sandbox+ops slack", resourceNotifier(), notifier)
+
+	alert, err := e.scopedResourceData(resourceAlert(), "sandbox")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resourceDataFromAlert(&wantAlert, alert)
+	e.writeResource("humio_alert", "sandbox_"+wantAlert.Name, "sandbox+"+wantAlert.Name, resourceAlert(), alert)
+
+	want := `resource "humio_repository" "sandbox" {
+  name = "sandbox"
+  retention {
+    time_in_days = 30
+  }
+}
+
+import {
+  to = humio_repository.sandbox
+  id = "sandbox"
+}
+
+resource "humio_notifier" "sandbox_ops_slack" {
+  entity     = "SlackNotifier"
+  name       = "ops slack"
+  repository = "sandbox"
+  slack {
+    fields = {
+      "Events String" = "{events_str}"
+    }
+    url = "https://hooks.slack.com/services/XXXXXXXXX/YYYYYYYYY/ZZZZZZZZZZZZZZZZZZZZZZZZ"
+  }
+}
+
+import {
+  to = humio_notifier.sandbox_ops_slack
+  id = "sandbox+ops slack"
+}
+
+resource "humio_alert" "sandbox_over_1000_errors_last_5_minutes" {
+  description          = "errors occurred"
+  labels               = ["important", "error"]
+  name                 = "over 1000 errors last 5 minutes"
+  notifiers            = ["notifier1", "notifier2"]
+  query                = "loglevel=ERROR | count() > 1000"
+  repository           = "sandbox"
+  start                = "15m"
+  throttle_time_millis = 3600000
+}
+
+import {
+  to = humio_alert.sandbox_over_1000_errors_last_5_minutes
+  id = "sandbox+over 1000 errors last 5 minutes"
+}
+
+`
+	got := string(hclwrite.Format(e.file.Bytes()))
+	if !cmp.Equal(want, got) {
+		t.Error(cmp.Diff(want, got))
+	}
+}
+
+func TestExportCluster(t *testing.T) {
+	server := newFakeHumioServer()
+	defer server.Close()
+	defer setTestEnv("HUMIO_ADDR", server.URL)()
+	defer setTestEnv("HUMIO_API_TOKEN", fakeHumioToken)()
+	client, err := sweeperClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var ids []string
+	for _, name := range []string{"ops slack", "ops_slack", "ops_slack_2"} {
+		notifier := humio.Notifier{
+			Entity:     humio.NotifierTypeEmail,
+			Name:       name,
+			Properties: map[string]interface{}{"recipients": []string{"ops@example.com"}},
+		}
+		if err := client.createNotifier(context.Background(), "sandbox", &notifier); err != nil {
+			t.Fatal(err)
+		}
+		id, err := client.notifierID(context.Background(), "sandbox", name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, id)
+	}
+	alert := humio.Alert{
+		Name:               "errors",
+		Query:              humio.HumioQuery{QueryString: "loglevel=ERROR", Start: "1h"},
+		ThrottleTimeMillis: 60000,
+		Notifiers:          []string{ids[0], ids[2], "deleted"},
+	}
+	if err := client.createAlert(context.Background(), "sandbox", &alert); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := exportCluster(context.Background(), client, []string{"sandbox"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `resource "humio_repository" "sandbox" {
+  name = "sandbox"
+  retention {
+    ingest_size_in_gb  = 0
+    storage_size_in_gb = 0
+    time_in_days       = 0
+  }
+}
+
+import {
+  to = humio_repository.sandbox
+  id = "sandbox"
+}
+
+resource "humio_notifier" "sandbox_ops_slack" {
+  email {
+    recipients = ["ops@example.com"]
+  }
+  entity     = "EmailNotifier"
+  name       = "ops slack"
+  repository = "sandbox"
+}
+
+import {
+  to = humio_notifier.sandbox_ops_slack
+  id = "sandbox+ops slack"
+}
+
+resource "humio_notifier" "sandbox_ops_slack_2" {
+  email {
+    recipients = ["ops@example.com"]
+  }
+  entity     = "EmailNotifier"
+  name       = "ops_slack"
+  repository = "sandbox"
+}
+
+import {
+  to = humio_notifier.sandbox_ops_slack_2
+  id = "sandbox+ops_slack"
+}
+
+resource "humio_notifier" "sandbox_ops_slack_2_2" {
+  email {
+    recipients = ["ops@example.com"]
+  }
+  entity     = "EmailNotifier"
+  name       = "ops_slack_2"
+  repository = "sandbox"
+}
+
+import {
+  to = humio_notifier.sandbox_ops_slack_2_2
+  id = "sandbox+ops_slack_2"
+}
+
+resource "humio_alert" "sandbox_errors" {
+  name                 = "errors"
+  notifiers            = [humio_notifier.sandbox_ops_slack.notifier_id, humio_notifier.sandbox_ops_slack_2_2.notifier_id, "deleted"]
+  query                = "loglevel=ERROR"
+  repository           = "sandbox"
+  start                = "1h"
+  throttle_time_millis = 60000
+}
+
+import {
+  to = humio_alert.sandbox_errors
+  id = "sandbox+errors"
+}
+
+`
+	got := string(hclwrite.Format(f.Bytes()))
+	if !cmp.Equal(want, got) {
+		t.Error(cmp.Diff(want, got))
+	}
+
+	checkExportPlanIsEmpty(t, client, f.Bytes())
+}
+
+// checkExportPlanIsEmpty runs the import blocks of the generated file against the cluster and checks that planning the
+// generated resources against the imported state shows no changes, like running terraform plan on the export would.
+func checkExportPlanIsEmpty(t *testing.T, client *apiClient, src []byte) {
+	t.Helper()
+
+	file, diags := hclsyntax.ParseConfig(src, "export.tf", hcl.InitialPos)
+	if diags.HasErrors() {
+		t.Fatal(diags)
+	}
+	blocks := file.Body.(*hclsyntax.Body).Blocks
+	resources := Provider().ResourcesMap
+
+	// Import every resource first, so references between the generated resources can be resolved from the state.
+	states := map[string]*terraform.InstanceState{}
+	variables := map[string]map[string]cty.Value{}
+	for _, block := range blocks {
+		if block.Type != "import" {
+			continue
+		}
+		to, diags := hcl.AbsTraversalForExpr(block.Body.Attributes["to"].Expr)
+		if diags.HasErrors() {
+			t.Fatal(diags)
+		}
+		id, diags := block.Body.Attributes["id"].Expr.Value(nil)
+		if diags.HasErrors() {
+			t.Fatal(diags)
+		}
+		resourceType, label := to.RootName(), to[1].(hcl.TraverseAttr).Name
+
+		r := resources[resourceType]
+		d := r.Data(nil)
+		d.SetId(id.AsString())
+		imported, err := r.Importer.StateContext(context.Background(), d, client)
+		if err != nil {
+			t.Fatalf("could not import %s.%s: %s", resourceType, label, err)
+		}
+		state, readDiags := r.RefreshWithoutUpgrade(context.Background(), imported[0].State(), client)
+		if readDiags.HasError() || state == nil {
+			t.Fatalf("could not read %s.%s: %s", resourceType, label, diagnosticsToString(readDiags))
+		}
+		states[resourceType+"."+label] = state
+
+		attributes := map[string]cty.Value{}
+		for k, v := range state.Attributes {
+			attributes[k] = cty.StringVal(v)
+		}
+		if variables[resourceType] == nil {
+			variables[resourceType] = map[string]cty.Value{}
+		}
+		variables[resourceType][label] = cty.ObjectVal(attributes)
+	}
+
+	evalCtx := &hcl.EvalContext{Variables: map[string]cty.Value{}}
+	for resourceType, labels := range variables {
+		evalCtx.Variables[resourceType] = cty.ObjectVal(labels)
+	}
+
+	for _, block := range blocks {
+		if block.Type != "resource" {
+			continue
+		}
+		address := block.Labels[0] + "." + block.Labels[1]
+		r := resources[block.Labels[0]]
+		config := terraform.NewResourceConfigRaw(hclBodyToMap(t, block.Body, evalCtx))
+		if diags := r.Validate(config); diags.HasError() {
+			t.Errorf("invalid configuration for %s: %s", address, diagnosticsToString(diags))
+			continue
+		}
+		diff, err := r.Diff(context.Background(), states[address], config, client)
+		if err != nil {
+			t.Errorf("could not plan %s: %s", address, err)
+			continue
+		}
+		if !diff.Empty() {
+			t.Errorf("plan for %s is not empty: %v", address, diff)
+		}
+	}
+}
+
+// hclBodyToMap evaluates the attributes and nested blocks of body into the raw configuration map that Terraform would
+// hand to the provider.
+func hclBodyToMap(t *testing.T, body *hclsyntax.Body, evalCtx *hcl.EvalContext) map[string]interface{} {
+	m := map[string]interface{}{}
+	for name, attr := range body.Attributes {
+		value, diags := attr.Expr.Value(evalCtx)
+		if diags.HasErrors() {
+			t.Fatal(diags)
+		}
+		m[name] = ctyToRaw(value)
+	}
+	for _, block := range body.Blocks {
+		items, _ := m[block.Type].([]interface{})
+		m[block.Type] = append(items, hclBodyToMap(t, block.Body, evalCtx))
+	}
+	return m
+}
+
+func ctyToRaw(value cty.Value) interface{} {
+	switch {
+	case value.IsNull():
+		return nil
+	case value.Type() == cty.String:
+		return value.AsString()
+	case value.Type() == cty.Bool:
+		return value.True()
+	case value.Type() == cty.Number:
+		if i, accuracy := value.AsBigFloat().Int64(); accuracy == big.Exact {
+			return int(i)
+		}
+		f, _ := value.AsBigFloat().Float64()
+		return f
+	case value.Type().IsObjectType() || value.Type().IsMapType():
+		m := map[string]interface{}{}
+		for k, v := range value.AsValueMap() {
+			m[k] = ctyToRaw(v)
+		}
+		return m
+	}
+	var items []interface{}
+	for _, v := range value.AsValueSlice() {
+		items = append(items, ctyToRaw(v))
+	}
+	return items
+}
+
+func TestUniqueLabel(t *testing.T) {
+	e := newExporter()
+	for _, c := range []struct {
+		name string
+		want string
+	}{
+		{"sandbox", "sandbox"},
+		{"sandbox", "sandbox_2"},
+		{"sandbox_2", "sandbox_2_2"},
+		{"sandbox_3", "sandbox_3"},
+		{"sandbox", "sandbox_4"},
+		{"ops slack", "ops_slack"},
+		{"ops/slack", "ops_slack_2"},
+		{"1st", "_1st"},
+		{"-x", "_-x"},
+		{"", "_"},
+		{"", "__2"},
+	} {
+		if got := e.uniqueLabel(c.name); got != c.want {
+			t.Errorf("uniqueLabel(%q) = %q, want %q", c.name, got, c.want)
+		}
+	}
+}
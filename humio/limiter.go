@@ -0,0 +1,156 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// requestLimiter limits the requests sent to Humio by all resources, which Terraform manages in parallel.
+type requestLimiter struct {
+	// slots holds a value for every request in flight. It is nil if the number of concurrent requests is unlimited.
+	slots chan struct{}
+	rate  *rate.Limiter
+
+	mu          sync.Mutex
+	pausedUntil time.Time
+}
+
+// newRequestLimiter returns a limiter allowing at most maxConcurrent requests in flight and requestsPerSecond requests
+// to be started per second. Zero means no limit for either.
+func newRequestLimiter(maxConcurrent int, requestsPerSecond float64) *requestLimiter {
+	l := &requestLimiter{
+		rate: rate.NewLimiter(rate.Inf, 1),
+	}
+	if maxConcurrent > 0 {
+		l.slots = make(chan struct{}, maxConcurrent)
+	}
+	if requestsPerSecond > 0 {
+		l.rate = rate.NewLimiter(rate.Limit(requestsPerSecond), 1)
+	}
+	return l
+}
+
+// acquire waits until a request can be sent. Every successful call must be followed by a call to release.
+func (l *requestLimiter) acquire(ctx context.Context) error {
+	l.mu.Lock()
+	pause := time.Until(l.pausedUntil)
+	l.mu.Unlock()
+	if pause > 0 {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(pause):
+		}
+	}
+
+	if err := l.rate.Wait(ctx); err != nil {
+		return err
+	}
+	if l.slots != nil {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case l.slots <- struct{}{}:
+		}
+	}
+	return nil
+}
+
+func (l *requestLimiter) release() {
+	if l.slots != nil {
+		<-l.slots
+	}
+}
+
+// pause holds back every request until the given time, e.g. when Humio asks clients to back off.
+func (l *requestLimiter) pause(until time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if until.After(l.pausedUntil) {
+		l.pausedUntil = until
+	}
+}
+
+// limitedTransport sends requests through a requestLimiter. Requests rejected with 429 Too Many Requests are sent
+// again, up to maxAttempts times, after the wait given by the Retry-After header, or after the backoff used by
+// apiClient.retry if there is none. Humio has not acted on such requests, so this is also safe for creates and deletes.
+// This is the only place 429 is retried.
+type limitedTransport struct {
+	base        http.RoundTripper
+	limiter     *requestLimiter
+	maxAttempts int
+	maxWait     time.Duration
+}
+
+func (t *limitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 1; ; attempt++ {
+		if err := t.limiter.acquire(req.Context()); err != nil {
+			return nil, err
+		}
+		resp, err := t.base.RoundTrip(req)
+		t.limiter.release()
+		if err != nil || resp.StatusCode != http.StatusTooManyRequests || attempt >= t.maxAttempts {
+			return resp, err
+		}
+		if req.Body != nil && req.GetBody == nil {
+			return resp, err
+		}
+		wait, ok := retryAfter(resp.Header.Get("Retry-After"), time.Now())
+		if !ok {
+			wait = retryBackoff(attempt, t.maxWait)
+		}
+		if wait > t.maxWait {
+			wait = t.maxWait
+		}
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				resp.Body.Close()
+				return nil, err
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+		resp.Body.Close()
+
+		log.Printf("[WARN] %s %s was rate limited by Humio, retrying in %s", req.Method, req.URL.Path, wait)
+		t.limiter.pause(time.Now().Add(wait))
+	}
+}
+
+// retryAfter parses a Retry-After header, which is either a number of seconds or an HTTP date.
+func retryAfter(header string, now time.Time) (time.Duration, bool) {
+	if header == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(header); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(header); err == nil {
+		if wait := date.Sub(now); wait > 0 {
+			return wait, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
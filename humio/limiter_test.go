@@ -0,0 +1,231 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2020, 11, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		header string
+		want   time.Duration
+		ok     bool
+	}{
+		{header: "", ok: false},
+		{header: "3", want: 3 * time.Second, ok: true},
+		{header: "0", want: 0, ok: true},
+		{header: "Sun, 01 Nov 2020 12:00:10 GMT", want: 10 * time.Second, ok: true},
+		{header: "Sun, 01 Nov 2020 11:59:00 GMT", want: 0, ok: true},
+		{header: "soon", ok: false},
+		{header: "-1", ok: false},
+	}
+	for _, test := range tests {
+		got, ok := retryAfter(test.header, now)
+		if got != test.want || ok != test.ok {
+			t.Errorf("retryAfter(%q) = %s, %v, want %s, %v", test.header, got, ok, test.want, test.ok)
+		}
+	}
+}
+
+func TestLimitedTransportRetryAfter(t *testing.T) {
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		if len(bodies) < 3 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: &limitedTransport{
+		base:        http.DefaultTransport,
+		limiter:     newRequestLimiter(0, 0),
+		maxAttempts: 3,
+		maxWait:     time.Second,
+	}}
+	resp, err := client.Post(server.URL, "application/json", strings.NewReader(`{"query": "mutation"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("unexpected status %s", resp.Status)
+	}
+	if len(bodies) != 3 || bodies[2] != `{"query": "mutation"}` {
+		t.Errorf("expected the request to be sent 3 times with its body, got %q", bodies)
+	}
+
+	bodies = nil
+	client.Transport.(*limitedTransport).maxAttempts = 2
+	resp, err = client.Post(server.URL, "application/json", strings.NewReader(`{}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusTooManyRequests {
+		t.Errorf("expected 429 after running out of attempts, got %s", resp.Status)
+	}
+}
+
+func TestLimitedTransportRetriesWithoutRetryAfter(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts < 2 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: &limitedTransport{
+		base:        http.DefaultTransport,
+		limiter:     newRequestLimiter(0, 0),
+		maxAttempts: 3,
+		maxWait:     time.Millisecond,
+	}}
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || attempts != 2 {
+		t.Errorf("expected a 429 without Retry-After to be retried with backoff, got %s after %d attempts", resp.Status, attempts)
+	}
+}
+
+func TestLimitedTransportConcurrency(t *testing.T) {
+	var mu sync.Mutex
+	var inFlight, maxInFlight int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		inFlight++
+		if inFlight > maxInFlight {
+			maxInFlight = inFlight
+		}
+		mu.Unlock()
+		time.Sleep(10 * time.Millisecond)
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: &limitedTransport{
+		base:        http.DefaultTransport,
+		limiter:     newRequestLimiter(2, 0),
+		maxAttempts: 1,
+	}}
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := client.Get(server.URL)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			resp.Body.Close()
+		}()
+	}
+	wg.Wait()
+	if maxInFlight > 2 {
+		t.Errorf("expected at most 2 concurrent requests, got %d", maxInFlight)
+	}
+}
+
+func TestLimitedTransportRate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	client := &http.Client{Transport: &limitedTransport{
+		base:        http.DefaultTransport,
+		limiter:     newRequestLimiter(0, 20),
+		maxAttempts: 1,
+	}}
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		resp, err := client.Get(server.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+	if elapsed := time.Since(start); elapsed < 150*time.Millisecond {
+		t.Errorf("expected 5 requests at 20 per second to take at least 200ms, took %s", elapsed)
+	}
+}
+
+// TestLimitedTransportAlertErrors checks that listing alerts fails with an error when Humio keeps answering 429, or
+// when the operation is cancelled or times out while it waits for the limiter.
+func TestLimitedTransportAlertErrors(t *testing.T) {
+	var attempts int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/repositories/x/alerts" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		attempts++
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	address, _ := url.Parse(server.URL)
+	transport := &limitedTransport{
+		base:        http.DefaultTransport,
+		limiter:     newRequestLimiter(0, 0),
+		maxAttempts: 3,
+		maxWait:     time.Millisecond,
+	}
+	c := newAPIClient(address, "secret", transport)
+	c.retryMaxAttempts, c.retryMaxWait = 3, time.Millisecond
+
+	_, err := c.listAlerts(context.Background(), "x")
+	if err == nil || !strings.Contains(err.Error(), "429") || attempts != 3 {
+		t.Errorf("expected an error after 3 attempts rejected with 429, got %v after %d attempts", err, attempts)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := c.listAlerts(ctx, "x"); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected a cancelled operation to fail with context.Canceled, got %v", err)
+	}
+
+	// Humio asks for a minute of back-off, which outlasts the operation.
+	transport.maxWait = time.Minute
+	ctx, cancel = context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := c.listAlerts(ctx, "x"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected an operation timing out while rate limited to fail with context.DeadlineExceeded, got %v", err)
+	}
+}
@@ -0,0 +1,186 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// maxLoggedBodySize is the size at which logged request bodies are truncated.
+const maxLoggedBodySize = 4096
+
+// redactedKeys are the JSON keys and GraphQL arguments holding secrets, compared case-insensitively.
+var redactedKeys = []string{
+	"token",
+	"apiToken",
+	"api_token",
+	"ingestToken",
+	"ingest_token",
+	"genieKey",
+	"genie_key",
+	"routingKey",
+	"routing_key",
+	"password",
+	"authorization",
+}
+
+var (
+	rxGraphQLOperation = regexp.MustCompile(`^\s*(query|mutation)\b\s*([_A-Za-z][_0-9A-Za-z]*)?[^{]*\{\s*([_A-Za-z][_0-9A-Za-z]*)`)
+	rxGraphQLSecret    = regexp.MustCompile(`(?i)\b(` + strings.Join(redactedKeys, "|") + `)(\s*:\s*)"(?:[^"\\]|\\.)*"`)
+)
+
+// loggingTransport logs every request sent to Humio at debug level, which is shown when running Terraform with
+// TF_LOG=DEBUG. Secrets in request bodies are redacted, and headers and response bodies are never logged.
+type loggingTransport struct {
+	base http.RoundTripper
+}
+
+func (t *loggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	operation, body := describeRequestBody(req)
+	if operation != "" {
+		operation = " " + operation
+	}
+
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	latency := time.Since(start).Round(time.Millisecond)
+
+	if err != nil {
+		log.Printf("[DEBUG] Humio API %s %s%s failed after %s: %s", req.Method, req.URL.Path, operation, latency, err)
+		return resp, err
+	}
+	log.Printf("[DEBUG] Humio API %s %s%s: %s in %s", req.Method, req.URL.Path, operation, resp.Status, latency)
+	if body != "" {
+		log.Printf("[DEBUG] Humio API %s %s%s request body: %s", req.Method, req.URL.Path, operation, body)
+	}
+	return resp, err
+}
+
+// describeRequestBody returns the GraphQL operation and the redacted body of a JSON request. The body of req is left
+// for the request to be sent.
+func describeRequestBody(req *http.Request) (string, string) {
+	if req.Body == nil || !strings.HasPrefix(req.Header.Get("Content-Type"), "application/json") {
+		return "", ""
+	}
+
+	var raw []byte
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return "", ""
+		}
+		raw, err = ioutil.ReadAll(body)
+		body.Close()
+		if err != nil {
+			return "", ""
+		}
+	} else {
+		var err error
+		raw, err = ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		req.Body = ioutil.NopCloser(bytes.NewReader(raw))
+		if err != nil {
+			return "", ""
+		}
+	}
+	if len(raw) == 0 {
+		return "", ""
+	}
+
+	var operation string
+	var graphQL struct {
+		Query string `json:"query"`
+	}
+	if json.Unmarshal(raw, &graphQL) == nil && graphQL.Query != "" {
+		operation = graphQLOperationName(graphQL.Query)
+	}
+
+	body := redactJSON(raw)
+	if len(body) > maxLoggedBodySize {
+		body = body[:maxLoggedBodySize] + "... (truncated)"
+	}
+	return operation, body
+}
+
+// graphQLOperationName returns the type and name of a GraphQL operation, e.g. "mutation createAlert". Anonymous
+// operations are named after their first field, as the GraphQL client used by humio.Client does not name them.
+func graphQLOperationName(query string) string {
+	m := rxGraphQLOperation.FindStringSubmatch(query)
+	if m == nil {
+		return ""
+	}
+	if m[2] != "" {
+		return m[1] + " " + m[2]
+	}
+	return m[1] + " " + m[3]
+}
+
+// redactJSON returns the JSON document with the values of redactedKeys replaced, including secrets passed as literal
+// arguments in a GraphQL query. Documents that are not valid JSON are left out entirely.
+func redactJSON(raw []byte) string {
+	var v interface{}
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return "(not JSON)"
+	}
+	redacted, err := json.Marshal(redactValue(v))
+	if err != nil {
+		return "(not JSON)"
+	}
+	return string(redacted)
+}
+
+func redactValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		// Webhook headers are given as a list of header and value pairs.
+		if header, ok := v["header"].(string); ok && isRedactedKey(header) {
+			v["value"] = "REDACTED"
+			return v
+		}
+		for key, value := range v {
+			switch {
+			case isRedactedKey(key):
+				v[key] = "REDACTED"
+			case key == "query":
+				if query, ok := value.(string); ok {
+					v[key] = rxGraphQLSecret.ReplaceAllString(query, `$1$2"REDACTED"`)
+				}
+			default:
+				v[key] = redactValue(value)
+			}
+		}
+	case []interface{}:
+		for i, value := range v {
+			v[i] = redactValue(value)
+		}
+	}
+	return v
+}
+
+func isRedactedKey(key string) bool {
+	for _, redacted := range redactedKeys {
+		if strings.EqualFold(key, redacted) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,155 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"bytes"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestGraphQLOperationName(t *testing.T) {
+	tests := map[string]string{
+		`query($repository: String!) { searchDomain(name: $repository) { actions { id } } }`:   "query searchDomain",
+		`mutation($input: CreateWebhookAction!) { createWebhookAction(input: $input) { id } }`: "mutation createWebhookAction",
+		`mutation RemoveParser { removeParser(input: {name: "p"}) { __typename } }`:            "mutation RemoveParser",
+		`{ currentUser { id } }`: "",
+	}
+	for query, want := range tests {
+		if got := graphQLOperationName(query); got != want {
+			t.Errorf("graphQLOperationName(%q) = %q, want %q", query, got, want)
+		}
+	}
+}
+
+func TestRedactJSON(t *testing.T) {
+	tests := []struct {
+		name   string
+		body   string
+		secret string
+	}{
+		{
+			name:   "notifier",
+			body:   `{"name": "opsgenie", "entity": "OpsGenieNotifier", "properties": {"apiUrl": "https://api.opsgenie.com", "genieKey": "s3cret"}}`,
+			secret: "s3cret",
+		},
+		{
+			name:   "action variables",
+			body:   `{"query": "mutation($input: CreateSlackPostMessageAction!) { createSlackPostMessageAction(input: $input) { id } }", "variables": {"input": {"name": "slack", "apiToken": "xoxb-s3cret", "channels": ["#ops"]}}}`,
+			secret: "xoxb-s3cret",
+		},
+		{
+			name:   "ingest token",
+			body:   `{"query": "query { repository(name: \"sandbox\") { ingestTokens { name token } } }", "variables": {"ingestToken": "s3cret"}}`,
+			secret: "s3cret",
+		},
+		{
+			name:   "literal argument",
+			body:   `{"query": "mutation { addIngestToken(repositoryName: \"sandbox\", name: \"t\", token: \"s3cret\") { name } }"}`,
+			secret: "s3cret",
+		},
+		{
+			name:   "webhook headers",
+			body:   `{"variables": {"input": {"headers": [{"header": "Authorization", "value": "Bearer s3cret"}, {"header": "Content-Type", "value": "application/json"}]}}}`,
+			secret: "s3cret",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := redactJSON([]byte(test.body))
+			if strings.Contains(got, test.secret) {
+				t.Errorf("secret was not redacted: %s", got)
+			}
+			if !strings.Contains(got, "REDACTED") {
+				t.Errorf("expected REDACTED in %s", got)
+			}
+		})
+	}
+
+	got := redactJSON([]byte(`{"variables": {"input": {"headers": [{"header": "Content-Type", "value": "application/json"}]}}}`))
+	if !strings.Contains(got, "application/json") {
+		t.Errorf("unexpected redaction of %s", got)
+	}
+	if got := redactJSON([]byte("token=s3cret")); got != "(not JSON)" {
+		t.Errorf("expected bodies that are not JSON to be left out, got %s", got)
+	}
+}
+
+func TestLoggingTransport(t *testing.T) {
+	var received string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		received = string(body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	defer log.SetOutput(os.Stderr)
+
+	body := `{"query": "mutation($input: CreatePagerDutyAction!) { createPagerDutyAction(input: $input) { id } }", "variables": {"input": {"routingKey": "s3cret"}}}`
+	client := &http.Client{Transport: &loggingTransport{base: http.DefaultTransport}}
+	resp, err := client.Post(server.URL+"/graphql", "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if received != body {
+		t.Errorf("expected the request body to be sent unchanged, got %s", received)
+	}
+	out := logs.String()
+	for _, want := range []string{"[DEBUG] Humio API POST /graphql mutation createPagerDutyAction: 200 OK in", "REDACTED"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in logs:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "s3cret") {
+		t.Errorf("secret was logged:\n%s", out)
+	}
+}
+
+func TestLoggingTransportWithoutBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	defer log.SetOutput(os.Stderr)
+
+	req, err := http.NewRequest(http.MethodGet, server.URL+"/api/v1/repositories/sandbox/alerts", http.NoBody)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	client := &http.Client{Transport: &loggingTransport{base: http.DefaultTransport}}
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if out := logs.String(); strings.Contains(out, "request body") {
+		t.Errorf("expected no request body to be logged for a request without a body:\n%s", out)
+	}
+}
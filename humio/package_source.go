@@ -0,0 +1,178 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// packageSource is the content of a Humio package, read from either a zip file or a directory.
+type packageSource struct {
+	// Name is the unversioned package ID from the manifest, e.g. myorg/mypackage.
+	Name    string
+	Version string
+	// Files maps the path of every file in the package to its content. Files and directories starting with _ or . are
+	// left out, as they are when Humio packages a directory.
+	Files map[string][]byte
+}
+
+// readPackageSource reads the package at source, which is either a zip file or a directory.
+func readPackageSource(source string) (*packageSource, error) {
+	info, err := os.Stat(source)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &packageSource{Files: map[string][]byte{}}
+	if info.IsDir() {
+		err = p.readDirectory(source, "")
+	} else {
+		err = p.readZip(source)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	manifest, ok := p.Files["manifest.yaml"]
+	if !ok {
+		return nil, fmt.Errorf("%s does not contain a manifest.yaml", source)
+	}
+	var m struct {
+		Name    string `yaml:"name"`
+		Version string `yaml:"version"`
+	}
+	if err := yaml.Unmarshal(manifest, &m); err != nil {
+		return nil, fmt.Errorf("could not parse manifest.yaml: %s", err)
+	}
+	if m.Name == "" {
+		return nil, fmt.Errorf("manifest.yaml in %s does not specify the name of the package", source)
+	}
+	p.Name, p.Version = m.Name, m.Version
+	return p, nil
+}
+
+func (p *packageSource) readDirectory(dir, prefix string) error {
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if isSkippedPackagePath(entry.Name()) {
+			continue
+		}
+		if entry.IsDir() {
+			if err := p.readDirectory(filepath.Join(dir, entry.Name()), path.Join(prefix, entry.Name())); err != nil {
+				return err
+			}
+			continue
+		}
+		b, err := ioutil.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return err
+		}
+		p.Files[path.Join(prefix, entry.Name())] = b
+	}
+	return nil
+}
+
+func (p *packageSource) readZip(file string) error {
+	r, err := zip.OpenReader(file)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	for _, f := range r.File {
+		if f.FileInfo().IsDir() || isSkippedPackagePath(f.Name) {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return err
+		}
+		b, err := ioutil.ReadAll(rc)
+		_ = rc.Close()
+		if err != nil {
+			return err
+		}
+		p.Files[f.Name] = b
+	}
+	return nil
+}
+
+// isSkippedPackagePath reports whether a path in a package is left out because it, or one of the directories it is in,
+// starts with _ or .
+func isSkippedPackagePath(name string) bool {
+	for _, part := range strings.Split(name, "/") {
+		if strings.HasPrefix(part, "_") || strings.HasPrefix(part, ".") {
+			return true
+		}
+	}
+	return false
+}
+
+// ContentHash returns a hash of the paths and contents of the files in the package, which is the same whether the
+// package is read from a directory or from a zip file of that directory.
+func (p *packageSource) ContentHash() string {
+	h := sha256.New()
+	for _, name := range p.paths() {
+		_, _ = io.WriteString(h, name)
+		_, _ = h.Write([]byte{0})
+		_, _ = h.Write(p.Files[name])
+		_, _ = h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// archive returns a zip file of the package, as sent to Humio to install it.
+func (p *packageSource) archive() ([]byte, error) {
+	var b bytes.Buffer
+	w := zip.NewWriter(&b)
+	for _, name := range p.paths() {
+		f, err := w.Create(name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := f.Write(p.Files[name]); err != nil {
+			return nil, err
+		}
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+func (p *packageSource) paths() []string {
+	paths := make([]string, 0, len(p.Files))
+	for name := range p.Files {
+		paths = append(paths, name)
+	}
+	sort.Strings(paths)
+	return paths
+}
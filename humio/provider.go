@@ -16,16 +16,17 @@ package humio
 
 import (
 	"context"
-	"encoding/pem"
 	"fmt"
+	"log"
+	"net/http"
 	"net/url"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-
-	humio "github.com/humio/cli/api"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 // tfMap is a shorthand alias for convenience; Terraform uses this type a *lot*.
@@ -40,30 +41,73 @@ func Provider() *schema.Provider {
 			if err != nil {
 				return nil, diag.FromErr(err)
 			}
-			caBundlePEM, ok := r.GetOk("ca_certificate_pem")
-			if ok {
-				pem, _ := pem.Decode([]byte(caBundlePEM.(string)))
-				if pem == nil {
-					return nil, diag.FromErr(fmt.Errorf("ca_certificate_pem specified but no pem was found"))
-				}
-				return humio.NewClient(humio.Config{
-					Address:          url,
-					Token:            r.Get("api_token").(string),
-					CACertificatePEM: caBundlePEM.(string),
-				}), diagnostics
+			token, err := apiTokenFromResourceData(ctx, r)
+			if err != nil {
+				return nil, diag.FromErr(err)
+			}
+			transportConfig, err := transportConfigFromResourceData(r)
+			if err != nil {
+				return nil, diag.FromErr(err)
+			}
+			transport, err := newTransport(transportConfig)
+			if err != nil {
+				return nil, diag.FromErr(err)
+			}
+			retryMaxWait, err := time.ParseDuration(r.Get("retry_max_wait").(string))
+			if err != nil {
+				return nil, diag.FromErr(err)
 			}
 
-			return humio.NewClient(humio.Config{
-				Address: url,
-				Token:   r.Get("api_token").(string),
-			}), diagnostics
+			retryMaxAttempts := r.Get("retry_max_attempts").(int)
+			limiter := newRequestLimiter(
+				r.Get("max_concurrent_requests").(int),
+				r.Get("requests_per_second").(float64),
+			)
+			var base http.RoundTripper = transport
+			if wrapAPITransport != nil {
+				base = wrapAPITransport(base)
+			}
+			client := newAPIClient(url, token, &limitedTransport{
+				base:        &loggingTransport{base: base},
+				limiter:     limiter,
+				maxAttempts: retryMaxAttempts,
+				maxWait:     retryMaxWait,
+			})
+			client.retryMaxAttempts = retryMaxAttempts
+			client.retryMaxWait = retryMaxWait
+			client.limiter = limiter
+			client.cache = &listCache{}
+			if r.Get("skip_version_check").(bool) {
+				log.Printf("[INFO] skipping Humio version check, resources will not be checked against the version of %s", addr)
+				return client, diagnostics
+			}
+			if err := client.detectServerVersion(ctx); err != nil {
+				return nil, append(diagnostics, diag.Diagnostic{
+					Severity: diag.Error,
+					Summary:  "Could not determine Humio version",
+					Detail:   fmt.Sprintf("Could not get the version of the Humio cluster at %s: %s. Set skip_version_check to use the provider without contacting Humio when it is configured.", addr, err),
+				})
+			}
+			log.Printf("[INFO] Humio cluster at %s runs Humio %s", addr, client.version)
+			return client, diagnostics
 		},
 		ResourcesMap: map[string]*schema.Resource{
-			"humio_alert":        resourceAlert(),
-			"humio_ingest_token": resourceIngestToken(),
-			"humio_notifier":     resourceNotifier(),
-			"humio_parser":       resourceParser(),
-			"humio_repository":   resourceRepository(),
+			"humio_action":                resourceAction(),
+			"humio_alert":                 resourceAlert(),
+			"humio_dashboard":             resourceDashboard(),
+			"humio_file":                  resourceFile(),
+			"humio_group":                 resourceGroup(),
+			"humio_group_role_assignment": resourceGroupRoleAssignment(),
+			"humio_ingest_token":          resourceIngestToken(),
+			"humio_notifier":              resourceNotifier(),
+			"humio_package":               resourcePackage(),
+			"humio_parser":                resourceParser(),
+			"humio_repository":            resourceRepository(),
+			"humio_role":                  resourceRole(),
+			"humio_saved_query":           resourceSavedQuery(),
+			"humio_scheduled_search":      resourceScheduledSearch(),
+			"humio_user":                  resourceUser(),
+			"humio_view":                  resourceView(),
 		},
 		Schema: map[string]*schema.Schema{
 			"addr": {
@@ -73,20 +117,139 @@ func Provider() *schema.Provider {
 				ValidateDiagFunc: validateURL,
 			},
 			"api_token": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Sensitive:     true,
+				ConflictsWith: []string{"api_token_file", "api_token_command"},
+			},
+			"api_token_file": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"api_token", "api_token_command"},
+			},
+			"api_token_command": {
+				Type:          schema.TypeList,
+				Optional:      true,
+				MinItems:      1,
+				Elem:          &schema.Schema{Type: schema.TypeString},
+				ConflictsWith: []string{"api_token", "api_token_file"},
+			},
+			"ca_certificate_pem": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				DefaultFunc:      schema.EnvDefaultFunc("HUMIO_CA_CERTIFICATE_PEM", nil),
+				ConflictsWith:    []string{"ca_certificate_file"},
+				ValidateDiagFunc: validateCertificateBundle,
+			},
+			"ca_certificate_file": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				DefaultFunc:   schema.EnvDefaultFunc("HUMIO_CA_CERTIFICATE_FILE", nil),
+				ConflictsWith: []string{"ca_certificate_pem"},
+			},
+			"client_certificate_pem": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				DefaultFunc:   schema.EnvDefaultFunc("HUMIO_CLIENT_CERTIFICATE_PEM", nil),
+				ConflictsWith: []string{"client_certificate_file"},
+			},
+			"client_certificate_file": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				DefaultFunc:   schema.EnvDefaultFunc("HUMIO_CLIENT_CERTIFICATE_FILE", nil),
+				ConflictsWith: []string{"client_certificate_pem"},
+			},
+			"client_key_pem": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Sensitive:     true,
+				DefaultFunc:   schema.EnvDefaultFunc("HUMIO_CLIENT_KEY_PEM", nil),
+				ConflictsWith: []string{"client_key_file"},
+			},
+			"client_key_file": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				DefaultFunc:   schema.EnvDefaultFunc("HUMIO_CLIENT_KEY_FILE", nil),
+				ConflictsWith: []string{"client_key_pem"},
+			},
+			"tls_server_name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("HUMIO_TLS_SERVER_NAME", nil),
+			},
+			"proxy_url": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				DefaultFunc:      schema.EnvDefaultFunc("HUMIO_PROXY_URL", nil),
+				ValidateDiagFunc: validateURL,
+			},
+			"proxy_username": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("HUMIO_PROXY_USERNAME", nil),
+			},
+			"proxy_password": {
 				Type:        schema.TypeString,
-				Required:    true,
+				Optional:    true,
 				Sensitive:   true,
-				DefaultFunc: schema.EnvDefaultFunc("HUMIO_API_TOKEN", nil),
+				DefaultFunc: schema.EnvDefaultFunc("HUMIO_PROXY_PASSWORD", nil),
 			},
-			"ca_certificate_pem": {
+			"no_proxy": {
 				Type:        schema.TypeString,
 				Optional:    true,
-				DefaultFunc: schema.EnvDefaultFunc("HUMIO_CA_CERTIFICATE_PEM", nil),
+				DefaultFunc: schema.EnvDefaultFunc("HUMIO_NO_PROXY", nil),
+			},
+			"insecure_skip_verify": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("HUMIO_INSECURE_SKIP_VERIFY", false),
+			},
+			"retry_max_attempts": {
+				Type:             schema.TypeInt,
+				Optional:         true,
+				Default:          3,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.IntAtLeast(1)),
+			},
+			"retry_max_wait": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "30s",
+				ValidateDiagFunc: validateDuration,
+			},
+			"max_concurrent_requests": {
+				Type:             schema.TypeInt,
+				Optional:         true,
+				Default:          0,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.IntAtLeast(0)),
+			},
+			"requests_per_second": {
+				Type:             schema.TypeFloat,
+				Optional:         true,
+				Default:          0,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.FloatAtLeast(0)),
+			},
+			"skip_version_check": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("HUMIO_SKIP_VERSION_CHECK", false),
 			},
 		},
 	}
 }
 
+// defaultTimeout is the timeout of resource operations unless overridden in a timeouts block.
+const defaultTimeout = 5 * time.Minute
+
+// defaultTimeouts returns the create, update and delete timeouts used by every resource unless overridden in a
+// timeouts block.
+func defaultTimeouts() *schema.ResourceTimeout {
+	return &schema.ResourceTimeout{
+		Create: schema.DefaultTimeout(defaultTimeout),
+		Update: schema.DefaultTimeout(defaultTimeout),
+		Delete: schema.DefaultTimeout(defaultTimeout),
+	}
+}
+
 func validateURL(val interface{}, key cty.Path) diag.Diagnostics {
 	var diagnostics diag.Diagnostics
 	v := val.(string)
@@ -116,6 +279,41 @@ func validateURL(val interface{}, key cty.Path) diag.Diagnostics {
 	return diagnostics
 }
 
+func validateDuration(val interface{}, key cty.Path) diag.Diagnostics {
+	var diagnostics diag.Diagnostics
+	v := val.(string)
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		diagnostics = append(diagnostics, diag.Diagnostic{
+			Severity:      diag.Error,
+			Summary:       "Invalid duration",
+			Detail:        fmt.Sprintf("%s is not a valid duration, expected e.g. 30s or 2m", v),
+			AttributePath: key,
+		})
+	} else if d <= 0 {
+		diagnostics = append(diagnostics, diag.Diagnostic{
+			Severity:      diag.Error,
+			Summary:       "Invalid duration",
+			Detail:        fmt.Sprintf("%s must be a positive duration", v),
+			AttributePath: key,
+		})
+	}
+	return diagnostics
+}
+
+func validateCertificateBundle(val interface{}, key cty.Path) diag.Diagnostics {
+	var diagnostics diag.Diagnostics
+	if _, err := parseCertificateBundle([]byte(val.(string))); err != nil {
+		diagnostics = append(diagnostics, diag.Diagnostic{
+			Severity:      diag.Error,
+			Summary:       "Invalid certificate bundle",
+			Detail:        fmt.Sprintf("The PEM encoded certificate bundle is invalid: %s", err),
+			AttributePath: key,
+		})
+	}
+	return diagnostics
+}
+
 func parseRepositoryAndID(fullIdentifier string) [2]string {
 	var repository, id string
 	parts := strings.SplitN(fullIdentifier, "+", 2)
@@ -0,0 +1,664 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// actionBlocks maps the name of each action block to the type of action it configures.
+var actionBlocks = map[string]string{
+	"email":            actionTypeEmail,
+	"humiorepo":        actionTypeHumioRepo,
+	"opsgenie":         actionTypeOpsGenie,
+	"pagerduty":        actionTypePagerDuty,
+	"slack":            actionTypeSlack,
+	"slackpostmessage": actionTypeSlackPostMessage,
+	"upload_file":      actionTypeUploadFile,
+	"victorops":        actionTypeVictorOps,
+	"webhook":          actionTypeWebhook,
+}
+
+func actionBlockNames() []string {
+	var names []string
+	for name := range actionBlocks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func resourceAction() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceActionCreate,
+		ReadContext:   resourceActionRead,
+		UpdateContext: resourceActionUpdate,
+		DeleteContext: resourceActionDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Timeouts:      defaultTimeouts(),
+		CustomizeDiff: customizeDiffRequireServerVersion("humio_action", minActionsVersion, "humio_notifier"),
+
+		Schema: map[string]*schema.Schema{
+			"action_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"repository": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"email": {
+				Type:         schema.TypeList,
+				MaxItems:     1,
+				ExactlyOneOf: actionBlockNames(),
+				Optional:     true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"body_template": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"recipients": {
+							Type:     schema.TypeList,
+							Required: true,
+							MinItems: 1,
+							Elem: &schema.Schema{
+								Type:             schema.TypeString,
+								ValidateDiagFunc: validateEmail,
+							},
+						},
+						"subject_template": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"use_proxy": {
+							Type:     schema.TypeBool,
+							Optional: true,
+							Default:  true,
+						},
+					},
+				},
+			},
+			"humiorepo": {
+				Type:         schema.TypeList,
+				MaxItems:     1,
+				ExactlyOneOf: actionBlockNames(),
+				Optional:     true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"ingest_token": {
+							Type:      schema.TypeString,
+							Required:  true,
+							Sensitive: true,
+						},
+					},
+				},
+			},
+			"opsgenie": {
+				Type:         schema.TypeList,
+				MaxItems:     1,
+				ExactlyOneOf: actionBlockNames(),
+				Optional:     true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"api_url": {
+							Type:             schema.TypeString,
+							Optional:         true,
+							Default:          "https://api.opsgenie.com",
+							ValidateDiagFunc: validateURL,
+						},
+						"genie_key": {
+							Type:      schema.TypeString,
+							Required:  true,
+							Sensitive: true,
+						},
+						"use_proxy": {
+							Type:     schema.TypeBool,
+							Optional: true,
+							Default:  true,
+						},
+					},
+				},
+			},
+			"pagerduty": {
+				Type:         schema.TypeList,
+				MaxItems:     1,
+				ExactlyOneOf: actionBlockNames(),
+				Optional:     true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"routing_key": {
+							Type:      schema.TypeString,
+							Required:  true,
+							Sensitive: true,
+						},
+						"severity": {
+							Type:     schema.TypeString,
+							Required: true,
+							ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice([]string{
+								"critical",
+								"error",
+								"warning",
+								"info",
+							}, false)),
+						},
+						"use_proxy": {
+							Type:     schema.TypeBool,
+							Optional: true,
+							Default:  true,
+						},
+					},
+				},
+			},
+			"slack": {
+				Type:         schema.TypeList,
+				MaxItems:     1,
+				ExactlyOneOf: actionBlockNames(),
+				Optional:     true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"fields": {
+							Type:     schema.TypeMap,
+							Required: true,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+						"url": {
+							Type:             schema.TypeString,
+							Required:         true,
+							ValidateDiagFunc: validateURL,
+						},
+						"use_proxy": {
+							Type:     schema.TypeBool,
+							Optional: true,
+							Default:  true,
+						},
+					},
+				},
+			},
+			"slackpostmessage": {
+				Type:         schema.TypeList,
+				MaxItems:     1,
+				ExactlyOneOf: actionBlockNames(),
+				Optional:     true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"api_token": {
+							Type:      schema.TypeString,
+							Required:  true,
+							Sensitive: true,
+						},
+						"channels": {
+							Type:     schema.TypeList,
+							Required: true,
+							MinItems: 1,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+						"fields": {
+							Type:     schema.TypeMap,
+							Required: true,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+						"use_proxy": {
+							Type:     schema.TypeBool,
+							Optional: true,
+							Default:  true,
+						},
+					},
+				},
+			},
+			"upload_file": {
+				Type:         schema.TypeList,
+				MaxItems:     1,
+				ExactlyOneOf: actionBlockNames(),
+				Optional:     true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"file_name": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+					},
+				},
+			},
+			"victorops": {
+				Type:         schema.TypeList,
+				MaxItems:     1,
+				ExactlyOneOf: actionBlockNames(),
+				Optional:     true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"message_type": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"notify_url": {
+							Type:             schema.TypeString,
+							Required:         true,
+							ValidateDiagFunc: validateURL,
+						},
+						"use_proxy": {
+							Type:     schema.TypeBool,
+							Optional: true,
+							Default:  true,
+						},
+					},
+				},
+			},
+			"webhook": {
+				Type:         schema.TypeList,
+				MaxItems:     1,
+				ExactlyOneOf: actionBlockNames(),
+				Optional:     true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"body_template": {
+							Type:     schema.TypeString,
+							Optional: true,
+							Default:  defaultWebhookBodyTemplate,
+						},
+						"headers": {
+							Type:     schema.TypeMap,
+							Optional: true,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+						"ignore_ssl": {
+							Type:     schema.TypeBool,
+							Optional: true,
+							Default:  false,
+						},
+						"method": {
+							Type:     schema.TypeString,
+							Optional: true,
+							Default:  "POST",
+							ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice([]string{
+								http.MethodGet,
+								http.MethodPost,
+								http.MethodPut,
+							}, false)),
+						},
+						"url": {
+							Type:             schema.TypeString,
+							Required:         true,
+							ValidateDiagFunc: validateURL,
+						},
+						"use_proxy": {
+							Type:     schema.TypeBool,
+							Optional: true,
+							Default:  true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func resourceActionCreate(ctx context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+	if diags := requireServerVersion(client, "humio_action", minActionsVersion, "humio_notifier"); diags != nil {
+		return diags
+	}
+
+	a, err := actionFromResourceData(d)
+	if err != nil {
+		return diag.Errorf("could not obtain action from resource data: %s", err)
+	}
+
+	err = client.(*apiClient).call(ctx, func() error {
+		_, err := client.(*apiClient).createAction(ctx, d.Get("repository").(string), a)
+		return err
+	})
+	if err != nil {
+		return diag.Errorf("could not create action: %s", err)
+	}
+	d.SetId(fmt.Sprintf("%s+%s", d.Get("repository").(string), a.Name))
+
+	return resourceActionRead(ctx, d, client)
+}
+
+func resourceActionRead(ctx context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+	if diags := requireServerVersion(client, "humio_action", minActionsVersion, "humio_notifier"); diags != nil {
+		return diags
+	}
+
+	parts := parseRepositoryAndID(d.Id())
+	// If we don't have a repository when importing, we parse it from the ID.
+	if _, ok := d.GetOk("repository"); !ok {
+		if parts[0] == "" || parts[1] == "" {
+			return diag.Errorf("error importing humio_action. Please make sure the ID is in the form REPOSITORYNAME+ACTIONNAME (i.e. myRepoName+myActionName)")
+		}
+		err := d.Set("repository", parts[0])
+		if err != nil {
+			return diag.Errorf("error setting repository for resource %s: %s", d.Id(), err)
+		}
+		err = d.Set("name", parts[1])
+		if err != nil {
+			return diag.Errorf("error setting name for resource %s: %s", d.Id(), err)
+		}
+	}
+
+	var a *action
+	err := client.(*apiClient).retry(ctx, "get action", func() error {
+		var err error
+		a, err = client.(*apiClient).getAction(
+			ctx,
+			d.Get("repository").(string),
+			d.Get("name").(string),
+		)
+		return err
+	})
+	if removeFromStateIfNotFound(d, "humio_action", err) {
+		return nil
+	}
+	if err != nil {
+		return diag.Errorf("could not get action: %s", err)
+	}
+	return resourceDataFromAction(a, d)
+}
+
+func resourceActionUpdate(ctx context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+	if diags := requireServerVersion(client, "humio_action", minActionsVersion, "humio_notifier"); diags != nil {
+		return diags
+	}
+
+	a, err := actionFromResourceData(d)
+	if err != nil {
+		return diag.Errorf("could not obtain action from resource data: %s", err)
+	}
+
+	// Changing the type of an action is not supported by the API, so the old action is replaced.
+	if old, _ := d.GetChange("action_id"); a.Type != actionTypeOfResourceData(d, true) {
+		err = client.(*apiClient).call(ctx, func() error {
+			return client.(*apiClient).deleteAction(ctx, d.Get("repository").(string), old.(string))
+		})
+		if err != nil {
+			return diag.Errorf("could not delete action: %s", err)
+		}
+		return resourceActionCreate(ctx, d, client)
+	}
+
+	err = client.(*apiClient).retry(ctx, "update action", func() error {
+		return client.(*apiClient).updateAction(ctx, d.Get("repository").(string), a)
+	})
+	if err != nil {
+		return diag.Errorf("could not update action: %s", err)
+	}
+
+	return resourceActionRead(ctx, d, client)
+}
+
+func resourceActionDelete(ctx context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+	if diags := requireServerVersion(client, "humio_action", minActionsVersion, "humio_notifier"); diags != nil {
+		return diags
+	}
+
+	err := client.(*apiClient).call(ctx, func() error {
+		return client.(*apiClient).deleteAction(
+			ctx,
+			d.Get("repository").(string),
+			d.Get("action_id").(string),
+		)
+	})
+	if err != nil {
+		return diag.Errorf("could not delete action: %s", err)
+	}
+	return nil
+}
+
+// actionTypeOfResourceData returns the type of action configured in either the current state or, if old is false, the
+// new configuration.
+func actionTypeOfResourceData(d *schema.ResourceData, old bool) string {
+	for name, actionType := range actionBlocks {
+		v := d.Get(name)
+		if old {
+			v, _ = d.GetChange(name)
+		}
+		if len(v.([]interface{})) > 0 {
+			return actionType
+		}
+	}
+	return ""
+}
+
+func resourceDataFromAction(a *action, d *schema.ResourceData) diag.Diagnostics {
+	err := d.Set("action_id", a.ID)
+	if err != nil {
+		return diag.Errorf("could not set action_id for action: %s", err)
+	}
+	err = d.Set("name", a.Name)
+	if err != nil {
+		return diag.Errorf("could not set name for action: %s", err)
+	}
+
+	var block string
+	for name, actionType := range actionBlocks {
+		if actionType == a.Type {
+			block = name
+			continue
+		}
+		if err := d.Set(name, nil); err != nil {
+			return diag.Errorf("error setting %s settings for resource %s: %s", name, d.Id(), err)
+		}
+	}
+
+	var settings tfMap
+	p := a.Properties
+	switch a.Type {
+	case actionTypeEmail:
+		settings = tfMap{
+			"recipients":       p["recipients"],
+			"subject_template": p["subjectTemplate"],
+			"body_template":    p["bodyTemplate"],
+			"use_proxy":        p["useProxy"],
+		}
+	case actionTypeHumioRepo:
+		settings = tfMap{
+			"ingest_token": p["ingestToken"],
+		}
+	case actionTypeOpsGenie:
+		settings = tfMap{
+			"api_url":   p["apiUrl"],
+			"genie_key": p["genieKey"],
+			"use_proxy": p["useProxy"],
+		}
+	case actionTypePagerDuty:
+		settings = tfMap{
+			"routing_key": p["routingKey"],
+			"severity":    p["severity"],
+			"use_proxy":   p["useProxy"],
+		}
+	case actionTypeSlack:
+		settings = tfMap{
+			"url":       p["url"],
+			"fields":    mapFromKeyValueList(p["fields"], "fieldName"),
+			"use_proxy": p["useProxy"],
+		}
+	case actionTypeSlackPostMessage:
+		settings = tfMap{
+			"api_token": p["apiToken"],
+			"channels":  p["channels"],
+			"fields":    mapFromKeyValueList(p["fields"], "fieldName"),
+			"use_proxy": p["useProxy"],
+		}
+	case actionTypeUploadFile:
+		settings = tfMap{
+			"file_name": p["fileName"],
+		}
+	case actionTypeVictorOps:
+		settings = tfMap{
+			"message_type": p["messageType"],
+			"notify_url":   p["notifyUrl"],
+			"use_proxy":    p["useProxy"],
+		}
+	case actionTypeWebhook:
+		settings = tfMap{
+			"method":        p["method"],
+			"url":           p["url"],
+			"headers":       mapFromKeyValueList(p["headers"], "header"),
+			"body_template": p["bodyTemplate"],
+			"ignore_ssl":    p["ignoreSSL"],
+			"use_proxy":     p["useProxy"],
+		}
+	default:
+		return diag.Errorf("unsupported action type: %s", a.Type)
+	}
+
+	if err := d.Set(block, []tfMap{settings}); err != nil {
+		return diag.Errorf("error setting %s settings for resource %s: %s", block, d.Id(), err)
+	}
+	return nil
+}
+
+func actionFromResourceData(d *schema.ResourceData) (*action, error) {
+	a := &action{
+		ID:   d.Get("action_id").(string),
+		Name: d.Get("name").(string),
+		Type: actionTypeOfResourceData(d, false),
+	}
+
+	var block string
+	for name, actionType := range actionBlocks {
+		if actionType == a.Type {
+			block = name
+		}
+	}
+	if block == "" {
+		return nil, fmt.Errorf("exactly one of %v must be set", actionBlockNames())
+	}
+	s := d.Get(block).([]interface{})[0].(tfMap)
+
+	switch a.Type {
+	case actionTypeEmail:
+		a.Properties = map[string]interface{}{
+			"recipients":      s["recipients"],
+			"subjectTemplate": optionalProperty(s["subject_template"].(string)),
+			"bodyTemplate":    optionalProperty(s["body_template"].(string)),
+			"useProxy":        s["use_proxy"],
+		}
+	case actionTypeHumioRepo:
+		a.Properties = map[string]interface{}{
+			"ingestToken": s["ingest_token"],
+		}
+	case actionTypeOpsGenie:
+		a.Properties = map[string]interface{}{
+			"apiUrl":   s["api_url"],
+			"genieKey": s["genie_key"],
+			"useProxy": s["use_proxy"],
+		}
+	case actionTypePagerDuty:
+		a.Properties = map[string]interface{}{
+			"routingKey": s["routing_key"],
+			"severity":   s["severity"],
+			"useProxy":   s["use_proxy"],
+		}
+	case actionTypeSlack:
+		a.Properties = map[string]interface{}{
+			"url":      s["url"],
+			"fields":   keyValueListFromMap(s["fields"].(map[string]interface{}), "fieldName"),
+			"useProxy": s["use_proxy"],
+		}
+	case actionTypeSlackPostMessage:
+		a.Properties = map[string]interface{}{
+			"apiToken": s["api_token"],
+			"channels": s["channels"],
+			"fields":   keyValueListFromMap(s["fields"].(map[string]interface{}), "fieldName"),
+			"useProxy": s["use_proxy"],
+		}
+	case actionTypeUploadFile:
+		a.Properties = map[string]interface{}{
+			"fileName": s["file_name"],
+		}
+	case actionTypeVictorOps:
+		a.Properties = map[string]interface{}{
+			"messageType": s["message_type"],
+			"notifyUrl":   s["notify_url"],
+			"useProxy":    s["use_proxy"],
+		}
+	case actionTypeWebhook:
+		a.Properties = map[string]interface{}{
+			"method":       s["method"],
+			"url":          s["url"],
+			"headers":      keyValueListFromMap(s["headers"].(map[string]interface{}), "header"),
+			"bodyTemplate": s["body_template"],
+			"ignoreSSL":    s["ignore_ssl"],
+			"useProxy":     s["use_proxy"],
+		}
+	}
+
+	return a, nil
+}
+
+// optionalProperty returns nil for empty strings, so the server default is used for optional properties.
+func optionalProperty(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+// keyValueListFromMap converts a map to the list of key-value objects used by the Actions API for Slack fields and
+// webhook headers, sorted by key.
+func keyValueListFromMap(m map[string]interface{}, keyName string) []map[string]interface{} {
+	var keys []string
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	list := []map[string]interface{}{}
+	for _, key := range keys {
+		list = append(list, map[string]interface{}{
+			keyName: key,
+			"value": m[key],
+		})
+	}
+	return list
+}
+
+// mapFromKeyValueList is the inverse of keyValueListFromMap.
+func mapFromKeyValueList(v interface{}, keyName string) map[string]interface{} {
+	m := map[string]interface{}{}
+	list, _ := v.([]interface{})
+	for _, item := range list {
+		kv, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		m[fmt.Sprint(kv[keyName])] = kv["value"]
+	}
+	return m
+}
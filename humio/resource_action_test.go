@@ -0,0 +1,273 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccActionRequiredFields(t *testing.T) {
+	config := actionEmpty
+	accTestCase(t, []resource.TestStep{
+		{Config: config, ExpectError: regexp.MustCompile(`The argument "repository" is required, but no definition was found.`)},
+		{Config: config, ExpectError: regexp.MustCompile(`The argument "name" is required, but no definition was found.`)},
+		{Config: config, ExpectError: regexp.MustCompile(`"email": one of`)},
+	}, nil)
+}
+
+func TestAccActionMultipleTypes(t *testing.T) {
+	accTestCase(t, []resource.TestStep{
+		{Config: actionMultipleTypes, ExpectError: regexp.MustCompile(`"email": only one of`)},
+	}, nil)
+}
+
+func TestAccActionWebhookBasicToFull(t *testing.T) {
+	accTestCase(t, []resource.TestStep{
+		{
+			Config: actionWebhookBasic,
+			Check: resource.ComposeTestCheckFunc(
+				resource.TestCheckResourceAttrSet("humio_action.test", "action_id"),
+				resource.TestCheckResourceAttr("humio_action.test", "repository", "sandbox"),
+				resource.TestCheckResourceAttr("humio_action.test", "name", testAccPrefix+"action-webhook"),
+				resource.TestCheckResourceAttr("humio_action.test", "webhook.#", "1"),
+				resource.TestCheckResourceAttr("humio_action.test", "webhook.0.url", "https://example.org/hook"),
+				resource.TestCheckResourceAttr("humio_action.test", "webhook.0.method", "POST"),
+				resource.TestCheckResourceAttr("humio_action.test", "webhook.0.body_template", defaultWebhookBodyTemplate),
+				resource.TestCheckResourceAttr("humio_action.test", "webhook.0.ignore_ssl", "false"),
+				resource.TestCheckResourceAttr("humio_action.test", "webhook.0.use_proxy", "true"),
+				resource.TestCheckResourceAttr("humio_action.test", "email.#", "0"),
+			),
+		},
+		{
+			Config: actionWebhookFull,
+			Check: resource.ComposeTestCheckFunc(
+				resource.TestCheckResourceAttr("humio_action.test", "webhook.0.method", "PUT"),
+				resource.TestCheckResourceAttr("humio_action.test", "webhook.0.headers.%", "1"),
+				resource.TestCheckResourceAttr("humio_action.test", "webhook.0.headers.Content-Type", "application/json"),
+				resource.TestCheckResourceAttr("humio_action.test", "webhook.0.body_template", "{alert_name}"),
+				resource.TestCheckResourceAttr("humio_action.test", "webhook.0.ignore_ssl", "true"),
+				resource.TestCheckResourceAttr("humio_action.test", "webhook.0.use_proxy", "false"),
+			),
+		},
+		{
+			ResourceName:            "humio_action.test",
+			ImportState:             true,
+			ImportStateId:           "sandbox+" + testAccPrefix + "action-webhook",
+			ImportStateVerify:       true,
+			ImportStateVerifyIgnore: []string{"timeouts"},
+		},
+	}, testAccCheckActionDestroy)
+}
+
+func TestAccActionEmailToUploadFile(t *testing.T) {
+	accTestCase(t, []resource.TestStep{
+		{
+			Config: actionEmail,
+			Check: resource.ComposeTestCheckFunc(
+				resource.TestCheckResourceAttr("humio_action.test", "email.#", "1"),
+				resource.TestCheckResourceAttr("humio_action.test", "email.0.recipients.#", "2"),
+				resource.TestCheckResourceAttr("humio_action.test", "email.0.recipients.0", "test@example.org"),
+				resource.TestCheckResourceAttr("humio_action.test", "email.0.subject_template", "{alert_name} triggered"),
+				resource.TestCheckResourceAttr("humio_action.test", "upload_file.#", "0"),
+			),
+		},
+		{
+			Config: actionUploadFile,
+			Check: resource.ComposeTestCheckFunc(
+				resource.TestCheckResourceAttr("humio_action.test", "email.#", "0"),
+				resource.TestCheckResourceAttr("humio_action.test", "upload_file.#", "1"),
+				resource.TestCheckResourceAttr("humio_action.test", "upload_file.0.file_name", "alerts.csv"),
+			),
+		},
+	}, testAccCheckActionDestroy)
+}
+
+func testAccCheckActionDestroy(s *terraform.State) error {
+	conn := testAccProviders["humio"].Meta().(*apiClient)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "humio_action" {
+			continue
+		}
+		_, err := conn.getAction(context.Background(), rs.Primary.Attributes["repository"], rs.Primary.Attributes["name"])
+		if err == nil {
+			return fmt.Errorf("action still exists: %s", rs.Primary.ID)
+		}
+		if !isNotFoundError(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+const actionEmpty = `
+resource "humio_action" "test" {}
+`
+
+const actionMultipleTypes = `
+resource "humio_action" "test" {
+	repository = "sandbox"
+	name       = "` + testAccPrefix + `action"
+	email {
+		recipients = ["test@example.org"]
+	}
+	upload_file {
+		file_name = "alerts.csv"
+	}
+}
+`
+
+const actionWebhookBasic = `
+resource "humio_action" "test" {
+	repository = "sandbox"
+	name       = "` + testAccPrefix + `action-webhook"
+	webhook {
+		url = "https://example.org/hook"
+	}
+}
+`
+
+const actionWebhookFull = `
+resource "humio_action" "test" {
+	repository = "sandbox"
+	name       = "` + testAccPrefix + `action-webhook"
+	webhook {
+		url           = "https://example.org/hook"
+		method        = "PUT"
+		headers       = {
+			"Content-Type" = "application/json"
+		}
+		body_template = "{alert_name}"
+		ignore_ssl    = true
+		use_proxy     = false
+	}
+}
+`
+
+const actionEmail = `
+resource "humio_action" "test" {
+	repository = "sandbox"
+	name       = "` + testAccPrefix + `action"
+	email {
+		recipients       = ["test@example.org", "ops@example.org"]
+		subject_template = "{alert_name} triggered"
+	}
+}
+`
+
+const actionUploadFile = `
+resource "humio_action" "test" {
+	repository = "sandbox"
+	name       = "` + testAccPrefix + `action"
+	upload_file {
+		file_name = "alerts.csv"
+	}
+}
+`
+
+func TestEncodeDecodeActionResource(t *testing.T) {
+	tests := []struct {
+		name   string
+		action action
+		want   map[string]interface{}
+	}{
+		{
+			name: "webhook",
+			action: action{
+				ID:   "abc",
+				Name: "hook",
+				Type: actionTypeWebhook,
+				Properties: map[string]interface{}{
+					"method":       "PUT",
+					"url":          "https://example.org/hook",
+					"headers":      []interface{}{map[string]interface{}{"header": "X-Token", "value": "secret"}},
+					"bodyTemplate": "{alert_name}",
+					"ignoreSSL":    true,
+					"useProxy":     false,
+				},
+			},
+			want: map[string]interface{}{
+				"method":       "PUT",
+				"url":          "https://example.org/hook",
+				"headers":      []map[string]interface{}{{"header": "X-Token", "value": "secret"}},
+				"bodyTemplate": "{alert_name}",
+				"ignoreSSL":    true,
+				"useProxy":     false,
+			},
+		},
+		{
+			name: "slackpostmessage",
+			action: action{
+				ID:   "def",
+				Name: "slack",
+				Type: actionTypeSlackPostMessage,
+				Properties: map[string]interface{}{
+					"apiToken": "xoxb-secret",
+					"channels": []interface{}{"#alerts"},
+					"fields": []interface{}{
+						map[string]interface{}{"fieldName": "query", "value": "{query_string}"},
+						map[string]interface{}{"fieldName": "alert", "value": "{alert_name}"},
+					},
+					"useProxy": true,
+				},
+			},
+			want: map[string]interface{}{
+				"apiToken": "xoxb-secret",
+				"channels": []interface{}{"#alerts"},
+				"fields": []map[string]interface{}{
+					{"fieldName": "alert", "value": "{alert_name}"},
+					{"fieldName": "query", "value": "{query_string}"},
+				},
+				"useProxy": true,
+			},
+		},
+		{
+			name: "upload_file",
+			action: action{
+				ID:         "ghi",
+				Name:       "upload",
+				Type:       actionTypeUploadFile,
+				Properties: map[string]interface{}{"fileName": "alerts.csv"},
+			},
+			want: map[string]interface{}{"fileName": "alerts.csv"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			res := resourceAction()
+			data := res.TestResourceData()
+			if diags := resourceDataFromAction(&test.action, data); diags.HasError() {
+				t.Fatal(diags)
+			}
+			got, err := actionFromResourceData(data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := test.action
+			want.Properties = test.want
+			if !cmp.Equal(&want, got) {
+				t.Error(cmp.Diff(&want, got))
+			}
+		})
+	}
+}
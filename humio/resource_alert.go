@@ -33,6 +33,8 @@ func resourceAlert() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
+		Timeouts:      defaultTimeouts(),
+		CustomizeDiff: customizeDiffRequireFieldServerVersion("humio_alert", "labels", minAlertLabelsVersion),
 
 		Schema: map[string]*schema.Schema{
 			"repository": {
@@ -89,11 +91,14 @@ func resourceAlertCreate(ctx context.Context, d *schema.ResourceData, client int
 		return diag.Errorf("could not obtain alert from resource data: %s", err)
 	}
 
-	_, err = client.(*humio.Client).Alerts().Add(
-		d.Get("repository").(string),
-		&alert,
-		false,
-	)
+	err = client.(*apiClient).call(ctx, func() error {
+		return client.(*apiClient).createAlert(
+			ctx,
+			d.Get("repository").(string),
+			&alert,
+		)
+	})
+	invalidateCachedListing(d, client, cacheAlerts)
 	if err != nil {
 		return diag.Errorf("could not create alert: %s", err)
 	}
@@ -102,7 +107,7 @@ func resourceAlertCreate(ctx context.Context, d *schema.ResourceData, client int
 	return resourceAlertRead(ctx, d, client)
 }
 
-func resourceAlertRead(_ context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+func resourceAlertRead(ctx context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
 	// If we don't have a repository when importing, we parse it from the ID.
 	if _, ok := d.GetOk("repository"); !ok {
 		parts := parseRepositoryAndID(d.Id())
@@ -120,10 +125,19 @@ func resourceAlertRead(_ context.Context, d *schema.ResourceData, client interfa
 		}
 	}
 
-	alert, err := client.(*humio.Client).Alerts().Get(
-		d.Get("repository").(string),
-		d.Get("name").(string),
-	)
+	var alert *humio.Alert
+	err := client.(*apiClient).retry(ctx, "get alert", func() error {
+		var err error
+		alert, err = client.(*apiClient).getAlert(
+			ctx,
+			d.Get("repository").(string),
+			d.Get("name").(string),
+		)
+		return err
+	})
+	if removeFromStateIfNotFound(d, "humio_alert", err) {
+		return nil
+	}
 	if err != nil {
 		return diag.Errorf("could not get alert: %s", err)
 	}
@@ -172,11 +186,14 @@ func resourceAlertUpdate(ctx context.Context, d *schema.ResourceData, client int
 		return diag.Errorf("could not obtain alert from resource data: %s", err)
 	}
 
-	_, err = client.(*humio.Client).Alerts().Add(
-		d.Get("repository").(string),
-		&alert,
-		true,
-	)
+	err = client.(*apiClient).retry(ctx, "update alert", func() error {
+		return client.(*apiClient).updateAlert(
+			ctx,
+			d.Get("repository").(string),
+			&alert,
+		)
+	})
+	invalidateCachedListing(d, client, cacheAlerts)
 	if err != nil {
 		return diag.Errorf("could not update alert: %s", err)
 	}
@@ -184,16 +201,20 @@ func resourceAlertUpdate(ctx context.Context, d *schema.ResourceData, client int
 	return resourceAlertRead(ctx, d, client)
 }
 
-func resourceAlertDelete(_ context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+func resourceAlertDelete(ctx context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
 	alert, err := alertFromResourceData(d)
 	if err != nil {
 		return diag.Errorf("could not obtain alert from resource data: %s", err)
 	}
 
-	err = client.(*humio.Client).Alerts().Delete(
-		d.Get("repository").(string),
-		alert.Name,
-	)
+	err = client.(*apiClient).call(ctx, func() error {
+		return client.(*apiClient).deleteAlert(
+			ctx,
+			d.Get("repository").(string),
+			alert.Name,
+		)
+	})
+	invalidateCachedListing(d, client, cacheAlerts)
 	if err != nil {
 		return diag.Errorf("could not delete alert: %s", err)
 	}
@@ -15,6 +15,7 @@
 package humio
 
 import (
+	"context"
 	"fmt"
 	"regexp"
 	"testing"
@@ -59,7 +60,7 @@ func TestAccAlertBasic(t *testing.T) {
 			Config: alertBasic,
 			Check: resource.ComposeTestCheckFunc(
 				resource.TestCheckResourceAttr("humio_alert.test", "repository", "sandbox"),
-				resource.TestCheckResourceAttr("humio_alert.test", "name", "alert-test"),
+				resource.TestCheckResourceAttr("humio_alert.test", "name", testAccPrefix+"alert"),
 				resource.TestCheckResourceAttr("humio_alert.test", "throttle_time_millis", "3600000"),
 				resource.TestCheckResourceAttr("humio_alert.test", "start", "24h"),
 				resource.TestCheckResourceAttr("humio_alert.test", "query", "loglevel=ERROR"),
@@ -78,7 +79,7 @@ func TestAccAlertBasicToFull(t *testing.T) {
 			Config: alertBasic,
 			Check: resource.ComposeTestCheckFunc(
 				resource.TestCheckResourceAttr("humio_alert.test", "repository", "sandbox"),
-				resource.TestCheckResourceAttr("humio_alert.test", "name", "alert-test"),
+				resource.TestCheckResourceAttr("humio_alert.test", "name", testAccPrefix+"alert"),
 				resource.TestCheckResourceAttr("humio_alert.test", "throttle_time_millis", "3600000"),
 				resource.TestCheckResourceAttr("humio_alert.test", "start", "24h"),
 				resource.TestCheckResourceAttr("humio_alert.test", "query", "loglevel=ERROR"),
@@ -92,7 +93,7 @@ func TestAccAlertBasicToFull(t *testing.T) {
 			Config: alertFull,
 			Check: resource.ComposeTestCheckFunc(
 				resource.TestCheckResourceAttr("humio_alert.test", "repository", "sandbox"),
-				resource.TestCheckResourceAttr("humio_alert.test", "name", "alert-test"),
+				resource.TestCheckResourceAttr("humio_alert.test", "name", testAccPrefix+"alert"),
 				resource.TestCheckResourceAttr("humio_alert.test", "throttle_time_millis", "3600000"),
 				resource.TestCheckResourceAttr("humio_alert.test", "start", "24h"),
 				resource.TestCheckResourceAttr("humio_alert.test", "query", "loglevel=ERROR"),
@@ -111,7 +112,7 @@ func TestAccAlertBasicToFull(t *testing.T) {
 			Config: alertFull,
 			Check: resource.ComposeTestCheckFunc(
 				resource.TestCheckResourceAttr("humio_alert.test", "repository", "sandbox"),
-				resource.TestCheckResourceAttr("humio_alert.test", "name", "alert-test"),
+				resource.TestCheckResourceAttr("humio_alert.test", "name", testAccPrefix+"alert"),
 				resource.TestCheckResourceAttr("humio_alert.test", "throttle_time_millis", "3600000"),
 				resource.TestCheckResourceAttr("humio_alert.test", "start", "24h"),
 				resource.TestCheckResourceAttr("humio_alert.test", "query", "loglevel=ERROR"),
@@ -133,7 +134,7 @@ func TestAccAlertFull(t *testing.T) {
 			Config: alertFull,
 			Check: resource.ComposeTestCheckFunc(
 				resource.TestCheckResourceAttr("humio_alert.test", "repository", "sandbox"),
-				resource.TestCheckResourceAttr("humio_alert.test", "name", "alert-test"),
+				resource.TestCheckResourceAttr("humio_alert.test", "name", testAccPrefix+"alert"),
 				resource.TestCheckResourceAttr("humio_alert.test", "throttle_time_millis", "3600000"),
 				resource.TestCheckResourceAttr("humio_alert.test", "start", "24h"),
 				resource.TestCheckResourceAttr("humio_alert.test", "query", "loglevel=ERROR"),
@@ -150,14 +151,14 @@ func TestAccAlertFull(t *testing.T) {
 }
 
 func testAccCheckAlertDestroy(s *terraform.State) error {
-	conn := testAccProviders["humio"].Meta().(*humio.Client)
+	conn := testAccProviders["humio"].Meta().(*apiClient)
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "humio_alert" {
 			continue
 		}
 		// TODO: Use rs.Primary.ID to figure out if alert exists, and not just list all alerts.
-		resp, err := conn.Alerts().List("sandbox")
+		resp, err := conn.listAlerts(context.Background(), "sandbox")
 		if err == nil {
 			if len(resp) > 0 {
 				return fmt.Errorf("alerts still exist: %#+v", resp)
@@ -188,7 +189,7 @@ resource "humio_alert" "test" {
 const alertBasic = `
 resource "humio_alert" "test" {
 	repository           = "sandbox"
-	name                 = "alert-test"
+	name                 = "` + testAccPrefix + `alert"
 	throttle_time_millis = 3600000
 	start                = "24h"
 	query                = "loglevel=ERROR"
@@ -199,7 +200,7 @@ const alertFull = `
 resource "humio_notifier" "test" {
     repository = "sandbox"
     entity     = "SlackNotifier"
-    name       = "notifier-slack-test"
+    name       = "` + testAccPrefix + `notifier-slack"
     slack {
         fields = {
             "Events String" = "{events_str}"
@@ -212,7 +213,7 @@ resource "humio_notifier" "test" {
 
 resource "humio_alert" "test" {
 	repository           = "sandbox"
-	name                 = "alert-test"
+	name                 = "` + testAccPrefix + `alert"
 	throttle_time_millis = 3600000
 	start                = "24h"
 	query                = "loglevel=ERROR"
@@ -0,0 +1,188 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceDashboard() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceDashboardCreate,
+		ReadContext:   resourceDashboardRead,
+		UpdateContext: resourceDashboardUpdate,
+		DeleteContext: resourceDashboardDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		CustomizeDiff: customizeDashboardDiff,
+		Timeouts:      defaultTimeouts(),
+
+		Schema: map[string]*schema.Schema{
+			"repository": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"template": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ValidateDiagFunc: validateDashboardTemplate,
+				DiffSuppressFunc: suppressEquivalentDashboardTemplate,
+			},
+		},
+	}
+}
+
+func resourceDashboardCreate(ctx context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+	dash, err := dashboardFromResourceData(d)
+	if err != nil {
+		return diag.Errorf("could not obtain dashboard from resource data: %s", err)
+	}
+
+	err = client.(*apiClient).call(ctx, func() error {
+		return client.(*apiClient).createDashboard(
+			ctx,
+			d.Get("repository").(string),
+			&dash,
+		)
+	})
+	if err != nil {
+		return diag.Errorf("could not create dashboard: %s", err)
+	}
+	d.SetId(fmt.Sprintf("%s+%s", d.Get("repository"), d.Get("name")))
+
+	return resourceDashboardRead(ctx, d, client)
+}
+
+func resourceDashboardRead(ctx context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+	// If we don't have a repository when importing, we parse it from the ID.
+	if _, ok := d.GetOk("repository"); !ok {
+		parts := parseRepositoryAndID(d.Id())
+		//we check that we have parsed the id into the correct number of segments
+		if parts[0] == "" || parts[1] == "" {
+			return diag.Errorf("error importing humio_dashboard. Please make sure the ID is in the form REPOSITORYNAME+DASHBOARDNAME (i.e. myRepoName+myDashboardName")
+		}
+		err := d.Set("repository", parts[0])
+		if err != nil {
+			return diag.Errorf("error setting repository for resource %s: %s", d.Id(), err)
+		}
+		err = d.Set("name", parts[1])
+		if err != nil {
+			return diag.Errorf("error setting name for resource %s: %s", d.Id(), err)
+		}
+	}
+
+	var dash *dashboard
+	err := client.(*apiClient).retry(ctx, "get dashboard", func() error {
+		var err error
+		dash, err = client.(*apiClient).getDashboard(
+			ctx,
+			d.Get("repository").(string),
+			d.Get("name").(string),
+		)
+		return err
+	})
+	if removeFromStateIfNotFound(d, "humio_dashboard", err) {
+		return nil
+	}
+	if err != nil {
+		return diag.Errorf("could not get dashboard: %s", err)
+	}
+	return resourceDataFromDashboard(dash, d)
+}
+
+func resourceDataFromDashboard(a *dashboard, d *schema.ResourceData) diag.Diagnostics {
+	err := d.Set("name", a.Name)
+	if err != nil {
+		return diag.Errorf("error setting name for resource %s: %s", d.Id(), err)
+	}
+	err = d.Set("template", a.Template)
+	if err != nil {
+		return diag.Errorf("error setting template for resource %s: %s", d.Id(), err)
+	}
+	return nil
+}
+
+// resourceDashboardUpdate updates the dashboard in place, so its ID and the links to it stay the same. Template changes
+// the update cannot express are planned as a replacement by customizeDashboardDiff instead.
+func resourceDashboardUpdate(ctx context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+	dash, err := dashboardFromResourceData(d)
+	if err != nil {
+		return diag.Errorf("could not obtain dashboard from resource data: %s", err)
+	}
+	repository := d.Get("repository").(string)
+
+	var old *dashboard
+	err = client.(*apiClient).retry(ctx, "get dashboard", func() error {
+		var err error
+		old, err = client.(*apiClient).getDashboard(ctx, repository, dash.Name)
+		return err
+	})
+	if err != nil {
+		return diag.Errorf("could not get dashboard: %s", err)
+	}
+
+	err = client.(*apiClient).call(ctx, func() error {
+		return client.(*apiClient).updateDashboard(ctx, old.ID, &dash)
+	})
+	if err != nil {
+		return diag.Errorf("could not update dashboard: %s", err)
+	}
+	return resourceDashboardRead(ctx, d, client)
+}
+
+func resourceDashboardDelete(ctx context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+	var dash *dashboard
+	err := client.(*apiClient).retry(ctx, "get dashboard", func() error {
+		var err error
+		dash, err = client.(*apiClient).getDashboard(
+			ctx,
+			d.Get("repository").(string),
+			d.Get("name").(string),
+		)
+		return err
+	})
+	if isNotFoundError(err) {
+		return nil
+	}
+	if err != nil {
+		return diag.Errorf("could not get dashboard: %s", err)
+	}
+
+	err = client.(*apiClient).call(ctx, func() error {
+		return client.(*apiClient).deleteDashboard(ctx, dash.ID)
+	})
+	if err != nil {
+		return diag.Errorf("could not delete dashboard: %s", err)
+	}
+	return nil
+}
+
+func dashboardFromResourceData(d *schema.ResourceData) (dashboard, error) {
+	return dashboard{
+		Name:     d.Get("name").(string),
+		Template: d.Get("template").(string),
+	}, nil
+}
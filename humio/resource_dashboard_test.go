@@ -0,0 +1,189 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDashboardRequiredFields(t *testing.T) {
+	config := dashboardEmpty
+	accTestCase(t, []resource.TestStep{
+		{Config: config, ExpectError: regexp.MustCompile(`The argument "repository" is required, but no definition was found.`)},
+		{Config: config, ExpectError: regexp.MustCompile(`The argument "name" is required, but no definition was found.`)},
+		{Config: config, ExpectError: regexp.MustCompile(`The argument "template" is required, but no definition was found.`)},
+	}, nil)
+}
+
+func TestAccDashboardInvalidTemplate(t *testing.T) {
+	accTestCase(t, []resource.TestStep{
+		{Config: dashboardInvalidTemplate, ExpectError: regexp.MustCompile(`Invalid dashboard template`)},
+	}, nil)
+}
+
+func TestAccDashboardBasicToFull(t *testing.T) {
+	accTestCase(t, []resource.TestStep{
+		{
+			Config: dashboardBasic,
+			Check: resource.ComposeTestCheckFunc(
+				resource.TestCheckResourceAttr("humio_dashboard.test", "repository", "sandbox"),
+				resource.TestCheckResourceAttr("humio_dashboard.test", "name", testAccPrefix+"dashboard"),
+			),
+		},
+		{
+			Config:   dashboardBasicReformatted,
+			PlanOnly: true,
+		},
+		{
+			Config: dashboardFull,
+			Check: resource.ComposeTestCheckFunc(
+				resource.TestCheckResourceAttr("humio_dashboard.test", "repository", "sandbox"),
+				resource.TestCheckResourceAttr("humio_dashboard.test", "name", testAccPrefix+"dashboard"),
+			),
+		},
+		{
+			ResourceName:            "humio_dashboard.test",
+			ImportState:             true,
+			ImportStateId:           "sandbox+" + testAccPrefix + "dashboard",
+			ImportStateVerify:       true,
+			ImportStateVerifyIgnore: []string{"template"},
+		},
+	}, testAccCheckDashboardDestroy)
+}
+
+func testAccCheckDashboardDestroy(s *terraform.State) error {
+	conn := testAccProviders["humio"].Meta().(*apiClient)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "humio_dashboard" {
+			continue
+		}
+		_, err := conn.getDashboard(context.Background(), rs.Primary.Attributes["repository"], rs.Primary.Attributes["name"])
+		if err == nil {
+			return fmt.Errorf("dashboard still exists: %s", rs.Primary.ID)
+		}
+		if !isNotFoundError(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+const dashboardEmpty = `
+resource "humio_dashboard" "test" {}
+`
+
+const dashboardInvalidTemplate = `
+resource "humio_dashboard" "test" {
+	repository = "sandbox"
+	name       = "` + testAccPrefix + `dashboard"
+	template   = "- not a dashboard"
+}
+`
+
+const dashboardBasic = `
+resource "humio_dashboard" "test" {
+	repository = "sandbox"
+	name       = "` + testAccPrefix + `dashboard"
+	template   = <<-EOT
+		name: ` + testAccPrefix + `dashboard
+		updateFrequency: never
+		widgets:
+		  count:
+		    x: 0
+		    y: 0
+		    width: 4
+		    height: 4
+		    queryString: count()
+		    visualization: single-value
+		    title: Events
+		EOT
+}
+`
+
+const dashboardBasicReformatted = `
+resource "humio_dashboard" "test" {
+	repository = "sandbox"
+	name       = "` + testAccPrefix + `dashboard"
+	template   = jsonencode({
+		updateFrequency = "never"
+		widgets = {
+			events = {
+				title         = "Events"
+				visualization = "single-value"
+				queryString   = "count()"
+				height        = 4
+				width         = 4
+				y             = 0
+				x             = 0
+			}
+		}
+	})
+}
+`
+
+const dashboardFull = `
+resource "humio_dashboard" "test" {
+	repository = "sandbox"
+	name       = "` + testAccPrefix + `dashboard"
+	template   = <<-EOT
+		name: ` + testAccPrefix + `dashboard
+		updateFrequency: never
+		widgets:
+		  count:
+		    x: 0
+		    y: 0
+		    width: 4
+		    height: 4
+		    queryString: count()
+		    visualization: single-value
+		    title: Events
+		  errors:
+		    x: 4
+		    y: 0
+		    width: 4
+		    height: 4
+		    queryString: loglevel=ERROR | count()
+		    visualization: single-value
+		    title: Errors
+		EOT
+}
+`
+
+var wantDashboard = dashboard{
+	Name:     "errors",
+	Template: dashboardTemplateYAML,
+}
+
+func TestEncodeDecodeDashboardResource(t *testing.T) {
+	res := resourceDashboard()
+	data := res.TestResourceData()
+	resourceDataFromDashboard(&wantDashboard, data)
+	got, err := dashboardFromResourceData(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cmp.Equal(wantDashboard, got) {
+		t.Error(cmp.Diff(wantDashboard, got))
+	}
+}
@@ -0,0 +1,745 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	humio "github.com/humio/cli/api"
+)
+
+// resourceDataTestID is the ID of the resources built by TestResourceDataRoundTrip.
+const resourceDataTestID = "test-id"
+
+// groupRoleAssignment combines the values returned by groupRoleFromResourceData.
+type groupRoleAssignment struct {
+	GroupID string
+	Role    groupRole
+}
+
+// resourceDataTest is a round trip between the Terraform state of a resource and the object sent to and returned by
+// Humio.
+type resourceDataTest struct {
+	name     string
+	resource *schema.Resource
+	// state is the state of the resource as stored by Terraform.
+	state map[string]interface{}
+	// kept are the attributes of state which are not returned by Humio, and so are kept from the state on read.
+	kept []string
+	// want is the object expanded from state.
+	want interface{}
+	// read is the object as returned by Humio, if it differs from want, e.g. because lists decoded from JSON are
+	// []interface{}.
+	read    interface{}
+	expand  func(*schema.ResourceData) (interface{}, error)
+	flatten func(interface{}, *schema.ResourceData) diag.Diagnostics
+	opts    []cmp.Option
+}
+
+func notifierResourceDataTest(name string, state map[string]interface{}, want humio.Notifier) resourceDataTest {
+	state["repository"] = "sandbox"
+	state["notifier_id"] = want.ID
+	state["name"] = want.Name
+	state["entity"] = want.Entity
+	return resourceDataTest{
+		name:     name,
+		resource: resourceNotifier(),
+		state:    state,
+		kept:     []string{"repository"},
+		want:     want,
+		expand: func(d *schema.ResourceData) (interface{}, error) {
+			return notifierFromResourceData(d)
+		},
+		flatten: func(v interface{}, d *schema.ResourceData) diag.Diagnostics {
+			n := v.(humio.Notifier)
+			return resourceDataFromNotifier(&n, d)
+		},
+	}
+}
+
+func actionResourceDataTest(name string, state map[string]interface{}, want, read action) resourceDataTest {
+	state["repository"] = "sandbox"
+	state["action_id"] = want.ID
+	state["name"] = want.Name
+	return resourceDataTest{
+		name:     name,
+		resource: resourceAction(),
+		state:    state,
+		kept:     []string{"repository"},
+		want:     want,
+		read:     read,
+		expand: func(d *schema.ResourceData) (interface{}, error) {
+			a, err := actionFromResourceData(d)
+			if err != nil {
+				return nil, err
+			}
+			return *a, nil
+		},
+		flatten: func(v interface{}, d *schema.ResourceData) diag.Diagnostics {
+			a := v.(action)
+			return resourceDataFromAction(&a, d)
+		},
+	}
+}
+
+var resourceDataTests = []resourceDataTest{
+	notifierResourceDataTest("notifier email", map[string]interface{}{
+		"email": []interface{}{map[string]interface{}{
+			"recipients":       []interface{}{"ops@example.com", "dev@example.com"},
+			"subject_template": "{alert_name} triggered",
+			"body_template":    "{events_str}",
+		}},
+	}, humio.Notifier{
+		ID:     "email-id",
+		Entity: humio.NotifierTypeEmail,
+		Name:   "email",
+		Properties: map[string]interface{}{
+			"recipients":      []interface{}{"ops@example.com", "dev@example.com"},
+			"subjectTemplate": "{alert_name} triggered",
+			"bodyTemplate":    "{events_str}",
+		},
+	}),
+	notifierResourceDataTest("notifier email without templates", map[string]interface{}{
+		"email": []interface{}{map[string]interface{}{
+			"recipients": []interface{}{"ops@example.com"},
+		}},
+	}, humio.Notifier{
+		ID:     "email-id",
+		Entity: humio.NotifierTypeEmail,
+		Name:   "email",
+		Properties: map[string]interface{}{
+			"recipients": []interface{}{"ops@example.com"},
+		},
+	}),
+	notifierResourceDataTest("notifier humiorepo", map[string]interface{}{
+		"humiorepo": []interface{}{map[string]interface{}{
+			"ingest_token": "12345678-abcd",
+		}},
+	}, humio.Notifier{
+		ID:         "humiorepo-id",
+		Entity:     humio.NotifierTypeHumioRepo,
+		Name:       "humiorepo",
+		Properties: map[string]interface{}{"ingestToken": "12345678-abcd"},
+	}),
+	notifierResourceDataTest("notifier opsgenie", map[string]interface{}{
+		"opsgenie": []interface{}{map[string]interface{}{
+			"api_url":   "https://api.eu.opsgenie.com",
+			"genie_key": "genie-key",
+		}},
+	}, humio.Notifier{
+		ID:     "opsgenie-id",
+		Entity: humio.NotifierTypeOpsGenie,
+		Name:   "opsgenie",
+		Properties: map[string]interface{}{
+			"apiUrl":   "https://api.eu.opsgenie.com",
+			"genieKey": "genie-key",
+		},
+	}),
+	notifierResourceDataTest("notifier pagerduty", map[string]interface{}{
+		"pagerduty": []interface{}{map[string]interface{}{
+			"routing_key": "routing-key",
+			"severity":    "critical",
+		}},
+	}, humio.Notifier{
+		ID:     "pagerduty-id",
+		Entity: humio.NotifierTypePagerDuty,
+		Name:   "pagerduty",
+		Properties: map[string]interface{}{
+			"routingKey": "routing-key",
+			"severity":   "critical",
+		},
+	}),
+	notifierResourceDataTest("notifier slack", map[string]interface{}{
+		"slack": []interface{}{map[string]interface{}{
+			"url":    "https://hooks.slack.com/services/X/Y/Z",
+			"fields": map[string]interface{}{"Query": "{query_string}", "Events": "{events_str}"},
+		}},
+	}, humio.Notifier{
+		ID:     "slack-id",
+		Entity: humio.NotifierTypeSlack,
+		Name:   "slack",
+		Properties: map[string]interface{}{
+			"url":    "https://hooks.slack.com/services/X/Y/Z",
+			"fields": map[string]interface{}{"Query": "{query_string}", "Events": "{events_str}"},
+		},
+	}),
+	notifierResourceDataTest("notifier slackpostmessage", map[string]interface{}{
+		"slackpostmessage": []interface{}{map[string]interface{}{
+			"api_token": "xoxb-token",
+			"channels":  []interface{}{"#alerts", "#ops"},
+			"fields":    map[string]interface{}{"Query": "{query_string}"},
+			"use_proxy": false,
+		}},
+	}, humio.Notifier{
+		ID:     "slackpostmessage-id",
+		Entity: humio.NotifierTypeSlackPostMessage,
+		Name:   "slackpostmessage",
+		Properties: map[string]interface{}{
+			"apiToken": "xoxb-token",
+			"channels": []interface{}{"#alerts", "#ops"},
+			"fields":   map[string]interface{}{"Query": "{query_string}"},
+			"useProxy": false,
+		},
+	}),
+	notifierResourceDataTest("notifier victorops", map[string]interface{}{
+		"victorops": []interface{}{map[string]interface{}{
+			"message_type": "CRITICAL",
+			"notify_url":   "https://alert.victorops.com/integrations/generic/1/alert/key/humio",
+		}},
+	}, humio.Notifier{
+		ID:     "victorops-id",
+		Entity: humio.NotifierTypeVictorOps,
+		Name:   "victorops",
+		Properties: map[string]interface{}{
+			"messageType": "CRITICAL",
+			"notifyUrl":   "https://alert.victorops.com/integrations/generic/1/alert/key/humio",
+		},
+	}),
+	notifierResourceDataTest("notifier webhook", map[string]interface{}{
+		"webhook": []interface{}{map[string]interface{}{
+			"body_template": "{alert_name}",
+			"headers":       map[string]interface{}{"Authorization": "Bearer token"},
+			"method":        "PUT",
+			"url":           "https://example.org/hook",
+		}},
+	}, humio.Notifier{
+		ID:     "webhook-id",
+		Entity: humio.NotifierTypeWebHook,
+		Name:   "webhook",
+		Properties: map[string]interface{}{
+			"bodyTemplate": "{alert_name}",
+			"headers":      map[string]interface{}{"Authorization": "Bearer token"},
+			"method":       "PUT",
+			"url":          "https://example.org/hook",
+		},
+	}),
+	actionResourceDataTest("action email", map[string]interface{}{
+		"email": []interface{}{map[string]interface{}{
+			"recipients":       []interface{}{"ops@example.com"},
+			"subject_template": "{alert_name}",
+			"use_proxy":        true,
+		}},
+	}, action{
+		ID:   "email-id",
+		Name: "email",
+		Type: actionTypeEmail,
+		Properties: map[string]interface{}{
+			"recipients":      []interface{}{"ops@example.com"},
+			"subjectTemplate": "{alert_name}",
+			"bodyTemplate":    nil,
+			"useProxy":        true,
+		},
+	}, action{
+		ID:   "email-id",
+		Name: "email",
+		Type: actionTypeEmail,
+		Properties: map[string]interface{}{
+			"recipients":      []interface{}{"ops@example.com"},
+			"subjectTemplate": "{alert_name}",
+			"useProxy":        true,
+		},
+	}),
+	actionResourceDataTest("action slack", map[string]interface{}{
+		"slack": []interface{}{map[string]interface{}{
+			"url":       "https://hooks.slack.com/services/X/Y/Z",
+			"fields":    map[string]interface{}{"Query": "{query_string}", "Alert": "{alert_name}"},
+			"use_proxy": false,
+		}},
+	}, action{
+		ID:   "slack-id",
+		Name: "slack",
+		Type: actionTypeSlack,
+		Properties: map[string]interface{}{
+			"url": "https://hooks.slack.com/services/X/Y/Z",
+			"fields": []map[string]interface{}{
+				{"fieldName": "Alert", "value": "{alert_name}"},
+				{"fieldName": "Query", "value": "{query_string}"},
+			},
+			"useProxy": false,
+		},
+	}, action{
+		ID:   "slack-id",
+		Name: "slack",
+		Type: actionTypeSlack,
+		Properties: map[string]interface{}{
+			"url": "https://hooks.slack.com/services/X/Y/Z",
+			"fields": []interface{}{
+				map[string]interface{}{"fieldName": "Query", "value": "{query_string}"},
+				map[string]interface{}{"fieldName": "Alert", "value": "{alert_name}"},
+			},
+			"useProxy": false,
+		},
+	}),
+	actionResourceDataTest("action upload_file", map[string]interface{}{
+		"upload_file": []interface{}{map[string]interface{}{
+			"file_name": "alerts.csv",
+		}},
+	}, action{
+		ID:         "upload-id",
+		Name:       "upload",
+		Type:       actionTypeUploadFile,
+		Properties: map[string]interface{}{"fileName": "alerts.csv"},
+	}, action{
+		ID:         "upload-id",
+		Name:       "upload",
+		Type:       actionTypeUploadFile,
+		Properties: map[string]interface{}{"fileName": "alerts.csv"},
+	}),
+	{
+		name:     "alert",
+		resource: resourceAlert(),
+		state: map[string]interface{}{
+			"repository":           "sandbox",
+			"name":                 "errors",
+			"description":          "too many errors",
+			"silenced":             true,
+			"throttle_time_millis": 60000,
+			"start":                "5m",
+			"query":                "loglevel=ERROR | count() > 10",
+			"notifiers":            []interface{}{"notifier-id"},
+			"labels":               []interface{}{"errors", "important"},
+		},
+		kept: []string{"repository"},
+		want: humio.Alert{
+			Name:               "errors",
+			Description:        "too many errors",
+			Silenced:           true,
+			ThrottleTimeMillis: 60000,
+			Query: humio.HumioQuery{
+				QueryString: "loglevel=ERROR | count() > 10",
+				Start:       "5m",
+				End:         "now",
+				IsLive:      true,
+			},
+			Notifiers: []string{"notifier-id"},
+			Labels:    []string{"errors", "important"},
+		},
+		expand: func(d *schema.ResourceData) (interface{}, error) {
+			return alertFromResourceData(d)
+		},
+		flatten: func(v interface{}, d *schema.ResourceData) diag.Diagnostics {
+			a := v.(humio.Alert)
+			return resourceDataFromAlert(&a, d)
+		},
+	},
+	{
+		name:     "repository",
+		resource: resourceRepository(),
+		state: map[string]interface{}{
+			"name":                "logs",
+			"description":         "application logs",
+			"allow_data_deletion": true,
+			"retention": []interface{}{map[string]interface{}{
+				"time_in_days":       30.0,
+				"ingest_size_in_gb":  10.5,
+				"storage_size_in_gb": 5.0,
+			}},
+		},
+		kept: []string{"allow_data_deletion"},
+		want: humio.Repository{
+			Name:                   "logs",
+			Description:            "application logs",
+			RetentionDays:          30,
+			IngestRetentionSizeGB:  10.5,
+			StorageRetentionSizeGB: 5,
+		},
+		expand: func(d *schema.ResourceData) (interface{}, error) {
+			return repositoryFromResourceData(d)
+		},
+		flatten: func(v interface{}, d *schema.ResourceData) diag.Diagnostics {
+			r := v.(humio.Repository)
+			return resourceDataFromRepository(&r, d)
+		},
+	},
+	{
+		name:     "repository without retention",
+		resource: resourceRepository(),
+		state: map[string]interface{}{
+			"name":      "logs",
+			"retention": []interface{}{map[string]interface{}{}},
+		},
+		kept: []string{"allow_data_deletion"},
+		want: humio.Repository{Name: "logs"},
+		expand: func(d *schema.ResourceData) (interface{}, error) {
+			return repositoryFromResourceData(d)
+		},
+		flatten: func(v interface{}, d *schema.ResourceData) diag.Diagnostics {
+			r := v.(humio.Repository)
+			return resourceDataFromRepository(&r, d)
+		},
+	},
+	{
+		name:     "parser",
+		resource: resourceParser(),
+		state: map[string]interface{}{
+			"repository":    "sandbox",
+			"name":          "accesslog-custom",
+			"parser_script": "parseJson() | @timestamp := parseTimestamp(field=time)",
+			"tag_fields":    []interface{}{"host", "service"},
+			"test_data":     []interface{}{`{"time": "2020-11-01T12:00:00Z"}`},
+		},
+		kept: []string{"repository"},
+		want: humio.Parser{
+			Name:      "accesslog-custom",
+			Script:    "parseJson() | @timestamp := parseTimestamp(field=time)",
+			TagFields: []string{"host", "service"},
+			Tests:     []humio.ParserTestCase{{Input: `{"time": "2020-11-01T12:00:00Z"}`}},
+		},
+		expand: func(d *schema.ResourceData) (interface{}, error) {
+			return parserFromResourceData(d)
+		},
+		flatten: func(v interface{}, d *schema.ResourceData) diag.Diagnostics {
+			p := v.(humio.Parser)
+			return resourceDataFromParser(&p, d)
+		},
+	},
+	{
+		name:     "ingest token",
+		resource: resourceIngestToken(),
+		state: map[string]interface{}{
+			"repository": "sandbox",
+			"name":       "shipper",
+			"token":      "12345678-abcd",
+			"parser":     "json",
+		},
+		kept: []string{"repository"},
+		want: humio.IngestToken{
+			Name:           "shipper",
+			Token:          "12345678-abcd",
+			AssignedParser: "json",
+		},
+		expand: func(d *schema.ResourceData) (interface{}, error) {
+			return ingestTokenFromResourceData(d)
+		},
+		flatten: func(v interface{}, d *schema.ResourceData) diag.Diagnostics {
+			i := v.(humio.IngestToken)
+			return resourceDataFromIngestToken(&i, d)
+		},
+	},
+	{
+		name:     "dashboard",
+		resource: resourceDashboard(),
+		state: map[string]interface{}{
+			"repository": "sandbox",
+			"name":       "errors",
+			"template":   dashboardTemplateYAML,
+		},
+		kept: []string{"repository"},
+		want: dashboard{
+			Name:     "errors",
+			Template: dashboardTemplateYAML,
+		},
+		expand: func(d *schema.ResourceData) (interface{}, error) {
+			return dashboardFromResourceData(d)
+		},
+		flatten: func(v interface{}, d *schema.ResourceData) diag.Diagnostics {
+			b := v.(dashboard)
+			return resourceDataFromDashboard(&b, d)
+		},
+	},
+	{
+		name:     "group",
+		resource: resourceGroup(),
+		state: map[string]interface{}{
+			"name":        "ops",
+			"lookup_name": "cn=ops,ou=groups,dc=example,dc=com",
+		},
+		want: group{
+			ID:          resourceDataTestID,
+			DisplayName: "ops",
+			LookupName:  "cn=ops,ou=groups,dc=example,dc=com",
+		},
+		expand: func(d *schema.ResourceData) (interface{}, error) {
+			return groupFromResourceData(d)
+		},
+		flatten: func(v interface{}, d *schema.ResourceData) diag.Diagnostics {
+			g := v.(group)
+			return resourceDataFromGroup(&g, d)
+		},
+	},
+	{
+		name:     "group role assignment",
+		resource: resourceGroupRoleAssignment(),
+		state: map[string]interface{}{
+			"group_id":   "group-id",
+			"role_id":    "role-id",
+			"repository": "sandbox",
+		},
+		want: groupRoleAssignment{
+			GroupID: "group-id",
+			Role:    groupRole{RoleID: "role-id", Repository: "sandbox"},
+		},
+		expand: func(d *schema.ResourceData) (interface{}, error) {
+			groupID, role := groupRoleFromResourceData(d)
+			return groupRoleAssignment{GroupID: groupID, Role: role}, nil
+		},
+	},
+	{
+		name:     "role",
+		resource: resourceRole(),
+		state: map[string]interface{}{
+			"name":                     "ops",
+			"view_permissions":         []interface{}{"ReadAccess", "ChangeParsers"},
+			"organization_permissions": []interface{}{"CreateRepository"},
+			"system_permissions":       []interface{}{"ReadHealthCheck"},
+		},
+		want: role{
+			ID:                      resourceDataTestID,
+			DisplayName:             "ops",
+			ViewPermissions:         []string{"ChangeParsers", "ReadAccess"},
+			OrganizationPermissions: []string{"CreateRepository"},
+			SystemPermissions:       []string{"ReadHealthCheck"},
+		},
+		expand: func(d *schema.ResourceData) (interface{}, error) {
+			return roleFromResourceData(d)
+		},
+		flatten: func(v interface{}, d *schema.ResourceData) diag.Diagnostics {
+			r := v.(role)
+			return resourceDataFromRole(&r, d)
+		},
+		// Permissions are sets, which have no order.
+		opts: []cmp.Option{cmpopts.SortSlices(func(a, b string) bool { return a < b })},
+	},
+	{
+		name:     "saved query",
+		resource: resourceSavedQuery(),
+		state: map[string]interface{}{
+			"repository":  "sandbox",
+			"name":        "errors",
+			"query":       "loglevel=ERROR | timechart()",
+			"start":       "7d",
+			"end":         "now",
+			"is_live":     true,
+			"widget_type": "time-chart",
+			"options":     `{"interpolation":"monotone"}`,
+		},
+		kept: []string{"repository"},
+		want: savedQuery{
+			Name: "errors",
+			Query: humio.HumioQuery{
+				QueryString: "loglevel=ERROR | timechart()",
+				Start:       "7d",
+				End:         "now",
+				IsLive:      true,
+			},
+			WidgetType: "time-chart",
+			Options:    `{"interpolation":"monotone"}`,
+		},
+		expand: func(d *schema.ResourceData) (interface{}, error) {
+			return savedQueryFromResourceData(d)
+		},
+		flatten: func(v interface{}, d *schema.ResourceData) diag.Diagnostics {
+			s := v.(savedQuery)
+			return resourceDataFromSavedQuery(&s, d)
+		},
+	},
+	{
+		name:     "scheduled search",
+		resource: resourceScheduledSearch(),
+		state: map[string]interface{}{
+			"repository":     "sandbox",
+			"name":           "weekly-errors",
+			"description":    "Weekly error report",
+			"query":          "loglevel=ERROR | count()",
+			"start":          "7d",
+			"end":            "now",
+			"schedule":       "0 8 * * MON",
+			"time_zone":      "UTC+01:00",
+			"backfill_limit": 3,
+			"enabled":        false,
+			"actions":        []interface{}{"action-id"},
+			"labels":         []interface{}{"report", "weekly"},
+		},
+		kept: []string{"repository"},
+		want: scheduledSearch{
+			Name:          "weekly-errors",
+			Description:   "Weekly error report",
+			QueryString:   "loglevel=ERROR | count()",
+			Start:         "7d",
+			End:           "now",
+			Schedule:      "0 8 * * MON",
+			TimeZone:      "UTC+01:00",
+			BackfillLimit: 3,
+			Enabled:       false,
+			Actions:       []string{"action-id"},
+			Labels:        []string{"report", "weekly"},
+		},
+		expand: func(d *schema.ResourceData) (interface{}, error) {
+			return scheduledSearchFromResourceData(d)
+		},
+		flatten: func(v interface{}, d *schema.ResourceData) diag.Diagnostics {
+			s := v.(scheduledSearch)
+			return resourceDataFromScheduledSearch(&s, d)
+		},
+	},
+	{
+		name:     "user",
+		resource: resourceUser(),
+		state: map[string]interface{}{
+			"username":     "jane@example.com",
+			"full_name":    "Jane Doe",
+			"email":        "jane@example.com",
+			"company":      "Example",
+			"country_code": "DK",
+			"is_root":      true,
+		},
+		want: humio.User{
+			Username:    "jane@example.com",
+			FullName:    "Jane Doe",
+			Email:       "jane@example.com",
+			Company:     "Example",
+			CountryCode: "DK",
+			IsRoot:      true,
+		},
+		expand: func(d *schema.ResourceData) (interface{}, error) {
+			return userFromResourceData(d)
+		},
+		flatten: func(v interface{}, d *schema.ResourceData) diag.Diagnostics {
+			u := v.(humio.User)
+			return resourceDataFromUser(&u, d)
+		},
+	},
+	{
+		name:     "view",
+		resource: resourceView(),
+		state: map[string]interface{}{
+			"name":        "all-errors",
+			"description": "errors across repositories",
+			"repository_connection": []interface{}{
+				map[string]interface{}{"repository": "sandbox", "filter": "loglevel=ERROR"},
+				map[string]interface{}{"repository": "humio"},
+			},
+		},
+		want: view{
+			Name:        "all-errors",
+			Description: "errors across repositories",
+			Connections: []humio.ViewConnection{
+				{RepoName: "humio", Filter: ""},
+				{RepoName: "sandbox", Filter: "loglevel=ERROR"},
+			},
+		},
+		expand: func(d *schema.ResourceData) (interface{}, error) {
+			return viewFromResourceData(d)
+		},
+		flatten: func(v interface{}, d *schema.ResourceData) diag.Diagnostics {
+			w := v.(view)
+			return resourceDataFromView(&w, d)
+		},
+		// Connections are a set, which has no order.
+		opts: []cmp.Option{cmpopts.SortSlices(func(a, b humio.ViewConnection) bool { return a.RepoName < b.RepoName })},
+	},
+}
+
+// stateAttributes returns the flatmapped state of d without empty values. Terraform does not distinguish empty strings
+// and blocks from unset ones, so whether they are stored depends only on how the attribute was written.
+func stateAttributes(d *schema.ResourceData) map[string]string {
+	attributes := map[string]string{}
+	for key, value := range d.State().Attributes {
+		if value == "" || value == "0" && (strings.HasSuffix(key, ".#") || strings.HasSuffix(key, ".%")) {
+			continue
+		}
+		attributes[key] = value
+	}
+	return attributes
+}
+
+// TestResourceDataRoundTrip checks that the state of each resource expands to the object sent to Humio, and that
+// reading the object back from Humio flattens it to the same state.
+func TestResourceDataRoundTrip(t *testing.T) {
+	for _, test := range resourceDataTests {
+		t.Run(test.name, func(t *testing.T) {
+			d := schema.TestResourceDataRaw(t, test.resource.Schema, test.state)
+			d.SetId(resourceDataTestID)
+			got, err := test.expand(d)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !cmp.Equal(test.want, got, test.opts...) {
+				t.Errorf("unexpected object expanded from state:\n%s", cmp.Diff(test.want, got, test.opts...))
+			}
+			if test.flatten == nil {
+				return
+			}
+
+			read := test.read
+			if read == nil {
+				read = test.want
+			}
+			flattened := test.resource.TestResourceData()
+			flattened.SetId(resourceDataTestID)
+			for _, key := range test.kept {
+				if err := flattened.Set(key, d.Get(key)); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if diags := test.flatten(read, flattened); diags.HasError() {
+				t.Fatal(diags)
+			}
+			want, got := stateAttributes(d), stateAttributes(flattened)
+			if !cmp.Equal(want, got) {
+				t.Errorf("unexpected state flattened from object:\n%s", cmp.Diff(want, got))
+			}
+
+			got, err = test.expand(flattened)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !cmp.Equal(test.want, got, test.opts...) {
+				t.Errorf("unexpected object expanded from flattened state:\n%s", cmp.Diff(test.want, got, test.opts...))
+			}
+		})
+	}
+}
+
+func TestGetNotifierPropertiesFromResourceData(t *testing.T) {
+	tests := []struct {
+		name  string
+		state []interface{}
+		want  []tfMap
+	}{
+		{
+			name:  "single block",
+			state: []interface{}{map[string]interface{}{"ingest_token": "abc"}},
+			want:  []tfMap{{"ingest_token": "abc"}},
+		},
+		{
+			name: "empty block left by a change",
+			state: []interface{}{
+				map[string]interface{}{"ingest_token": ""},
+				map[string]interface{}{"ingest_token": "abc"},
+			},
+			want: []tfMap{{"ingest_token": "abc"}},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			d := schema.TestResourceDataRaw(t, resourceNotifier().Schema, map[string]interface{}{
+				"repository": "sandbox",
+				"name":       "humiorepo",
+				"entity":     humio.NotifierTypeHumioRepo,
+				"humiorepo":  test.state,
+			})
+			got := getNotifierPropertiesFromResourceData(d, "humiorepo", "ingest_token")
+			if !cmp.Equal(test.want, got) {
+				t.Error(cmp.Diff(test.want, got))
+			}
+		})
+	}
+}
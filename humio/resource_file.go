@@ -0,0 +1,214 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceFile() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceFileCreate,
+		ReadContext:   resourceFileRead,
+		UpdateContext: resourceFileUpdate,
+		DeleteContext: resourceFileDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		CustomizeDiff: resourceFileCustomizeDiff,
+		Timeouts:      defaultTimeouts(),
+
+		Schema: map[string]*schema.Schema{
+			"repository": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"content": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ExactlyOneOf:     []string{"content", "source"},
+				DiffSuppressFunc: suppressImportedFileContent,
+			},
+			"source": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ExactlyOneOf:     []string{"content", "source"},
+				DiffSuppressFunc: suppressImportedFileContent,
+			},
+			"content_hash": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+// resourceFileCustomizeDiff computes the hash of the file content when planning, so changes to a file referenced by
+// source are detected and cause the file to be uploaded again.
+func resourceFileCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, client interface{}) error {
+	if !d.NewValueKnown("content") || !d.NewValueKnown("source") {
+		return d.SetNewComputed("content_hash")
+	}
+	content, err := fileContent(d.Get("content").(string), d.Get("source").(string))
+	if err != nil {
+		return err
+	}
+	return d.SetNew("content_hash", contentHash(content))
+}
+
+// suppressImportedFileContent suppresses the diff of content or source after an import, which cannot set them, as long
+// as the configured content has the content_hash of the file in Humio.
+func suppressImportedFileContent(k, old, new string, d *schema.ResourceData) bool {
+	if old != "" || new == "" {
+		return false
+	}
+	content := []byte(new)
+	if k == "source" {
+		var err error
+		if content, err = fileContent("", new); err != nil {
+			return false
+		}
+	}
+	hash, _ := d.GetChange("content_hash")
+	return contentHash(content) == hash.(string)
+}
+
+func resourceFileCreate(ctx context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+	diags := resourceFileUpload(ctx, d, client)
+	if diags.HasError() {
+		return diags
+	}
+	d.SetId(fmt.Sprintf("%s+%s", d.Get("repository"), d.Get("name")))
+
+	return resourceFileRead(ctx, d, client)
+}
+
+func resourceFileRead(ctx context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+	// If we don't have a repository when importing, we parse it from the ID.
+	if _, ok := d.GetOk("repository"); !ok {
+		parts := parseRepositoryAndID(d.Id())
+		//we check that we have parsed the id into the correct number of segments
+		if parts[0] == "" || parts[1] == "" {
+			return diag.Errorf("error importing humio_file. Please make sure the ID is in the form REPOSITORYNAME+FILENAME (i.e. myRepoName+myFileName.csv")
+		}
+		err := d.Set("repository", parts[0])
+		if err != nil {
+			return diag.Errorf("error setting repository for resource %s: %s", d.Id(), err)
+		}
+		err = d.Set("name", parts[1])
+		if err != nil {
+			return diag.Errorf("error setting name for resource %s: %s", d.Id(), err)
+		}
+	}
+
+	// The content is hashed while it is downloaded rather than stored, so a file changed outside of Terraform shows up
+	// as a change of content_hash against the hash computed from the configuration.
+	var hash string
+	err := client.(*apiClient).retry(ctx, "get file", func() error {
+		h := sha256.New()
+		err := client.(*apiClient).downloadFile(
+			ctx,
+			d.Get("repository").(string),
+			d.Get("name").(string),
+			h,
+		)
+		hash = hex.EncodeToString(h.Sum(nil))
+		return err
+	})
+	if removeFromStateIfNotFound(d, "humio_file", err) {
+		return nil
+	}
+	if err != nil {
+		return diag.Errorf("could not get file: %s", err)
+	}
+	err = d.Set("content_hash", hash)
+	if err != nil {
+		return diag.Errorf("error setting content_hash for resource %s: %s", d.Id(), err)
+	}
+	return nil
+}
+
+func resourceFileUpdate(ctx context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+	diags := resourceFileUpload(ctx, d, client)
+	if diags.HasError() {
+		return diags
+	}
+	return resourceFileRead(ctx, d, client)
+}
+
+func resourceFileUpload(ctx context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+	content, err := fileContent(d.Get("content").(string), d.Get("source").(string))
+	if err != nil {
+		return diag.Errorf("could not obtain file from resource data: %s", err)
+	}
+
+	err = client.(*apiClient).retry(ctx, "upload file", func() error {
+		return client.(*apiClient).uploadFile(
+			ctx,
+			d.Get("repository").(string),
+			d.Get("name").(string),
+			bytes.NewReader(content),
+		)
+	})
+	if err != nil {
+		return diag.Errorf("could not upload file: %s", err)
+	}
+	return nil
+}
+
+func resourceFileDelete(ctx context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+	err := client.(*apiClient).call(ctx, func() error {
+		return client.(*apiClient).removeFile(
+			ctx,
+			d.Get("repository").(string),
+			d.Get("name").(string),
+		)
+	})
+	if err != nil {
+		return diag.Errorf("could not delete file: %s", err)
+	}
+	return nil
+}
+
+// fileContent returns content, or the content of the local file at source if content is empty.
+func fileContent(content, source string) ([]byte, error) {
+	if source == "" {
+		return []byte(content), nil
+	}
+	b, err := ioutil.ReadFile(source)
+	if err != nil {
+		return nil, fmt.Errorf("could not read source: %s", err)
+	}
+	return b, nil
+}
+
+func contentHash(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,278 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/shurcooL/graphql"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccFileRequiredFields(t *testing.T) {
+	config := fileEmpty
+	accTestCase(t, []resource.TestStep{
+		{Config: config, ExpectError: regexp.MustCompile(`The argument "repository" is required, but no definition was found.`)},
+		{Config: config, ExpectError: regexp.MustCompile(`The argument "name" is required, but no definition was found.`)},
+		{Config: config, ExpectError: regexp.MustCompile(`"content": one of ` + "`content,source`" + ` must be specified`)},
+	}, nil)
+}
+
+func TestAccFileContentAndSource(t *testing.T) {
+	accTestCase(t, []resource.TestStep{
+		{Config: fileContentAndSource, ExpectError: regexp.MustCompile(`"content": only one of ` + "`content,source`" + ` can be specified`)},
+	}, nil)
+}
+
+func TestAccFileContentToSource(t *testing.T) {
+	accTestCase(t, []resource.TestStep{
+		{
+			Config: fileInline,
+			Check: resource.ComposeTestCheckFunc(
+				resource.TestCheckResourceAttr("humio_file.test", "repository", "sandbox"),
+				resource.TestCheckResourceAttr("humio_file.test", "name", testAccPrefix+"file.csv"),
+				resource.TestCheckResourceAttr("humio_file.test", "content_hash", contentHash([]byte("host,team\nweb-1,frontend\n"))),
+			),
+		},
+		{
+			// A file changed outside of Terraform is uploaded again.
+			PreConfig: func() {
+				conn := testAccProviders["humio"].Meta().(*apiClient)
+				if err := conn.uploadFile(context.Background(), "sandbox", testAccPrefix+"file.csv", strings.NewReader("host,team\n")); err != nil {
+					t.Fatal(err)
+				}
+			},
+			Config: fileInline,
+			Check: resource.ComposeTestCheckFunc(
+				resource.TestCheckResourceAttr("humio_file.test", "content_hash", contentHash([]byte("host,team\nweb-1,frontend\n"))),
+			),
+		},
+		{
+			Config: fileSource,
+			Check: resource.ComposeTestCheckFunc(
+				resource.TestCheckResourceAttr("humio_file.test", "content_hash", "d994c917500007ff8965c72a4111f25ae8408bc1511a47aacf492d224d191363"),
+			),
+		},
+		{
+			ResourceName:            "humio_file.test",
+			ImportState:             true,
+			ImportStateId:           "sandbox+" + testAccPrefix + "file.csv",
+			ImportStateVerify:       true,
+			ImportStateVerifyIgnore: []string{"content", "source"},
+		},
+	}, testAccCheckFileDestroy)
+}
+
+func testAccCheckFileDestroy(s *terraform.State) error {
+	conn := testAccProviders["humio"].Meta().(*apiClient)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "humio_file" {
+			continue
+		}
+		exists, err := conn.fileExists(context.Background(), rs.Primary.Attributes["repository"], rs.Primary.Attributes["name"])
+		if err != nil {
+			return err
+		}
+		if exists {
+			return fmt.Errorf("file still exists: %s", rs.Primary.ID)
+		}
+	}
+	return nil
+}
+
+const fileEmpty = `
+resource "humio_file" "test" {}
+`
+
+const fileContentAndSource = `
+resource "humio_file" "test" {
+	repository = "sandbox"
+	name       = "` + testAccPrefix + `file.csv"
+	content    = "host,team\n"
+	source     = "testdata/hosts.csv"
+}
+`
+
+const fileInline = `
+resource "humio_file" "test" {
+	repository = "sandbox"
+	name       = "` + testAccPrefix + `file.csv"
+	content    = "host,team\nweb-1,frontend\n"
+}
+`
+
+const fileSource = `
+resource "humio_file" "test" {
+	repository = "sandbox"
+	name       = "` + testAccPrefix + `file.csv"
+	source     = "testdata/hosts.csv"
+}
+`
+
+func TestFileContent(t *testing.T) {
+	got, err := fileContent("a,b\n", "")
+	if err != nil || string(got) != "a,b\n" {
+		t.Errorf("expected inline content, got %q and error %v", got, err)
+	}
+
+	want, _ := ioutil.ReadFile("testdata/hosts.csv")
+	got, err = fileContent("", "testdata/hosts.csv")
+	if err != nil || string(got) != string(want) {
+		t.Errorf("expected content of source, got %q and error %v", got, err)
+	}
+
+	if _, err := fileContent("", "testdata/missing.csv"); err == nil {
+		t.Error("expected an error for a missing source")
+	}
+}
+
+func TestUploadFile(t *testing.T) {
+	var gotPath, gotName, gotContent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		f, header, err := r.FormFile("file")
+		if err != nil {
+			t.Error(err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		defer f.Close()
+		b, _ := ioutil.ReadAll(f)
+		gotName, gotContent = header.Filename, string(b)
+	}))
+	defer server.Close()
+
+	address, _ := url.Parse(server.URL)
+	c := newAPIClient(address, "secret", http.DefaultTransport)
+
+	if err := c.uploadFile(context.Background(), "sandbox", "hosts.csv", strings.NewReader("host,team\n")); err != nil {
+		t.Fatal(err)
+	}
+	if gotPath != "/api/v1/repositories/sandbox/files" || gotName != "hosts.csv" || gotContent != "host,team\n" {
+		t.Errorf("unexpected upload of %s to %s: %q", gotName, gotPath, gotContent)
+	}
+}
+
+func TestDownloadFile(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/repositories/sandbox/files/hosts.csv" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte("host,team\n"))
+	}))
+	defer server.Close()
+
+	address, _ := url.Parse(server.URL)
+	c := newAPIClient(address, "secret", http.DefaultTransport)
+
+	var content bytes.Buffer
+	err := c.downloadFile(context.Background(), "sandbox", "hosts.csv", &content)
+	if err != nil || content.String() != "host,team\n" {
+		t.Errorf("expected the file content, got %q and error %v", content.String(), err)
+	}
+	if err := c.downloadFile(context.Background(), "sandbox", "missing.csv", ioutil.Discard); !isNotFoundError(err) {
+		t.Errorf("expected a not found error for a missing file, got %v", err)
+	}
+}
+
+// TestFileImportPlanIsEmpty checks that a file imported with the content of its configuration plans no changes, even
+// though the import cannot set content or source.
+func TestFileImportPlanIsEmpty(t *testing.T) {
+	source, _ := ioutil.ReadFile("testdata/hosts.csv")
+	for name, tt := range map[string]struct {
+		config    map[string]interface{}
+		hash      string
+		wantEmpty bool
+	}{
+		"content":         {map[string]interface{}{"content": "host,team\n"}, contentHash([]byte("host,team\n")), true},
+		"source":          {map[string]interface{}{"source": "testdata/hosts.csv"}, contentHash(source), true},
+		"changed content": {map[string]interface{}{"content": "host,team\n"}, contentHash([]byte("host\n")), false},
+		"changed source":  {map[string]interface{}{"source": "testdata/hosts.csv"}, contentHash([]byte("host\n")), false},
+	} {
+		t.Run(name, func(t *testing.T) {
+			state := &terraform.InstanceState{
+				ID: "sandbox+hosts.csv",
+				Attributes: map[string]string{
+					"id":           "sandbox+hosts.csv",
+					"repository":   "sandbox",
+					"name":         "hosts.csv",
+					"content_hash": tt.hash,
+				},
+			}
+			tt.config["repository"] = "sandbox"
+			tt.config["name"] = "hosts.csv"
+
+			diff, err := resourceFile().Diff(context.Background(), state, terraform.NewResourceConfigRaw(tt.config), nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if empty := diff == nil || diff.Empty(); empty != tt.wantEmpty {
+				t.Errorf("expected an empty plan to be %v, got %#v", tt.wantEmpty, diff)
+			}
+		})
+	}
+}
+
+// listFiles returns the names of the lookup files in the repository.
+func (c *apiClient) listFiles(ctx context.Context, repository string) ([]string, error) {
+	var q struct {
+		SearchDomain struct {
+			Files []struct {
+				Name string
+			}
+		} `graphql:"searchDomain(name: $repository)"`
+	}
+
+	variables := map[string]interface{}{
+		"repository": graphql.String(repository),
+	}
+
+	if err := c.query(ctx, &q, variables); err != nil {
+		return nil, err
+	}
+
+	names := make([]string, len(q.SearchDomain.Files))
+	for i, f := range q.SearchDomain.Files {
+		names[i] = f.Name
+	}
+	return names, nil
+}
+
+// fileExists reports whether a lookup file with the given name exists in the repository.
+func (c *apiClient) fileExists(ctx context.Context, repository, name string) (bool, error) {
+	names, err := c.listFiles(ctx, repository)
+	if err != nil {
+		return false, err
+	}
+	for _, n := range names {
+		if n == name {
+			return true, nil
+		}
+	}
+	return false, nil
+}
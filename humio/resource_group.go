@@ -0,0 +1,127 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceGroup() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceGroupCreate,
+		ReadContext:   resourceGroupRead,
+		UpdateContext: resourceGroupUpdate,
+		DeleteContext: resourceGroupDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Timeouts: defaultTimeouts(),
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"lookup_name": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+		},
+	}
+}
+
+func resourceGroupCreate(ctx context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+	group, err := groupFromResourceData(d)
+	if err != nil {
+		return diag.Errorf("could not obtain group from resource data: %s", err)
+	}
+
+	var id string
+	err = client.(*apiClient).call(ctx, func() error {
+		var err error
+		id, err = client.(*apiClient).addGroup(ctx, &group)
+		return err
+	})
+	if err != nil {
+		return diag.Errorf("could not create group: %s", err)
+	}
+	d.SetId(id)
+
+	return resourceGroupRead(ctx, d, client)
+}
+
+func resourceGroupRead(ctx context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+	var g *group
+	err := client.(*apiClient).retry(ctx, "get group", func() error {
+		var err error
+		g, err = client.(*apiClient).getGroup(ctx, d.Id())
+		return err
+	})
+	if removeFromStateIfNotFound(d, "humio_group", err) {
+		return nil
+	}
+	if err != nil {
+		return diag.Errorf("could not get group: %s", err)
+	}
+	return resourceDataFromGroup(g, d)
+}
+
+func resourceDataFromGroup(g *group, d *schema.ResourceData) diag.Diagnostics {
+	err := d.Set("name", g.DisplayName)
+	if err != nil {
+		return diag.Errorf("error setting name for resource %s: %s", d.Id(), err)
+	}
+	err = d.Set("lookup_name", g.LookupName)
+	if err != nil {
+		return diag.Errorf("error setting lookup_name for resource %s: %s", d.Id(), err)
+	}
+	return nil
+}
+
+func resourceGroupUpdate(ctx context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+	group, err := groupFromResourceData(d)
+	if err != nil {
+		return diag.Errorf("could not obtain group from resource data: %s", err)
+	}
+
+	err = client.(*apiClient).retry(ctx, "update group", func() error {
+		return client.(*apiClient).updateGroup(ctx, &group)
+	})
+	if err != nil {
+		return diag.Errorf("could not update group: %s", err)
+	}
+	return resourceGroupRead(ctx, d, client)
+}
+
+func resourceGroupDelete(ctx context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+	err := client.(*apiClient).call(ctx, func() error {
+		return client.(*apiClient).removeGroup(ctx, d.Id())
+	})
+	if err != nil {
+		return diag.Errorf("could not delete group: %s", err)
+	}
+	return nil
+}
+
+func groupFromResourceData(d *schema.ResourceData) (group, error) {
+	return group{
+		ID:          d.Id(),
+		DisplayName: d.Get("name").(string),
+		LookupName:  d.Get("lookup_name").(string),
+	}, nil
+}
@@ -0,0 +1,139 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceGroupRoleAssignment() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceGroupRoleAssignmentCreate,
+		ReadContext:   resourceGroupRoleAssignmentRead,
+		DeleteContext: resourceGroupRoleAssignmentDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: defaultTimeouts().Create,
+			Delete: defaultTimeouts().Delete,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"repository": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"group_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"role_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+		},
+	}
+}
+
+func resourceGroupRoleAssignmentCreate(ctx context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+	groupID, role := groupRoleFromResourceData(d)
+
+	err := client.(*apiClient).call(ctx, func() error {
+		return client.(*apiClient).assignRoleToGroup(ctx, groupID, role)
+	})
+	if err != nil {
+		return diag.Errorf("could not assign role to group: %s", err)
+	}
+	d.SetId(fmt.Sprintf("%s+%s+%s", role.Repository, groupID, role.RoleID))
+
+	return resourceGroupRoleAssignmentRead(ctx, d, client)
+}
+
+func resourceGroupRoleAssignmentRead(ctx context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+	// If we don't have a repository when importing, we parse it from the ID.
+	if _, ok := d.GetOk("repository"); !ok {
+		parts := strings.SplitN(d.Id(), "+", 3)
+		if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+			return diag.Errorf("error importing humio_group_role_assignment. Please make sure the ID is in the form REPOSITORYNAME+GROUPID+ROLEID (i.e. myRepoName+myGroupID+myRoleID")
+		}
+		err := d.Set("repository", parts[0])
+		if err != nil {
+			return diag.Errorf("error setting repository for resource %s: %s", d.Id(), err)
+		}
+		err = d.Set("group_id", parts[1])
+		if err != nil {
+			return diag.Errorf("error setting group_id for resource %s: %s", d.Id(), err)
+		}
+		err = d.Set("role_id", parts[2])
+		if err != nil {
+			return diag.Errorf("error setting role_id for resource %s: %s", d.Id(), err)
+		}
+	}
+
+	groupID, role := groupRoleFromResourceData(d)
+	var roles []groupRole
+	err := client.(*apiClient).retry(ctx, "get roles for group", func() error {
+		var err error
+		roles, err = client.(*apiClient).getGroupRoles(ctx, groupID)
+		return err
+	})
+	if err == nil && !containsGroupRole(roles, role) {
+		err = newNotFoundError("role %s for group %s in %s", role.RoleID, groupID, role.Repository)
+	}
+	if removeFromStateIfNotFound(d, "humio_group_role_assignment", err) {
+		return nil
+	}
+	if err != nil {
+		return diag.Errorf("could not get roles for group: %s", err)
+	}
+	return nil
+}
+
+func resourceGroupRoleAssignmentDelete(ctx context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+	groupID, role := groupRoleFromResourceData(d)
+
+	err := client.(*apiClient).call(ctx, func() error {
+		return client.(*apiClient).unassignRoleFromGroup(ctx, groupID, role)
+	})
+	if err != nil {
+		return diag.Errorf("could not unassign role from group: %s", err)
+	}
+	return nil
+}
+
+func groupRoleFromResourceData(d *schema.ResourceData) (string, groupRole) {
+	return d.Get("group_id").(string), groupRole{
+		RoleID:     d.Get("role_id").(string),
+		Repository: d.Get("repository").(string),
+	}
+}
+
+func containsGroupRole(roles []groupRole, role groupRole) bool {
+	for _, r := range roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
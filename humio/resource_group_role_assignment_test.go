@@ -0,0 +1,89 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccGroupRoleAssignmentRequiredFields(t *testing.T) {
+	config := groupRoleAssignmentEmpty
+	accTestCase(t, []resource.TestStep{
+		{Config: config, ExpectError: regexp.MustCompile(`The argument "repository" is required, but no definition was found.`)},
+		{Config: config, ExpectError: regexp.MustCompile(`The argument "group_id" is required, but no definition was found.`)},
+		{Config: config, ExpectError: regexp.MustCompile(`The argument "role_id" is required, but no definition was found.`)},
+	}, nil)
+}
+
+func TestAccGroupRoleAssignmentBasic(t *testing.T) {
+	accTestCase(t, []resource.TestStep{
+		{
+			Config: groupRoleAssignmentBasic,
+			Check: resource.ComposeTestCheckFunc(
+				resource.TestCheckResourceAttr("humio_group_role_assignment.test", "repository", "sandbox"),
+				resource.TestCheckResourceAttrPair("humio_group_role_assignment.test", "group_id", "humio_group.test", "id"),
+				resource.TestCheckResourceAttrPair("humio_group_role_assignment.test", "role_id", "humio_role.test", "id"),
+			),
+		},
+		{
+			ResourceName:      "humio_group_role_assignment.test",
+			ImportState:       true,
+			ImportStateVerify: true,
+		},
+	}, testAccCheckGroupRoleAssignmentDestroy)
+}
+
+func testAccCheckGroupRoleAssignmentDestroy(s *terraform.State) error {
+	conn := testAccProviders["humio"].Meta().(*apiClient)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "humio_group_role_assignment" {
+			continue
+		}
+		roles, err := conn.getGroupRoles(context.Background(), rs.Primary.Attributes["group_id"])
+		if err == nil && containsGroupRole(roles, groupRole{RoleID: rs.Primary.Attributes["role_id"], Repository: rs.Primary.Attributes["repository"]}) {
+			return fmt.Errorf("role assignment still exists: %s", rs.Primary.ID)
+		}
+	}
+	return nil
+}
+
+const groupRoleAssignmentEmpty = `
+resource "humio_group_role_assignment" "test" {}
+`
+
+const groupRoleAssignmentBasic = `
+resource "humio_group" "test" {
+	name = "` + testAccPrefix + `group-role-assignment"
+}
+
+resource "humio_role" "test" {
+	name             = "` + testAccPrefix + `group-role-assignment"
+	view_permissions = ["ReadAccess"]
+}
+
+resource "humio_group_role_assignment" "test" {
+	repository = "sandbox"
+	group_id   = humio_group.test.id
+	role_id    = humio_role.test.id
+}
+`
@@ -0,0 +1,165 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/shurcooL/graphql"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccGroupRequiredFields(t *testing.T) {
+	config := groupEmpty
+	accTestCase(t, []resource.TestStep{
+		{Config: config, ExpectError: regexp.MustCompile(`The argument "name" is required, but no definition was found.`)},
+	}, nil)
+}
+
+func TestAccGroupInvalidInputs(t *testing.T) {
+	config := groupInvalidInputs
+	accTestCase(t, []resource.TestStep{
+		{Config: config, ExpectError: regexp.MustCompile(`Inappropriate value for attribute "name"`)},
+		{Config: config, ExpectError: regexp.MustCompile(`Inappropriate value for attribute "lookup_name"`)},
+	}, nil)
+}
+
+func TestAccGroupBasicToFull(t *testing.T) {
+	accTestCase(t, []resource.TestStep{
+		{
+			Config: groupBasic,
+			Check: resource.ComposeTestCheckFunc(
+				resource.TestCheckResourceAttr("humio_group.test", "name", testAccPrefix+"group"),
+				resource.TestCheckResourceAttr("humio_group.test", "lookup_name", ""),
+			),
+		},
+		{
+			Config: groupFull,
+			Check: resource.ComposeTestCheckFunc(
+				resource.TestCheckResourceAttr("humio_group.test", "name", testAccPrefix+"group-renamed"),
+				resource.TestCheckResourceAttr("humio_group.test", "lookup_name", "cn=ops,ou=groups,dc=example,dc=com"),
+			),
+		},
+		{
+			ResourceName:      "humio_group.test",
+			ImportState:       true,
+			ImportStateVerify: true,
+		},
+	}, testAccCheckGroupDestroy)
+}
+
+func testAccCheckGroupDestroy(s *terraform.State) error {
+	conn := testAccProviders["humio"].Meta().(*apiClient)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "humio_group" {
+			continue
+		}
+		_, err := conn.getGroup(context.Background(), rs.Primary.ID)
+		if err == nil {
+			return fmt.Errorf("group still exists: %s", rs.Primary.ID)
+		}
+	}
+	return nil
+}
+
+const groupEmpty = `
+resource "humio_group" "test" {}
+`
+
+const groupInvalidInputs = `
+resource "humio_group" "test" {
+	name        = ["invalid"]
+	lookup_name = ["invalid"]
+}
+`
+
+const groupBasic = `
+resource "humio_group" "test" {
+	name = "` + testAccPrefix + `group"
+}
+`
+
+const groupFull = `
+resource "humio_group" "test" {
+	name        = "` + testAccPrefix + `group-renamed"
+	lookup_name = "cn=ops,ou=groups,dc=example,dc=com"
+}
+`
+
+var wantGroup = group{
+	ID:          "",
+	DisplayName: "ops",
+	LookupName:  "cn=ops,ou=groups,dc=example,dc=com",
+}
+
+func TestEncodeDecodeGroupResource(t *testing.T) {
+	res := resourceGroup()
+	data := res.TestResourceData()
+	resourceDataFromGroup(&wantGroup, data)
+	got, err := groupFromResourceData(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cmp.Equal(wantGroup, got) {
+		t.Error(cmp.Diff(wantGroup, got))
+	}
+}
+
+// groupsPageSize is the number of groups fetched per request when listing groups.
+const groupsPageSize = 100
+
+// listGroups returns the groups whose names contain search, or every group if search is empty.
+func (c *apiClient) listGroups(ctx context.Context, search string) ([]group, error) {
+	var groups []group
+	for page := 1; ; page++ {
+		var q struct {
+			GroupsPage struct {
+				Page []struct {
+					ID          string
+					DisplayName string
+					LookupName  string
+				}
+			} `graphql:"groupsPage(search: $search, pageNumber: $page, pageSize: $pageSize)"`
+		}
+
+		variables := map[string]interface{}{
+			"search":   optionalString(search),
+			"page":     graphql.Int(page),
+			"pageSize": graphql.Int(groupsPageSize),
+		}
+
+		if err := c.query(ctx, &q, variables); err != nil {
+			return nil, err
+		}
+
+		for _, g := range q.GroupsPage.Page {
+			groups = append(groups, group{
+				ID:          g.ID,
+				DisplayName: g.DisplayName,
+				LookupName:  g.LookupName,
+			})
+		}
+		if len(q.GroupsPage.Page) < groupsPageSize {
+			return groups, nil
+		}
+	}
+}
@@ -33,6 +33,7 @@ func resourceIngestToken() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
+		Timeouts: defaultTimeouts(),
 
 		Schema: map[string]*schema.Schema{
 			"repository": {
@@ -65,11 +66,16 @@ func resourceIngestTokenCreate(ctx context.Context, d *schema.ResourceData, clie
 		return diag.Errorf("could not obtain alert from resource data: %s", err)
 	}
 
-	_, err = client.(*humio.Client).IngestTokens().Add(
-		d.Get("repository").(string),
-		ingestToken.Name,
-		ingestToken.AssignedParser,
-	)
+	err = client.(*apiClient).call(ctx, func() error {
+		_, err := client.(*apiClient).addIngestToken(
+			ctx,
+			d.Get("repository").(string),
+			ingestToken.Name,
+			ingestToken.AssignedParser,
+		)
+		return err
+	})
+	invalidateCachedListing(d, client, cacheIngestTokens)
 	if err != nil {
 		return diag.Diagnostics{diag.Diagnostic{
 			Severity:      diag.Error,
@@ -83,7 +89,7 @@ func resourceIngestTokenCreate(ctx context.Context, d *schema.ResourceData, clie
 	return resourceIngestTokenRead(ctx, d, client)
 }
 
-func resourceIngestTokenRead(_ context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+func resourceIngestTokenRead(ctx context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
 	// If we don't have a repository when importing, we parse it from the ID.
 	if _, ok := d.GetOk("repository"); !ok {
 		parts := parseRepositoryAndID(d.Id())
@@ -101,10 +107,19 @@ func resourceIngestTokenRead(_ context.Context, d *schema.ResourceData, client i
 		}
 	}
 
-	ingestToken, err := client.(*humio.Client).IngestTokens().Get(
-		d.Get("repository").(string),
-		d.Get("name").(string),
-	)
+	var ingestToken *humio.IngestToken
+	err := client.(*apiClient).retry(ctx, "get ingest token", func() error {
+		var err error
+		ingestToken, err = client.(*apiClient).getIngestToken(
+			ctx,
+			d.Get("repository").(string),
+			d.Get("name").(string),
+		)
+		return err
+	})
+	if removeFromStateIfNotFound(d, "humio_ingest_token", err) {
+		return nil
+	}
 	if err != nil {
 		return diag.Errorf("could not get ingest token: %s", err)
 	}
@@ -133,27 +148,35 @@ func resourceIngestTokenUpdate(ctx context.Context, d *schema.ResourceData, clie
 		return diag.Errorf("could not obtain alert from resource data: %s", err)
 	}
 
-	_, err = client.(*humio.Client).IngestTokens().Update(
-		d.Get("repository").(string),
-		ingestToken.Name,
-		ingestToken.AssignedParser,
-	)
+	err = client.(*apiClient).retry(ctx, "update ingest token", func() error {
+		return client.(*apiClient).assignIngestToken(
+			ctx,
+			d.Get("repository").(string),
+			ingestToken.Name,
+			ingestToken.AssignedParser,
+		)
+	})
+	invalidateCachedListing(d, client, cacheIngestTokens)
 	if err != nil {
 		return diag.Errorf("could not update ingest token: %s", err)
 	}
 	return resourceIngestTokenRead(ctx, d, client)
 }
 
-func resourceIngestTokenDelete(_ context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+func resourceIngestTokenDelete(ctx context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
 	ingestToken, err := ingestTokenFromResourceData(d)
 	if err != nil {
 		return diag.Errorf("could not obtain alert from resource data: %s", err)
 	}
 
-	err = client.(*humio.Client).IngestTokens().Remove(
-		d.Get("repository").(string),
-		ingestToken.Name,
-	)
+	err = client.(*apiClient).call(ctx, func() error {
+		return client.(*apiClient).removeIngestToken(
+			ctx,
+			d.Get("repository").(string),
+			ingestToken.Name,
+		)
+	})
+	invalidateCachedListing(d, client, cacheIngestTokens)
 	if err != nil {
 		return diag.Errorf("could not delete ingest token: %s", err)
 	}
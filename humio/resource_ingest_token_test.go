@@ -15,6 +15,7 @@
 package humio
 
 import (
+	"context"
 	"fmt"
 	"regexp"
 	"testing"
@@ -56,7 +57,7 @@ func TestAccIngestTokenBasic(t *testing.T) {
 			Config: ingestTokenBasic,
 			Check: resource.ComposeTestCheckFunc(
 				resource.TestCheckResourceAttr("humio_ingest_token.test", "repository", "sandbox"),
-				resource.TestCheckResourceAttr("humio_ingest_token.test", "name", "ingest-token-test"),
+				resource.TestCheckResourceAttr("humio_ingest_token.test", "name", testAccPrefix+"ingest-token"),
 				resource.TestCheckResourceAttr("humio_ingest_token.test", "parser", ""),
 				resource.TestCheckResourceAttrSet("humio_ingest_token.test", "token"),
 			),
@@ -70,7 +71,7 @@ func TestAccIngestTokenBasicToFull(t *testing.T) {
 			Config: ingestTokenBasic,
 			Check: resource.ComposeTestCheckFunc(
 				resource.TestCheckResourceAttr("humio_ingest_token.test", "repository", "sandbox"),
-				resource.TestCheckResourceAttr("humio_ingest_token.test", "name", "ingest-token-test"),
+				resource.TestCheckResourceAttr("humio_ingest_token.test", "name", testAccPrefix+"ingest-token"),
 				resource.TestCheckResourceAttr("humio_ingest_token.test", "parser", ""),
 				resource.TestCheckResourceAttrSet("humio_ingest_token.test", "token"),
 			),
@@ -79,7 +80,7 @@ func TestAccIngestTokenBasicToFull(t *testing.T) {
 			Config: ingestTokenFull,
 			Check: resource.ComposeTestCheckFunc(
 				resource.TestCheckResourceAttr("humio_ingest_token.test", "repository", "sandbox"),
-				resource.TestCheckResourceAttr("humio_ingest_token.test", "name", "ingest-token-test"),
+				resource.TestCheckResourceAttr("humio_ingest_token.test", "name", testAccPrefix+"ingest-token"),
 				resource.TestCheckResourceAttr("humio_ingest_token.test", "parser", "json"),
 				resource.TestCheckResourceAttrSet("humio_ingest_token.test", "token"),
 			),
@@ -90,7 +91,7 @@ func TestAccIngestTokenBasicToFull(t *testing.T) {
 			Config: ingestTokenFull,
 			Check: resource.ComposeTestCheckFunc(
 				resource.TestCheckResourceAttr("humio_ingest_token.test", "repository", "sandbox"),
-				resource.TestCheckResourceAttr("humio_ingest_token.test", "name", "ingest-token-test"),
+				resource.TestCheckResourceAttr("humio_ingest_token.test", "name", testAccPrefix+"ingest-token"),
 				resource.TestCheckResourceAttr("humio_ingest_token.test", "parser", "json"),
 				resource.TestCheckResourceAttrSet("humio_ingest_token.test", "token"),
 			),
@@ -104,7 +105,7 @@ func TestAccIngestTokenFull(t *testing.T) {
 			Config: ingestTokenFull,
 			Check: resource.ComposeTestCheckFunc(
 				resource.TestCheckResourceAttr("humio_ingest_token.test", "repository", "sandbox"),
-				resource.TestCheckResourceAttr("humio_ingest_token.test", "name", "ingest-token-test"),
+				resource.TestCheckResourceAttr("humio_ingest_token.test", "name", testAccPrefix+"ingest-token"),
 				resource.TestCheckResourceAttr("humio_ingest_token.test", "parser", "json"),
 				resource.TestCheckResourceAttrSet("humio_ingest_token.test", "token"),
 			),
@@ -113,14 +114,14 @@ func TestAccIngestTokenFull(t *testing.T) {
 }
 
 func testAccCheckIngestTokenDestroy(s *terraform.State) error {
-	conn := testAccProviders["humio"].Meta().(*humio.Client)
+	conn := testAccProviders["humio"].Meta().(*apiClient)
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "humio_ingest_token" {
 			continue
 		}
 		// TODO: Use rs.Primary.ID to figure out if ingest token exists, and not just list all ingest tokens.
-		resp, err := conn.IngestTokens().List("sandbox")
+		resp, err := conn.listIngestTokens(context.Background(), "sandbox")
 		if err == nil {
 			if len(resp) > 1 { // by default there is an ingest token called "default"
 				return fmt.Errorf("ingest tokens still exist: %#+v", resp)
@@ -146,14 +147,14 @@ resource "humio_ingest_token" "test" {
 const ingestTokenBasic = `
 resource "humio_ingest_token" "test" {
 	repository = "sandbox"
-	name       = "ingest-token-test"
+	name       = "` + testAccPrefix + `ingest-token"
 }
 `
 
 const ingestTokenFull = `
 resource "humio_ingest_token" "test" {
 	repository = "sandbox"
-	name       = "ingest-token-test"
+	name       = "` + testAccPrefix + `ingest-token"
 	parser     = "json"
 }
 `
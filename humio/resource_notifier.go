@@ -18,7 +18,6 @@ import (
 	"context"
 	"fmt"
 	"net/http"
-	"reflect"
 	"regexp"
 
 	"github.com/hashicorp/go-cty/cty"
@@ -29,6 +28,9 @@ import (
 	humio "github.com/humio/cli/api"
 )
 
+// defaultWebhookBodyTemplate is the body sent by webhooks when no template is given.
+const defaultWebhookBodyTemplate = "{\n  \"repository\": \"{repo_name}\",\n  \"timestamp\": \"{alert_triggered_timestamp}\",\n  \"alert\": {\n    \"name\": \"{alert_name}\",\n    \"description\": \"{alert_description}\",\n    \"query\": {\n      \"queryString\": \"{query_string} \",\n      \"end\": \"{query_time_end}\",\n      \"start\": \"{query_time_start}\"\n    },\n    \"notifierID\": \"{alert_notifier_id}\",\n    \"id\": \"{alert_id}\"\n  },\n  \"warnings\": \"{warnings}\",\n  \"events\": {events},\n  \"numberOfEvents\": {event_count}\n  }"
+
 var rxEmail = regexp.MustCompile("^[a-zA-Z0-9.!#$%&'*+\\/=?^_`{|}~-]+@[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?(?:\\.[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)*$")
 
 func resourceNotifier() *schema.Resource {
@@ -40,6 +42,7 @@ func resourceNotifier() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
+		Timeouts: defaultTimeouts(),
 
 		Schema: map[string]*schema.Schema{
 			"notifier_id": {
@@ -85,14 +88,8 @@ func resourceNotifier() *schema.Resource {
 							Required: true,
 							MinItems: 1,
 							Elem: &schema.Schema{
-								Type: schema.TypeString,
-								ValidateDiagFunc: func(val interface{}, key cty.Path) diag.Diagnostics {
-									v := val.(string)
-									if len(v) > 254 || !rxEmail.MatchString(v) {
-										return diag.FromErr(fmt.Errorf("%q must be a valid email, got: %s", key, v))
-									}
-									return nil
-								},
+								Type:             schema.TypeString,
+								ValidateDiagFunc: validateEmail,
 							},
 						},
 						"subject_template": {
@@ -245,7 +242,7 @@ func resourceNotifier() *schema.Resource {
 						"body_template": {
 							Type:     schema.TypeString,
 							Optional: true,
-							Default:  "{\n  \"repository\": \"{repo_name}\",\n  \"timestamp\": \"{alert_triggered_timestamp}\",\n  \"alert\": {\n    \"name\": \"{alert_name}\",\n    \"description\": \"{alert_description}\",\n    \"query\": {\n      \"queryString\": \"{query_string} \",\n      \"end\": \"{query_time_end}\",\n      \"start\": \"{query_time_start}\"\n    },\n    \"notifierID\": \"{alert_notifier_id}\",\n    \"id\": \"{alert_id}\"\n  },\n  \"warnings\": \"{warnings}\",\n  \"events\": {events},\n  \"numberOfEvents\": {event_count}\n  }",
+							Default:  defaultWebhookBodyTemplate,
 						},
 						"headers": {
 							Type:     schema.TypeMap,
@@ -276,26 +273,37 @@ func resourceNotifier() *schema.Resource {
 	}
 }
 
+func validateEmail(val interface{}, key cty.Path) diag.Diagnostics {
+	v := val.(string)
+	if len(v) > 254 || !rxEmail.MatchString(v) {
+		return diag.FromErr(fmt.Errorf("%q must be a valid email, got: %s", key, v))
+	}
+	return nil
+}
+
 func resourceNotifierCreate(ctx context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
 	notifier, err := notifierFromResourceData(d)
 	if err != nil {
 		return diag.Errorf("could not obtain notifier from resource data: %s", err)
 	}
 
-	n, err := client.(*humio.Client).Notifiers().Add(
-		d.Get("repository").(string),
-		&notifier,
-		false,
-	)
+	err = client.(*apiClient).call(ctx, func() error {
+		return client.(*apiClient).createNotifier(
+			ctx,
+			d.Get("repository").(string),
+			&notifier,
+		)
+	})
+	invalidateCachedListing(d, client, cacheNotifiers)
 	if err != nil {
 		return diag.Errorf("could not create notifier: %s", err)
 	}
-	d.SetId(fmt.Sprintf("%s+%s", d.Get("repository").(string), n.Name))
+	d.SetId(fmt.Sprintf("%s+%s", d.Get("repository").(string), notifier.Name))
 
 	return resourceNotifierRead(ctx, d, client)
 }
 
-func resourceNotifierRead(_ context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+func resourceNotifierRead(ctx context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
 	parts := parseRepositoryAndID(d.Id())
 	// If we don't have a repository when importing, we parse it from the ID.
 	if _, ok := d.GetOk("repository"); !ok {
@@ -313,11 +321,20 @@ func resourceNotifierRead(_ context.Context, d *schema.ResourceData, client inte
 		}
 	}
 
-	notifier, err := client.(*humio.Client).Notifiers().Get(
-		d.Get("repository").(string),
-		d.Get("name").(string),
-	)
-	if err != nil || reflect.DeepEqual(*notifier, humio.Notifier{}) {
+	var notifier *humio.Notifier
+	err := client.(*apiClient).retry(ctx, "get notifier", func() error {
+		var err error
+		notifier, err = client.(*apiClient).getNotifier(
+			ctx,
+			d.Get("repository").(string),
+			d.Get("name").(string),
+		)
+		return err
+	})
+	if removeFromStateIfNotFound(d, "humio_notifier", err) {
+		return nil
+	}
+	if err != nil {
 		return diag.Errorf("could not get notifier: %s", err)
 	}
 	return resourceDataFromNotifier(notifier, d)
@@ -383,11 +400,14 @@ func resourceNotifierUpdate(ctx context.Context, d *schema.ResourceData, client
 		return diag.Errorf("could not obtain notifier from resource data: %s", err)
 	}
 
-	_, err = client.(*humio.Client).Notifiers().Add(
-		d.Get("repository").(string),
-		&notifier,
-		true,
-	)
+	err = client.(*apiClient).retry(ctx, "update notifier", func() error {
+		return client.(*apiClient).updateNotifier(
+			ctx,
+			d.Get("repository").(string),
+			&notifier,
+		)
+	})
+	invalidateCachedListing(d, client, cacheNotifiers)
 	if err != nil {
 		return diag.Errorf("could not update notifier: %s", err)
 	}
@@ -395,16 +415,20 @@ func resourceNotifierUpdate(ctx context.Context, d *schema.ResourceData, client
 	return resourceNotifierRead(ctx, d, client)
 }
 
-func resourceNotifierDelete(_ context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+func resourceNotifierDelete(ctx context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
 	notifier, err := notifierFromResourceData(d)
 	if err != nil {
 		return diag.Errorf("could not obtain notifier from resource data: %s", err)
 	}
 
-	err = client.(*humio.Client).Notifiers().Delete(
-		d.Get("repository").(string),
-		notifier.Name,
-	)
+	err = client.(*apiClient).call(ctx, func() error {
+		return client.(*apiClient).deleteNotifier(
+			ctx,
+			d.Get("repository").(string),
+			notifier.Name,
+		)
+	})
+	invalidateCachedListing(d, client, cacheNotifiers)
 	if err != nil {
 		return diag.Errorf("could not delete notifier: %s", err)
 	}
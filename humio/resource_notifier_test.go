@@ -15,10 +15,9 @@
 package humio
 
 import (
+	"context"
 	"fmt"
-	"reflect"
 	"regexp"
-	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -139,7 +138,7 @@ func TestAccNotifierEmailBasic(t *testing.T) {
 				resource.TestCheckResourceAttrSet("humio_notifier.test", "notifier_id"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "repository", "sandbox"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "entity", "EmailNotifier"),
-				resource.TestCheckResourceAttr("humio_notifier.test", "name", "notifier-email-test"),
+				resource.TestCheckResourceAttr("humio_notifier.test", "name", testAccPrefix+"notifier-email"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "email.#", "1"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "email.0.recipients.#", "1"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "email.0.recipients.0", "test@example.org"),
@@ -166,7 +165,7 @@ func TestAccNotifierEmailBasicToFull(t *testing.T) {
 				resource.TestCheckResourceAttrSet("humio_notifier.test", "notifier_id"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "repository", "sandbox"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "entity", "EmailNotifier"),
-				resource.TestCheckResourceAttr("humio_notifier.test", "name", "notifier-email-test"),
+				resource.TestCheckResourceAttr("humio_notifier.test", "name", testAccPrefix+"notifier-email"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "email.#", "1"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "email.0.recipients.#", "1"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "email.0.recipients.0", "test@example.org"),
@@ -188,7 +187,7 @@ func TestAccNotifierEmailBasicToFull(t *testing.T) {
 				resource.TestCheckResourceAttrSet("humio_notifier.test", "notifier_id"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "repository", "sandbox"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "entity", "EmailNotifier"),
-				resource.TestCheckResourceAttr("humio_notifier.test", "name", "notifier-email-test"),
+				resource.TestCheckResourceAttr("humio_notifier.test", "name", testAccPrefix+"notifier-email"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "email.#", "1"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "email.0.recipients.#", "2"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "email.0.recipients.0", "test@example.org"),
@@ -213,7 +212,7 @@ func TestAccNotifierEmailBasicToFull(t *testing.T) {
 				resource.TestCheckResourceAttrSet("humio_notifier.test", "notifier_id"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "repository", "sandbox"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "entity", "EmailNotifier"),
-				resource.TestCheckResourceAttr("humio_notifier.test", "name", "notifier-email-test"),
+				resource.TestCheckResourceAttr("humio_notifier.test", "name", testAccPrefix+"notifier-email"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "email.#", "1"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "email.0.recipients.#", "2"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "email.0.recipients.0", "test@example.org"),
@@ -241,7 +240,7 @@ func TestAccNotifierEmailFull(t *testing.T) {
 				resource.TestCheckResourceAttrSet("humio_notifier.test", "notifier_id"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "repository", "sandbox"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "entity", "EmailNotifier"),
-				resource.TestCheckResourceAttr("humio_notifier.test", "name", "notifier-email-test"),
+				resource.TestCheckResourceAttr("humio_notifier.test", "name", testAccPrefix+"notifier-email"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "email.#", "1"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "email.0.recipients.#", "2"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "email.0.recipients.0", "test@example.org"),
@@ -269,7 +268,7 @@ func TestAccNotifierHumioRepoFull(t *testing.T) {
 				resource.TestCheckResourceAttrSet("humio_notifier.test", "notifier_id"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "repository", "sandbox"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "entity", "HumioRepoNotifier"),
-				resource.TestCheckResourceAttr("humio_notifier.test", "name", "notifier-humiorepo-test"),
+				resource.TestCheckResourceAttr("humio_notifier.test", "name", testAccPrefix+"notifier-humiorepo"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "humiorepo.#", "1"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "humiorepo.0.ingest_token", "secrettoken"),
 
@@ -293,7 +292,7 @@ func TestAccNotifierOpsGenieBasic(t *testing.T) {
 				resource.TestCheckResourceAttrSet("humio_notifier.test", "notifier_id"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "repository", "sandbox"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "entity", "OpsGenieNotifier"),
-				resource.TestCheckResourceAttr("humio_notifier.test", "name", "notifier-opsgenie-test"),
+				resource.TestCheckResourceAttr("humio_notifier.test", "name", testAccPrefix+"notifier-opsgenie"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "opsgenie.#", "1"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "opsgenie.0.api_url", "https://api.opsgenie.com"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "opsgenie.0.genie_key", "secretgeniekey"),
@@ -318,7 +317,7 @@ func TestAccNotifierOpsGenieBasicToFull(t *testing.T) {
 				resource.TestCheckResourceAttrSet("humio_notifier.test", "notifier_id"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "repository", "sandbox"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "entity", "OpsGenieNotifier"),
-				resource.TestCheckResourceAttr("humio_notifier.test", "name", "notifier-opsgenie-test"),
+				resource.TestCheckResourceAttr("humio_notifier.test", "name", testAccPrefix+"notifier-opsgenie"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "opsgenie.#", "1"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "opsgenie.0.api_url", "https://api.opsgenie.com"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "opsgenie.0.genie_key", "secretgeniekey"),
@@ -338,7 +337,7 @@ func TestAccNotifierOpsGenieBasicToFull(t *testing.T) {
 				resource.TestCheckResourceAttrSet("humio_notifier.test", "notifier_id"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "repository", "sandbox"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "entity", "OpsGenieNotifier"),
-				resource.TestCheckResourceAttr("humio_notifier.test", "name", "notifier-opsgenie-test"),
+				resource.TestCheckResourceAttr("humio_notifier.test", "name", testAccPrefix+"notifier-opsgenie"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "opsgenie.#", "1"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "opsgenie.0.api_url", "https://127.0.0.1/iasjdojaoijdioajd"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "opsgenie.0.genie_key", "secretgeniekey"),
@@ -360,7 +359,7 @@ func TestAccNotifierOpsGenieBasicToFull(t *testing.T) {
 				resource.TestCheckResourceAttrSet("humio_notifier.test", "notifier_id"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "repository", "sandbox"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "entity", "OpsGenieNotifier"),
-				resource.TestCheckResourceAttr("humio_notifier.test", "name", "notifier-opsgenie-test"),
+				resource.TestCheckResourceAttr("humio_notifier.test", "name", testAccPrefix+"notifier-opsgenie"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "opsgenie.#", "1"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "opsgenie.0.api_url", "https://127.0.0.1/iasjdojaoijdioajd"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "opsgenie.0.genie_key", "secretgeniekey"),
@@ -385,7 +384,7 @@ func TestAccNotifierOpsGenieFull(t *testing.T) {
 				resource.TestCheckResourceAttrSet("humio_notifier.test", "notifier_id"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "repository", "sandbox"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "entity", "OpsGenieNotifier"),
-				resource.TestCheckResourceAttr("humio_notifier.test", "name", "notifier-opsgenie-test"),
+				resource.TestCheckResourceAttr("humio_notifier.test", "name", testAccPrefix+"notifier-opsgenie"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "opsgenie.#", "1"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "opsgenie.0.api_url", "https://127.0.0.1/iasjdojaoijdioajd"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "opsgenie.0.genie_key", "secretgeniekey"),
@@ -410,7 +409,7 @@ func TestAccNotifierPagerDutyFull(t *testing.T) {
 				resource.TestCheckResourceAttrSet("humio_notifier.test", "notifier_id"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "repository", "sandbox"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "entity", "PagerDutyNotifier"),
-				resource.TestCheckResourceAttr("humio_notifier.test", "name", "notifier-pagerduty-test"),
+				resource.TestCheckResourceAttr("humio_notifier.test", "name", testAccPrefix+"notifier-pagerduty"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "pagerduty.#", "1"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "pagerduty.0.routing_key", "secretroutingkey"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "pagerduty.0.severity", "critical"),
@@ -435,7 +434,7 @@ func TestAccNotifierSlackBasic(t *testing.T) {
 				resource.TestCheckResourceAttrSet("humio_notifier.test", "notifier_id"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "repository", "sandbox"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "entity", "SlackNotifier"),
-				resource.TestCheckResourceAttr("humio_notifier.test", "name", "notifier-slack-test"),
+				resource.TestCheckResourceAttr("humio_notifier.test", "name", testAccPrefix+"notifier-slack"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "slack.#", "1"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "slack.0.fields.%", "3"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "slack.0.fields.Events String", "{events_str}"),
@@ -463,7 +462,7 @@ func TestAccNotifierSlackBasicToFull(t *testing.T) {
 				resource.TestCheckResourceAttrSet("humio_notifier.test", "notifier_id"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "repository", "sandbox"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "entity", "SlackNotifier"),
-				resource.TestCheckResourceAttr("humio_notifier.test", "name", "notifier-slack-test"),
+				resource.TestCheckResourceAttr("humio_notifier.test", "name", testAccPrefix+"notifier-slack"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "slack.#", "1"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "slack.0.fields.%", "3"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "slack.0.fields.Events String", "{events_str}"),
@@ -486,7 +485,7 @@ func TestAccNotifierSlackBasicToFull(t *testing.T) {
 				resource.TestCheckResourceAttrSet("humio_notifier.test", "notifier_id"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "repository", "sandbox"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "entity", "SlackNotifier"),
-				resource.TestCheckResourceAttr("humio_notifier.test", "name", "notifier-slack-test"),
+				resource.TestCheckResourceAttr("humio_notifier.test", "name", testAccPrefix+"notifier-slack"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "slack.#", "1"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "slack.0.fields.%", "2"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "slack.0.fields.Link", "{url}"),
@@ -510,7 +509,7 @@ func TestAccNotifierSlackBasicToFull(t *testing.T) {
 				resource.TestCheckResourceAttrSet("humio_notifier.test", "notifier_id"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "repository", "sandbox"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "entity", "SlackNotifier"),
-				resource.TestCheckResourceAttr("humio_notifier.test", "name", "notifier-slack-test"),
+				resource.TestCheckResourceAttr("humio_notifier.test", "name", testAccPrefix+"notifier-slack"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "slack.#", "1"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "slack.0.fields.%", "2"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "slack.0.fields.Link", "{url}"),
@@ -537,7 +536,7 @@ func TestAccNotifierSlackFull(t *testing.T) {
 				resource.TestCheckResourceAttrSet("humio_notifier.test", "notifier_id"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "repository", "sandbox"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "entity", "SlackNotifier"),
-				resource.TestCheckResourceAttr("humio_notifier.test", "name", "notifier-slack-test"),
+				resource.TestCheckResourceAttr("humio_notifier.test", "name", testAccPrefix+"notifier-slack"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "slack.#", "1"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "slack.0.fields.%", "2"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "slack.0.fields.Link", "{url}"),
@@ -564,7 +563,7 @@ func TestAccNotifierSlackPostMessageBasic(t *testing.T) {
 				resource.TestCheckResourceAttrSet("humio_notifier.test", "notifier_id"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "repository", "sandbox"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "entity", "SlackPostMessageNotifier"),
-				resource.TestCheckResourceAttr("humio_notifier.test", "name", "notifier-slackpostmessage-test"),
+				resource.TestCheckResourceAttr("humio_notifier.test", "name", testAccPrefix+"notifier-slackpostmessage"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "slackpostmessage.#", "1"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "slackpostmessage.0.api_token", "secretapitoken"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "slackpostmessage.0.channels.#", "2"),
@@ -596,7 +595,7 @@ func TestAccNotifierSlackPostMessageBasicToFull(t *testing.T) {
 				resource.TestCheckResourceAttrSet("humio_notifier.test", "notifier_id"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "repository", "sandbox"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "entity", "SlackPostMessageNotifier"),
-				resource.TestCheckResourceAttr("humio_notifier.test", "name", "notifier-slackpostmessage-test"),
+				resource.TestCheckResourceAttr("humio_notifier.test", "name", testAccPrefix+"notifier-slackpostmessage"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "slackpostmessage.#", "1"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "slackpostmessage.0.api_token", "secretapitoken"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "slackpostmessage.0.channels.#", "2"),
@@ -623,7 +622,7 @@ func TestAccNotifierSlackPostMessageBasicToFull(t *testing.T) {
 				resource.TestCheckResourceAttrSet("humio_notifier.test", "notifier_id"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "repository", "sandbox"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "entity", "SlackPostMessageNotifier"),
-				resource.TestCheckResourceAttr("humio_notifier.test", "name", "notifier-slackpostmessage-test"),
+				resource.TestCheckResourceAttr("humio_notifier.test", "name", testAccPrefix+"notifier-slackpostmessage"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "slackpostmessage.#", "1"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "slackpostmessage.0.api_token", "secretapitoken"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "slackpostmessage.0.channels.#", "2"),
@@ -651,7 +650,7 @@ func TestAccNotifierSlackPostMessageBasicToFull(t *testing.T) {
 				resource.TestCheckResourceAttrSet("humio_notifier.test", "notifier_id"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "repository", "sandbox"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "entity", "SlackPostMessageNotifier"),
-				resource.TestCheckResourceAttr("humio_notifier.test", "name", "notifier-slackpostmessage-test"),
+				resource.TestCheckResourceAttr("humio_notifier.test", "name", testAccPrefix+"notifier-slackpostmessage"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "slackpostmessage.#", "1"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "slackpostmessage.0.api_token", "secretapitoken"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "slackpostmessage.0.channels.#", "2"),
@@ -682,7 +681,7 @@ func TestAccNotifierSlackPostMessageFull(t *testing.T) {
 				resource.TestCheckResourceAttrSet("humio_notifier.test", "notifier_id"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "repository", "sandbox"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "entity", "SlackPostMessageNotifier"),
-				resource.TestCheckResourceAttr("humio_notifier.test", "name", "notifier-slackpostmessage-test"),
+				resource.TestCheckResourceAttr("humio_notifier.test", "name", testAccPrefix+"notifier-slackpostmessage"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "slackpostmessage.#", "1"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "slackpostmessage.0.api_token", "secretapitoken"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "slackpostmessage.0.channels.#", "2"),
@@ -713,7 +712,7 @@ func TestAccNotifierVictorOpsFull(t *testing.T) {
 				resource.TestCheckResourceAttrSet("humio_notifier.test", "notifier_id"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "repository", "sandbox"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "entity", "VictorOpsNotifier"),
-				resource.TestCheckResourceAttr("humio_notifier.test", "name", "notifier-victorops-test"),
+				resource.TestCheckResourceAttr("humio_notifier.test", "name", testAccPrefix+"notifier-victorops"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "victorops.#", "1"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "victorops.0.message_type", "important"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "victorops.0.notify_url", "https://127.0.0.1/iasjdojaoijdioajd"),
@@ -738,7 +737,7 @@ func TestAccNotifierWebHookBasic(t *testing.T) {
 				resource.TestCheckResourceAttrSet("humio_notifier.test", "notifier_id"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "repository", "sandbox"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "entity", "WebHookNotifier"),
-				resource.TestCheckResourceAttr("humio_notifier.test", "name", "notifier-webhook-test"),
+				resource.TestCheckResourceAttr("humio_notifier.test", "name", testAccPrefix+"notifier-webhook"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "webhook.#", "1"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "webhook.0.body_template", "{\n  \"repository\": \"{repo_name}\",\n  \"timestamp\": \"{alert_triggered_timestamp}\",\n  \"alert\": {\n    \"name\": \"{alert_name}\",\n    \"description\": \"{alert_description}\",\n    \"query\": {\n      \"queryString\": \"{query_string} \",\n      \"end\": \"{query_time_end}\",\n      \"start\": \"{query_time_start}\"\n    },\n    \"notifierID\": \"{alert_notifier_id}\",\n    \"id\": \"{alert_id}\"\n  },\n  \"warnings\": \"{warnings}\",\n  \"events\": {events},\n  \"numberOfEvents\": {event_count}\n  }"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "webhook.0.headers.%", "1"),
@@ -766,7 +765,7 @@ func TestAccNotifierWebHookBasicToFull(t *testing.T) {
 				resource.TestCheckResourceAttrSet("humio_notifier.test", "notifier_id"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "repository", "sandbox"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "entity", "WebHookNotifier"),
-				resource.TestCheckResourceAttr("humio_notifier.test", "name", "notifier-webhook-test"),
+				resource.TestCheckResourceAttr("humio_notifier.test", "name", testAccPrefix+"notifier-webhook"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "webhook.#", "1"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "webhook.0.body_template", "{\n  \"repository\": \"{repo_name}\",\n  \"timestamp\": \"{alert_triggered_timestamp}\",\n  \"alert\": {\n    \"name\": \"{alert_name}\",\n    \"description\": \"{alert_description}\",\n    \"query\": {\n      \"queryString\": \"{query_string} \",\n      \"end\": \"{query_time_end}\",\n      \"start\": \"{query_time_start}\"\n    },\n    \"notifierID\": \"{alert_notifier_id}\",\n    \"id\": \"{alert_id}\"\n  },\n  \"warnings\": \"{warnings}\",\n  \"events\": {events},\n  \"numberOfEvents\": {event_count}\n  }"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "webhook.0.headers.%", "1"),
@@ -789,7 +788,7 @@ func TestAccNotifierWebHookBasicToFull(t *testing.T) {
 				resource.TestCheckResourceAttrSet("humio_notifier.test", "notifier_id"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "repository", "sandbox"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "entity", "WebHookNotifier"),
-				resource.TestCheckResourceAttr("humio_notifier.test", "name", "notifier-webhook-test"),
+				resource.TestCheckResourceAttr("humio_notifier.test", "name", testAccPrefix+"notifier-webhook"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "webhook.#", "1"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "webhook.0.body_template", "custom body"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "webhook.0.headers.%", "2"),
@@ -815,7 +814,7 @@ func TestAccNotifierWebHookBasicToFull(t *testing.T) {
 				resource.TestCheckResourceAttrSet("humio_notifier.test", "notifier_id"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "repository", "sandbox"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "entity", "WebHookNotifier"),
-				resource.TestCheckResourceAttr("humio_notifier.test", "name", "notifier-webhook-test"),
+				resource.TestCheckResourceAttr("humio_notifier.test", "name", testAccPrefix+"notifier-webhook"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "webhook.#", "1"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "webhook.0.body_template", "custom body"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "webhook.0.headers.%", "2"),
@@ -844,7 +843,7 @@ func TestAccNotifierWebHookFull(t *testing.T) {
 				resource.TestCheckResourceAttrSet("humio_notifier.test", "notifier_id"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "repository", "sandbox"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "entity", "WebHookNotifier"),
-				resource.TestCheckResourceAttr("humio_notifier.test", "name", "notifier-webhook-test"),
+				resource.TestCheckResourceAttr("humio_notifier.test", "name", testAccPrefix+"notifier-webhook"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "webhook.#", "1"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "webhook.0.body_template", "custom body"),
 				resource.TestCheckResourceAttr("humio_notifier.test", "webhook.0.headers.%", "2"),
@@ -866,7 +865,7 @@ func TestAccNotifierWebHookFull(t *testing.T) {
 }
 
 func testAccCheckNotifierDestroy(s *terraform.State) error {
-	conn := testAccProviders["humio"].Meta().(*humio.Client)
+	conn := testAccProviders["humio"].Meta().(*apiClient)
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "humio_notifier" {
@@ -874,19 +873,14 @@ func testAccCheckNotifierDestroy(s *terraform.State) error {
 		}
 
 		parts := parseRepositoryAndID(rs.Primary.ID)
-		resp, err := conn.Notifiers().Get(parts[0], parts[1])
-		emptyNotifier := humio.Notifier{}
+		_, err := conn.notifierID(context.Background(), parts[0], parts[1])
 		if err == nil {
-			if !reflect.DeepEqual(*resp, emptyNotifier) {
-				return fmt.Errorf("notifier still exist for id %s: %#+v", rs.Primary.ID, *resp)
-			}
+			return fmt.Errorf("notifier still exist for id %s", rs.Primary.ID)
 		}
-		if err != nil {
-			if strings.HasPrefix(err.Error(), "could not find a notifier") {
-				return nil
-			}
-			return fmt.Errorf("could not validate if notifers have been cleaned up: %s", err)
+		if isNotFoundError(err) {
+			return nil
 		}
+		return fmt.Errorf("could not validate if notifers have been cleaned up: %s", err)
 	}
 	return nil
 }
@@ -1015,7 +1009,7 @@ const notifierEmailBasic = `
 resource "humio_notifier" "test" {
     repository = "sandbox"
     entity     = "EmailNotifier"
-    name       = "notifier-email-test"
+    name       = "` + testAccPrefix + `notifier-email"
     email {
         recipients = ["test@example.org"]
     }
@@ -1026,7 +1020,7 @@ const notifierEmailFull = `
 resource "humio_notifier" "test" {
     repository  = "sandbox"
     entity      = "EmailNotifier"
-    name        = "notifier-email-test"
+    name        = "` + testAccPrefix + `notifier-email"
     email {
         body_template    = "this is the body"
         recipients       = ["test@example.org", "ops@example.org"]
@@ -1039,7 +1033,7 @@ const notifierHumioRepoFull = `
 resource "humio_notifier" "test" {
     repository = "sandbox"
     entity     = "HumioRepoNotifier"
-    name       = "notifier-humiorepo-test"
+    name       = "` + testAccPrefix + `notifier-humiorepo"
     humiorepo {
         ingest_token = "secrettoken"
     }
@@ -1050,7 +1044,7 @@ const notifierOpsGenieBasic = `
 resource "humio_notifier" "test" {
     repository = "sandbox"
     entity     = "OpsGenieNotifier"
-    name       = "notifier-opsgenie-test"
+    name       = "` + testAccPrefix + `notifier-opsgenie"
     opsgenie {
         genie_key = "secretgeniekey"
     }
@@ -1061,7 +1055,7 @@ const notifierOpsGenieFull = `
 resource "humio_notifier" "test" {
     repository = "sandbox"
     entity     = "OpsGenieNotifier"
-    name       = "notifier-opsgenie-test"
+    name       = "` + testAccPrefix + `notifier-opsgenie"
     opsgenie {
         api_url   = "https://127.0.0.1/iasjdojaoijdioajd"
         genie_key = "secretgeniekey"
@@ -1073,7 +1067,7 @@ const notifierPagerDutyFull = `
 resource "humio_notifier" "test" {
     repository = "sandbox"
     entity     = "PagerDutyNotifier"
-    name       = "notifier-pagerduty-test"
+    name       = "` + testAccPrefix + `notifier-pagerduty"
     pagerduty {
         routing_key = "secretroutingkey"
         severity    = "critical"
@@ -1085,7 +1079,7 @@ const notifierSlackBasic = `
 resource "humio_notifier" "test" {
     repository = "sandbox"
     entity     = "SlackNotifier"
-    name       = "notifier-slack-test"
+    name       = "` + testAccPrefix + `notifier-slack"
     slack {
         fields = {
             "Events String" = "{events_str}"
@@ -1101,7 +1095,7 @@ const notifierSlackFull = `
 resource "humio_notifier" "test" {
     repository = "sandbox"
     entity     = "SlackNotifier"
-    name       = "notifier-slack-test"
+    name       = "` + testAccPrefix + `notifier-slack"
     slack {
         fields = {
 			"Link" = "{url}"
@@ -1116,7 +1110,7 @@ const notifierSlackPostMessageBasic = `
 resource "humio_notifier" "test" {
     repository = "sandbox"
     entity     = "SlackPostMessageNotifier"
-    name       = "notifier-slackpostmessage-test"
+    name       = "` + testAccPrefix + `notifier-slackpostmessage"
     slackpostmessage {
         api_token = "secretapitoken"
         channels  = ["#alerts","#ops"]
@@ -1133,7 +1127,7 @@ const notifierSlackPostMessageFull = `
 resource "humio_notifier" "test" {
     repository = "sandbox"
     entity     = "SlackPostMessageNotifier"
-    name       = "notifier-slackpostmessage-test"
+    name       = "` + testAccPrefix + `notifier-slackpostmessage"
     slackpostmessage {
         api_token = "secretapitoken"
         channels  = ["#alerts","#ops"]
@@ -1150,7 +1144,7 @@ const notifierVictorOpsFull = `
 resource "humio_notifier" "test" {
     repository = "sandbox"
     entity     = "VictorOpsNotifier"
-    name       = "notifier-victorops-test"
+    name       = "` + testAccPrefix + `notifier-victorops"
     victorops {
         message_type = "important"
         notify_url   = "https://127.0.0.1/iasjdojaoijdioajd"
@@ -1162,7 +1156,7 @@ const notifierWebHookBasic = `
 resource "humio_notifier" "test" {
     repository = "sandbox"
     entity     = "WebHookNotifier"
-    name       = "notifier-webhook-test"
+    name       = "` + testAccPrefix + `notifier-webhook"
     webhook {
         headers = {
             "Content-Type" = "application/json"
@@ -1176,7 +1170,7 @@ const notifierWebHookFull = `
 resource "humio_notifier" "test" {
     repository = "sandbox"
     entity     = "WebHookNotifier"
-    name       = "notifier-webhook-test"
+    name       = "` + testAccPrefix + `notifier-webhook"
     webhook {
         body_template = "custom body"
         headers       = {
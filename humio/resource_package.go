@@ -0,0 +1,241 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	humio "github.com/humio/cli/api"
+)
+
+func resourcePackage() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourcePackageCreate,
+		ReadContext:   resourcePackageRead,
+		UpdateContext: resourcePackageUpdate,
+		DeleteContext: resourcePackageDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		CustomizeDiff: resourcePackageCustomizeDiff,
+		Timeouts:      defaultTimeouts(),
+
+		Schema: map[string]*schema.Schema{
+			"repository": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"source": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"version": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"content_hash": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"installed_objects": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+// resourcePackageCustomizeDiff reads the package when planning, so changes to its content cause it to be upgraded
+// and installing a different package replaces the resource.
+func resourcePackageCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, client interface{}) error {
+	if !d.NewValueKnown("source") {
+		return d.SetNewComputed("content_hash")
+	}
+	p, err := readPackageSource(d.Get("source").(string))
+	if err != nil {
+		return fmt.Errorf("could not read package: %s", err)
+	}
+
+	if err := d.SetNew("name", p.Name); err != nil {
+		return err
+	}
+	if d.Id() != "" && d.HasChange("name") {
+		if err := d.ForceNew("name"); err != nil {
+			return err
+		}
+	}
+	if old, _ := d.GetChange("content_hash"); old.(string) != p.ContentHash() {
+		for _, key := range []string{"content_hash", "version", "installed_objects"} {
+			if err := d.SetNewComputed(key); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func resourcePackageCreate(ctx context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+	p, diags := resourcePackageInstall(ctx, d, client)
+	if diags.HasError() {
+		return diags
+	}
+	d.SetId(fmt.Sprintf("%s+%s", d.Get("repository"), p.Name))
+
+	return resourcePackageRead(ctx, d, client)
+}
+
+func resourcePackageRead(ctx context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+	// If we don't have a repository when importing, we parse it from the ID.
+	if _, ok := d.GetOk("repository"); !ok {
+		parts := parseRepositoryAndID(d.Id())
+		//we check that we have parsed the id into the correct number of segments
+		if parts[0] == "" || parts[1] == "" {
+			return diag.Errorf("error importing humio_package. Please make sure the ID is in the form REPOSITORYNAME+PACKAGENAME (i.e. myRepoName+myorg/myPackage")
+		}
+		err := d.Set("repository", parts[0])
+		if err != nil {
+			return diag.Errorf("error setting repository for resource %s: %s", d.Id(), err)
+		}
+		err = d.Set("name", parts[1])
+		if err != nil {
+			return diag.Errorf("error setting name for resource %s: %s", d.Id(), err)
+		}
+	}
+
+	var p *installedPackage
+	err := client.(*apiClient).retry(ctx, "get installed package", func() error {
+		var err error
+		p, err = client.(*apiClient).getInstalledPackage(
+			ctx,
+			d.Get("repository").(string),
+			d.Get("name").(string),
+		)
+		return err
+	})
+	if removeFromStateIfNotFound(d, "humio_package", err) {
+		return nil
+	}
+	if err != nil {
+		return diag.Errorf("could not get package: %s", err)
+	}
+
+	err = d.Set("version", p.Version)
+	if err != nil {
+		return diag.Errorf("error setting version for resource %s: %s", d.Id(), err)
+	}
+	err = d.Set("installed_objects", p.Objects)
+	if err != nil {
+		return diag.Errorf("error setting installed_objects for resource %s: %s", d.Id(), err)
+	}
+	return nil
+}
+
+// resourcePackageUpdate upgrades the package in place by installing the new content over the installed package.
+func resourcePackageUpdate(ctx context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+	_, diags := resourcePackageInstall(ctx, d, client)
+	if diags.HasError() {
+		return diags
+	}
+	return resourcePackageRead(ctx, d, client)
+}
+
+func resourcePackageInstall(ctx context.Context, d *schema.ResourceData, client interface{}) (*packageSource, diag.Diagnostics) {
+	p, err := readPackageSource(d.Get("source").(string))
+	if err != nil {
+		return nil, diag.Errorf("could not read package: %s", err)
+	}
+	archive, err := p.archive()
+	if err != nil {
+		return nil, diag.Errorf("could not read package: %s", err)
+	}
+
+	// Installing a package again upgrades it in place, so the install is retried like an update.
+	var report *humio.ValidationResponse
+	err = client.(*apiClient).retry(ctx, "install package", func() error {
+		var err error
+		report, err = client.(*apiClient).installPackage(ctx, d.Get("repository").(string), archive)
+		return err
+	})
+	if err == nil && !report.IsValid() {
+		err = fmt.Errorf("%s", strings.Join(append(report.ParseErrors, report.InstallationErrors...), "; "))
+	}
+	if err != nil {
+		return nil, packageInstallError(ctx, d, client, p, err)
+	}
+
+	err = d.Set("name", p.Name)
+	if err != nil {
+		return nil, diag.Errorf("error setting name for resource %s: %s", d.Id(), err)
+	}
+	err = d.Set("content_hash", p.ContentHash())
+	if err != nil {
+		return nil, diag.Errorf("error setting content_hash for resource %s: %s", d.Id(), err)
+	}
+	return p, nil
+}
+
+// packageInstallError returns the error of a failed install. Humio may have installed some of the objects of the
+// package before failing, so if the package shows up as installed with the new version, the error lists the objects
+// installed and the resource is kept in the state, which leaves it to the next apply to install the package again.
+func packageInstallError(ctx context.Context, d *schema.ResourceData, client interface{}, p *packageSource, err error) diag.Diagnostics {
+	repository := d.Get("repository").(string)
+	installed, getErr := client.(*apiClient).getInstalledPackage(ctx, repository, p.Name)
+	if getErr != nil || installed.Version != p.Version {
+		return diag.Errorf("could not install package %s: %s", p.Name, err)
+	}
+
+	if d.Id() == "" {
+		d.SetId(fmt.Sprintf("%s+%s", repository, p.Name))
+	}
+	if setErr := d.Set("name", p.Name); setErr != nil {
+		return diag.Errorf("error setting name for resource %s: %s", d.Id(), setErr)
+	}
+	return diag.Errorf("package %s was only partially installed in repository %s: %s. Installed objects: %s", p.Name, repository, err, strings.Join(installed.Objects, ", "))
+}
+
+func resourcePackageDelete(ctx context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+	err := client.(*apiClient).call(ctx, func() error {
+		return client.(*apiClient).uninstallPackage(
+			ctx,
+			d.Get("repository").(string),
+			d.Get("name").(string),
+		)
+	})
+	if err != nil {
+		return diag.Errorf("could not uninstall package: %s", err)
+	}
+	return nil
+}
+
+// splitPackageID splits the ID of an installed package, such as myorg/mypackage@1.2.0, into the package name and
+// version.
+func splitPackageID(id string) (string, string) {
+	i := strings.LastIndex(id, "@")
+	if i < 0 {
+		return id, ""
+	}
+	return id[:i], id[i+1:]
+}
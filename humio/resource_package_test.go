@@ -0,0 +1,318 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"archive/zip"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	humio "github.com/humio/cli/api"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccPackageRequiredFields(t *testing.T) {
+	config := packageEmpty
+	accTestCase(t, []resource.TestStep{
+		{Config: config, ExpectError: regexp.MustCompile(`The argument "repository" is required, but no definition was found.`)},
+		{Config: config, ExpectError: regexp.MustCompile(`The argument "source" is required, but no definition was found.`)},
+	}, nil)
+}
+
+func TestAccPackageMissingSource(t *testing.T) {
+	accTestCase(t, []resource.TestStep{
+		{Config: packageMissingSource, ExpectError: regexp.MustCompile(`could not read package`)},
+	}, nil)
+}
+
+func TestAccPackageBasic(t *testing.T) {
+	accTestCase(t, []resource.TestStep{
+		{
+			Config: packageBasic,
+			Check: resource.ComposeTestCheckFunc(
+				resource.TestCheckResourceAttr("humio_package.test", "repository", "sandbox"),
+				resource.TestCheckResourceAttr("humio_package.test", "name", testAccPrefix+"terraform/package"),
+				resource.TestCheckResourceAttr("humio_package.test", "version", "1.0.0"),
+				resource.TestCheckResourceAttr("humio_package.test", "installed_objects.#", "3"),
+				resource.TestCheckResourceAttrSet("humio_package.test", "content_hash"),
+			),
+		},
+		{
+			ResourceName:            "humio_package.test",
+			ImportState:             true,
+			ImportStateId:           "sandbox+" + testAccPrefix + "terraform/package",
+			ImportStateVerify:       true,
+			ImportStateVerifyIgnore: []string{"source", "content_hash"},
+		},
+	}, testAccCheckPackageDestroy)
+}
+
+func testAccCheckPackageDestroy(s *terraform.State) error {
+	conn := testAccProviders["humio"].Meta().(*apiClient)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "humio_package" {
+			continue
+		}
+		installed, err := conn.listInstalledPackages(context.Background(), rs.Primary.Attributes["repository"])
+		if err != nil {
+			return err
+		}
+		for _, p := range installed {
+			if p.Name == rs.Primary.Attributes["name"] {
+				return fmt.Errorf("package still installed: %s", rs.Primary.ID)
+			}
+		}
+	}
+	return nil
+}
+
+const packageEmpty = `
+resource "humio_package" "test" {}
+`
+
+const packageMissingSource = `
+resource "humio_package" "test" {
+	repository = "sandbox"
+	source     = "testdata/missing"
+}
+`
+
+const packageBasic = `
+resource "humio_package" "test" {
+	repository = "sandbox"
+	source     = "testdata/package"
+}
+`
+
+func TestReadPackageSource(t *testing.T) {
+	dir, err := readPackageSource("testdata/package")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dir.Name != testAccPrefix+"terraform/package" || dir.Version != "1.0.0" {
+		t.Errorf("unexpected package %s@%s", dir.Name, dir.Version)
+	}
+	wantPaths := []string{"dashboards/overview.yaml", "files/hosts.csv", "manifest.yaml", "parsers/accesslog.yaml"}
+	if !cmp.Equal(wantPaths, dir.paths()) {
+		t.Error(cmp.Diff(wantPaths, dir.paths()))
+	}
+
+	tmp, err := ioutil.TempDir("", "humio-package")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmp)
+	archive := filepath.Join(tmp, "package.zip")
+	if err := humio.NewClient(humio.Config{}).Packages().CreateArchive("testdata/package", archive); err != nil {
+		t.Fatal(err)
+	}
+
+	zip, err := readPackageSource(archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dir.ContentHash() != zip.ContentHash() {
+		t.Error("expected the same content hash for a directory and a zip file of it")
+	}
+
+	if err := ioutil.WriteFile(filepath.Join(tmp, "manifest.yaml"), []byte("version: 1.0.0\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := readPackageSource(tmp); err == nil {
+		t.Error("expected an error for a manifest without a name")
+	}
+}
+
+// TestReadPackageSourceZipSkipsHiddenFiles checks that files starting with _ or . are left out of a zip file like they
+// are left out of a directory, using a zip file that contains them, unlike the ones created by Humio.
+func TestReadPackageSourceZipSkipsHiddenFiles(t *testing.T) {
+	dir, err := readPackageSource("testdata/package")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tmp, err := ioutil.TempDir("", "humio-package")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmp)
+	archive := filepath.Join(tmp, "package.zip")
+	f, err := os.Create(archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := zip.NewWriter(f)
+	files := map[string]string{
+		"_build/notes.txt":         "build notes",
+		".DS_Store":                "",
+		"parsers/.accesslog":       "editor backup",
+		"parsers/_draft.yaml":      "name: draft",
+		"manifest.yaml":            string(dir.Files["manifest.yaml"]),
+		"parsers/accesslog.yaml":   string(dir.Files["parsers/accesslog.yaml"]),
+		"dashboards/overview.yaml": string(dir.Files["dashboards/overview.yaml"]),
+		"files/hosts.csv":          string(dir.Files["files/hosts.csv"]),
+	}
+	for name, content := range files {
+		fw, err := w.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := fw.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	fromZip, err := readPackageSource(archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cmp.Equal(dir.paths(), fromZip.paths()) {
+		t.Error(cmp.Diff(dir.paths(), fromZip.paths()))
+	}
+	if dir.ContentHash() != fromZip.ContentHash() {
+		t.Error("expected the same content hash for a directory and a zip file of it with hidden files")
+	}
+}
+
+func TestListInstalledPackages(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"data": {"searchDomain": {"installedPackages": [{
+			"id": "` + testAccPrefix + `terraform/package@1.0.0",
+			"package": {
+				"actionTemplates": [],
+				"alertTemplates": [],
+				"dashboardTemplates": [{"name": "overview"}],
+				"lookupFileTemplates": [{"name": "hosts.csv"}],
+				"parserTemplates": [{"name": "accesslog"}],
+				"savedQueryTemplates": [],
+				"scheduledSearchTemplates": []
+			}
+		}]}}}`))
+	}))
+	defer server.Close()
+
+	address, _ := url.Parse(server.URL)
+	c := newAPIClient(address, "secret", http.DefaultTransport)
+
+	p, err := c.getInstalledPackage(context.Background(), "sandbox", testAccPrefix+"terraform/package")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &installedPackage{
+		Name:    testAccPrefix + "terraform/package",
+		Version: "1.0.0",
+		Objects: []string{"dashboards/overview", "files/hosts.csv", "parsers/accesslog"},
+	}
+	if !cmp.Equal(want, p) {
+		t.Error(cmp.Diff(want, p))
+	}
+
+	if _, err := c.getInstalledPackage(context.Background(), "sandbox", "other/package"); !isNotFoundError(err) {
+		t.Errorf("expected not found error, got %v", err)
+	}
+}
+
+// TestPackageInstall checks that the package is sent as a zip file with the context of the operation, that a 502 from a
+// proxy is retried, and that errors reported after Humio installed some of the objects name the installed objects.
+func TestPackageInstall(t *testing.T) {
+	var installs int
+	var sent *packageSource
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/packages/install":
+			installs++
+			if installs == 1 {
+				w.WriteHeader(http.StatusBadGateway)
+				_, _ = w.Write([]byte(badGatewayPage))
+				return
+			}
+			if r.URL.Query().Get("view") != "sandbox" || r.Header.Get("Content-Type") != humio.ZIPContentType {
+				t.Errorf("unexpected install request %s with content type %s", r.URL, r.Header.Get("Content-Type"))
+			}
+			b, _ := ioutil.ReadAll(r.Body)
+			archive := filepath.Join(t.TempDir(), "package.zip")
+			if err := ioutil.WriteFile(archive, b, 0644); err != nil {
+				t.Error(err)
+				return
+			}
+			var err error
+			if sent, err = readPackageSource(archive); err != nil {
+				t.Error(err)
+			}
+			_, _ = w.Write([]byte(`{"installationErrors": ["could not install dashboard overview"], "parseErrors": []}`))
+		case "/graphql":
+			_, _ = w.Write([]byte(`{"data": {"searchDomain": {"installedPackages": [{
+				"id": "` + testAccPrefix + `terraform/package@1.0.0",
+				"package": {"lookupFileTemplates": [{"name": "hosts.csv"}], "parserTemplates": [{"name": "accesslog"}]}
+			}]}}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	address, _ := url.Parse(server.URL)
+	c := newAPIClient(address, "secret", http.DefaultTransport)
+	c.retryMaxAttempts, c.retryMaxWait = 3, time.Millisecond
+	d := resourcePackage().Data(nil)
+	_ = d.Set("repository", "sandbox")
+	_ = d.Set("source", "testdata/package")
+
+	_, diags := resourcePackageInstall(context.Background(), d, c)
+	if installs != 2 {
+		t.Errorf("expected the 502 to be retried, got %d installs", installs)
+	}
+	want, _ := readPackageSource("testdata/package")
+	if sent == nil || sent.ContentHash() != want.ContentHash() {
+		t.Error("expected the package to be sent as a zip file of its content")
+	}
+	if !diags.HasError() || !strings.Contains(diags[0].Summary, "only partially installed") || !strings.Contains(diags[0].Summary, "files/hosts.csv, parsers/accesslog") {
+		t.Errorf("expected a partial install error naming the installed objects, got %v", diags)
+	}
+	if d.Id() != "sandbox+"+testAccPrefix+"terraform/package" {
+		t.Errorf("expected the partially installed package to be kept in the state, got ID %q", d.Id())
+	}
+}
+
+func TestSplitPackageID(t *testing.T) {
+	name, version := splitPackageID("terraform/test-package@1.0.0")
+	if name != "terraform/test-package" || version != "1.0.0" {
+		t.Errorf("unexpected split %q %q", name, version)
+	}
+	name, version = splitPackageID("terraform/test-package")
+	if name != "terraform/test-package" || version != "" {
+		t.Errorf("unexpected split %q %q", name, version)
+	}
+}
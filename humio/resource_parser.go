@@ -34,6 +34,7 @@ func resourceParser() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
+		Timeouts: defaultTimeouts(),
 
 		Schema: map[string]*schema.Schema{
 			"repository": {
@@ -69,11 +70,14 @@ func resourceParserCreate(ctx context.Context, d *schema.ResourceData, client in
 		return diag.Errorf("could not obtain parser from resource data: %s", err)
 	}
 
-	err = client.(*humio.Client).Parsers().Add(
-		d.Get("repository").(string),
-		&parser,
-		false,
-	)
+	err = client.(*apiClient).call(ctx, func() error {
+		return client.(*apiClient).addParser(
+			ctx,
+			d.Get("repository").(string),
+			&parser,
+			false,
+		)
+	})
 	if err != nil {
 		return diag.Errorf("could not create parser: %s", err)
 	}
@@ -82,7 +86,7 @@ func resourceParserCreate(ctx context.Context, d *schema.ResourceData, client in
 	return resourceParserRead(ctx, d, client)
 }
 
-func resourceParserRead(_ context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+func resourceParserRead(ctx context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
 	// If we don't have a repository when importing, we parse it from the ID.
 	if _, ok := d.GetOk("repository"); !ok {
 		parts := parseRepositoryAndID(d.Id())
@@ -100,11 +104,23 @@ func resourceParserRead(_ context.Context, d *schema.ResourceData, client interf
 		}
 	}
 
-	parser, err := client.(*humio.Client).Parsers().Get(
-		d.Get("repository").(string),
-		d.Get("name").(string),
-	)
-	if err != nil || reflect.DeepEqual(*parser, humio.Parser{Tests: []humio.ParserTestCase{}}) {
+	var parser *humio.Parser
+	err := client.(*apiClient).retry(ctx, "get parser", func() error {
+		var err error
+		parser, err = client.(*apiClient).getParser(
+			ctx,
+			d.Get("repository").(string),
+			d.Get("name").(string),
+		)
+		return err
+	})
+	if err == nil && reflect.DeepEqual(*parser, humio.Parser{Tests: []humio.ParserTestCase{}}) {
+		err = newNotFoundError("parser %s in repository %s", d.Get("name"), d.Get("repository"))
+	}
+	if removeFromStateIfNotFound(d, "humio_parser", err) {
+		return nil
+	}
+	if err != nil {
 		return diag.Errorf("could not get parser: %s", err)
 	}
 	return resourceDataFromParser(parser, d)
@@ -140,11 +156,14 @@ func resourceParserUpdate(ctx context.Context, d *schema.ResourceData, client in
 		return diag.Errorf("could not obtain parser from resource data: %s", err)
 	}
 
-	err = client.(*humio.Client).Parsers().Add(
-		d.Get("repository").(string),
-		&parser,
-		true,
-	)
+	err = client.(*apiClient).retry(ctx, "update parser", func() error {
+		return client.(*apiClient).addParser(
+			ctx,
+			d.Get("repository").(string),
+			&parser,
+			true,
+		)
+	})
 	if err != nil {
 		return diag.Errorf("could not update parser: %s", err)
 	}
@@ -169,16 +188,19 @@ func convertInterfaceListToParserTestCases(s []interface{}) []humio.ParserTestCa
 	return element
 }
 
-func resourceParserDelete(_ context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+func resourceParserDelete(ctx context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
 	parser, err := parserFromResourceData(d)
 	if err != nil {
 		return diag.Errorf("could not obtain parser from resource data: %s", err)
 	}
 
-	err = client.(*humio.Client).Parsers().Remove(
-		d.Get("repository").(string),
-		parser.Name,
-	)
+	err = client.(*apiClient).call(ctx, func() error {
+		return client.(*apiClient).removeParser(
+			ctx,
+			d.Get("repository").(string),
+			parser.Name,
+		)
+	})
 	if err != nil {
 		return diag.Errorf("could not delete parser: %s", err)
 	}
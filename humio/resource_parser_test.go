@@ -15,6 +15,7 @@
 package humio
 
 import (
+	"context"
 	"fmt"
 	"reflect"
 	"regexp"
@@ -52,7 +53,7 @@ func TestAccParserBasic(t *testing.T) {
 			Config: parserBasic,
 			Check: resource.ComposeTestCheckFunc(
 				resource.TestCheckResourceAttr("humio_parser.test", "repository", "sandbox"),
-				resource.TestCheckResourceAttr("humio_parser.test", "name", "parser-test"),
+				resource.TestCheckResourceAttr("humio_parser.test", "name", testAccPrefix+"parser"),
 				resource.TestCheckResourceAttr("humio_parser.test", "parser_script", ""),
 				resource.TestCheckNoResourceAttr("humio_parser.test", "tag_fields"),
 				resource.TestCheckNoResourceAttr("humio_parser.test", "test_data"),
@@ -67,7 +68,7 @@ func TestAccParserBasicToFull(t *testing.T) {
 			Config: parserBasic,
 			Check: resource.ComposeTestCheckFunc(
 				resource.TestCheckResourceAttr("humio_parser.test", "repository", "sandbox"),
-				resource.TestCheckResourceAttr("humio_parser.test", "name", "parser-test"),
+				resource.TestCheckResourceAttr("humio_parser.test", "name", testAccPrefix+"parser"),
 				resource.TestCheckResourceAttr("humio_parser.test", "parser_script", ""),
 				resource.TestCheckNoResourceAttr("humio_parser.test", "tag_fields"),
 				resource.TestCheckNoResourceAttr("humio_parser.test", "test_data"),
@@ -77,7 +78,7 @@ func TestAccParserBasicToFull(t *testing.T) {
 			Config: parserFull,
 			Check: resource.ComposeTestCheckFunc(
 				resource.TestCheckResourceAttr("humio_parser.test", "repository", "sandbox"),
-				resource.TestCheckResourceAttr("humio_parser.test", "name", "parser-test"),
+				resource.TestCheckResourceAttr("humio_parser.test", "name", testAccPrefix+"parser"),
 				resource.TestCheckResourceAttr("humio_parser.test", "parser_script", "parser script here"),
 				resource.TestCheckResourceAttr("humio_parser.test", "tag_fields.#", "2"),
 				resource.TestCheckResourceAttr("humio_parser.test", "tag_fields.0", "json"),
@@ -93,7 +94,7 @@ func TestAccParserBasicToFull(t *testing.T) {
 			Config: parserFull,
 			Check: resource.ComposeTestCheckFunc(
 				resource.TestCheckResourceAttr("humio_parser.test", "repository", "sandbox"),
-				resource.TestCheckResourceAttr("humio_parser.test", "name", "parser-test"),
+				resource.TestCheckResourceAttr("humio_parser.test", "name", testAccPrefix+"parser"),
 				resource.TestCheckResourceAttr("humio_parser.test", "parser_script", "parser script here"),
 				resource.TestCheckResourceAttr("humio_parser.test", "tag_fields.#", "2"),
 				resource.TestCheckResourceAttr("humio_parser.test", "tag_fields.0", "json"),
@@ -112,7 +113,7 @@ func TestAccParserFull(t *testing.T) {
 			Config: parserFull,
 			Check: resource.ComposeTestCheckFunc(
 				resource.TestCheckResourceAttr("humio_parser.test", "repository", "sandbox"),
-				resource.TestCheckResourceAttr("humio_parser.test", "name", "parser-test"),
+				resource.TestCheckResourceAttr("humio_parser.test", "name", testAccPrefix+"parser"),
 				resource.TestCheckResourceAttr("humio_parser.test", "parser_script", "parser script here"),
 				resource.TestCheckResourceAttr("humio_parser.test", "tag_fields.#", "2"),
 				resource.TestCheckResourceAttr("humio_parser.test", "tag_fields.0", "json"),
@@ -126,14 +127,14 @@ func TestAccParserFull(t *testing.T) {
 }
 
 func testAccCheckParserDestroy(s *terraform.State) error {
-	conn := testAccProviders["humio"].Meta().(*humio.Client)
+	conn := testAccProviders["humio"].Meta().(*apiClient)
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "humio_parser" {
 			continue
 		}
 		parts := parseRepositoryAndID(rs.Primary.ID)
-		resp, err := conn.Parsers().Get(parts[0], parts[1])
+		resp, err := conn.getParser(context.Background(), parts[0], parts[1])
 		emptyParser := humio.Parser{
 			Name:      "",
 			Tests:     []humio.ParserTestCase{},
@@ -167,14 +168,14 @@ resource "humio_parser" "test" {
 const parserBasic = `
 resource "humio_parser" "test" {
     repository = "sandbox"
-    name       = "parser-test"
+    name       = "` + testAccPrefix + `parser"
 }
 `
 
 const parserFull = `
 resource "humio_parser" "test" {
     repository    = "sandbox"
-    name          = "parser-test"
+    name          = "` + testAccPrefix + `parser"
     parser_script = "parser script here"
     tag_fields    = ["json","test"]
     test_data     = ["data1","data2"]
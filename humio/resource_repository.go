@@ -19,6 +19,7 @@ func resourceRepository() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
+		Timeouts: defaultTimeouts(),
 
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -70,41 +71,59 @@ func resourceRepositoryCreate(ctx context.Context, d *schema.ResourceData, clien
 		return diag.Errorf("could not obtain repository from resource data: %s", err)
 	}
 
-	err = client.(*humio.Client).Repositories().Create(
-		repository.Name,
-	)
+	err = client.(*apiClient).call(ctx, func() error {
+		return client.(*apiClient).createRepository(
+			ctx,
+			repository.Name,
+		)
+	})
 	if err != nil {
 		return diag.Errorf("could not create repository: %s", err)
 	}
 
-	err = client.(*humio.Client).Repositories().UpdateDescription(
-		repository.Name,
-		repository.Description,
-	)
+	err = client.(*apiClient).retry(ctx, "set description for repository", func() error {
+		return client.(*apiClient).updateSearchDomainDescription(
+			ctx,
+			repository.Name,
+			repository.Description,
+		)
+	})
 	if err != nil {
 		return diag.Errorf("could not set description for repository: %s", err)
 	}
-	err = client.(*humio.Client).Repositories().UpdateTimeBasedRetention(
-		repository.Name,
-		repository.RetentionDays,
-		d.Get("allow_data_deletion").(bool),
-	)
+	err = client.(*apiClient).retry(ctx, "set time based retention for repository", func() error {
+		return client.(*apiClient).updateRetention(
+			ctx,
+			repository.Name,
+			timeBasedRetention,
+			repository.RetentionDays,
+			d.Get("allow_data_deletion").(bool),
+		)
+	})
 	if err != nil {
 		return diag.Errorf("could not set time based retention for repository: %s", err)
 	}
-	err = client.(*humio.Client).Repositories().UpdateIngestBasedRetention(
-		repository.Name,
-		repository.IngestRetentionSizeGB,
-		d.Get("allow_data_deletion").(bool),
-	)
+	err = client.(*apiClient).retry(ctx, "set ingest size retention for repository", func() error {
+		return client.(*apiClient).updateRetention(
+			ctx,
+			repository.Name,
+			ingestSizeBasedRetention,
+			repository.IngestRetentionSizeGB,
+			d.Get("allow_data_deletion").(bool),
+		)
+	})
 	if err != nil {
 		return diag.Errorf("could not set ingest size retention for repository: %s", err)
 	}
-	err = client.(*humio.Client).Repositories().UpdateStorageBasedRetention(
-		repository.Name,
-		repository.StorageRetentionSizeGB,
-		d.Get("allow_data_deletion").(bool),
-	)
+	err = client.(*apiClient).retry(ctx, "set storage size retention for repository", func() error {
+		return client.(*apiClient).updateRetention(
+			ctx,
+			repository.Name,
+			storageSizeBasedRetention,
+			repository.StorageRetentionSizeGB,
+			d.Get("allow_data_deletion").(bool),
+		)
+	})
 	if err != nil {
 		return diag.Errorf("could not set storage size retention for repository: %s", err)
 	}
@@ -114,11 +133,27 @@ func resourceRepositoryCreate(ctx context.Context, d *schema.ResourceData, clien
 	return resourceRepositoryRead(ctx, d, client)
 }
 
-func resourceRepositoryRead(_ context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
-	repo, err := client.(*humio.Client).Repositories().Get(d.Id())
+func resourceRepositoryRead(ctx context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+	var repo humio.Repository
+	err := client.(*apiClient).retry(ctx, "get repository", func() error {
+		var err error
+		repo, err = client.(*apiClient).getRepository(ctx, d.Id())
+		return err
+	})
+	if err == nil && repo.Name == "" {
+		err = newNotFoundError("repository %s", d.Id())
+	}
+	if removeFromStateIfNotFound(d, "humio_repository", err) {
+		return nil
+	}
 	if err != nil {
 		return diag.Errorf("could not get repository: %s", err)
 	}
+	// allow_data_deletion only exists in the configuration, so an imported repository gets the default, which keeps
+	// the plan after the import empty.
+	if err := d.Set("allow_data_deletion", d.Get("allow_data_deletion")); err != nil {
+		return diag.Errorf("error setting allow_data_deletion for resource %s: %s", d.Id(), err)
+	}
 	return resourceDataFromRepository(&repo, d)
 }
 
@@ -151,34 +186,49 @@ func resourceRepositoryUpdate(ctx context.Context, d *schema.ResourceData, clien
 		return diag.Errorf("could not obtain repository from resource data: %s", err)
 	}
 
-	err = client.(*humio.Client).Repositories().UpdateDescription(
-		repository.Name,
-		repository.Description,
-	)
+	err = client.(*apiClient).retry(ctx, "update description for repository", func() error {
+		return client.(*apiClient).updateSearchDomainDescription(
+			ctx,
+			repository.Name,
+			repository.Description,
+		)
+	})
 	if err != nil {
 		return diag.Errorf("could not update description for repository: %s", err)
 	}
-	err = client.(*humio.Client).Repositories().UpdateTimeBasedRetention(
-		repository.Name,
-		repository.RetentionDays,
-		d.Get("allow_data_deletion").(bool),
-	)
+	err = client.(*apiClient).retry(ctx, "update time based retention for repository", func() error {
+		return client.(*apiClient).updateRetention(
+			ctx,
+			repository.Name,
+			timeBasedRetention,
+			repository.RetentionDays,
+			d.Get("allow_data_deletion").(bool),
+		)
+	})
 	if err != nil {
 		return diag.Errorf("could not update time based retention for repository: %s", err)
 	}
-	err = client.(*humio.Client).Repositories().UpdateIngestBasedRetention(
-		repository.Name,
-		repository.IngestRetentionSizeGB,
-		d.Get("allow_data_deletion").(bool),
-	)
+	err = client.(*apiClient).retry(ctx, "update ingest size retention for repository", func() error {
+		return client.(*apiClient).updateRetention(
+			ctx,
+			repository.Name,
+			ingestSizeBasedRetention,
+			repository.IngestRetentionSizeGB,
+			d.Get("allow_data_deletion").(bool),
+		)
+	})
 	if err != nil {
 		return diag.Errorf("could not update time based retention for repository: %s", err)
 	}
-	err = client.(*humio.Client).Repositories().UpdateStorageBasedRetention(
-		repository.Name,
-		repository.StorageRetentionSizeGB,
-		d.Get("allow_data_deletion").(bool),
-	)
+	err = client.(*apiClient).retry(ctx, "update storage size retention for repository", func() error {
+		return client.(*apiClient).updateRetention(
+			ctx,
+			repository.Name,
+			storageSizeBasedRetention,
+			repository.StorageRetentionSizeGB,
+			d.Get("allow_data_deletion").(bool),
+		)
+	})
 	if err != nil {
 		return diag.Errorf("could not update time based retention for repository: %s", err)
 	}
@@ -186,18 +236,22 @@ func resourceRepositoryUpdate(ctx context.Context, d *schema.ResourceData, clien
 	return resourceRepositoryRead(ctx, d, client)
 }
 
-func resourceRepositoryDelete(_ context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+func resourceRepositoryDelete(ctx context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
 	repository, err := repositoryFromResourceData(d)
 	if err != nil {
 		return diag.Errorf("could not obtain repository from resource data: %s", err)
 	}
 
 	deleteReason := "Deleted by Terraform"
-	err = client.(*humio.Client).Repositories().Delete(
-		repository.Name,
-		deleteReason,
-		d.Get("allow_data_deletion").(bool),
-	)
+	err = client.(*apiClient).call(ctx, func() error {
+		return client.(*apiClient).deleteRepository(
+			ctx,
+			repository.Name,
+			deleteReason,
+			d.Get("allow_data_deletion").(bool),
+		)
+	})
+	client.(*apiClient).cache.invalidateRepository(repository.Name)
 	if err != nil {
 		return diag.Errorf("could not delete repository: %s", err)
 	}
@@ -15,6 +15,7 @@
 package humio
 
 import (
+	"context"
 	"fmt"
 	"regexp"
 	"testing"
@@ -68,7 +69,7 @@ func TestAccRepositoryBasic(t *testing.T) {
 		{
 			Config: repositoryBasic,
 			Check: resource.ComposeTestCheckFunc(
-				resource.TestCheckResourceAttr("humio_repository.test", "name", "repository-test"),
+				resource.TestCheckResourceAttr("humio_repository.test", "name", testAccPrefix+"repository"),
 				resource.TestCheckResourceAttr("humio_repository.test", "description", ""),
 				resource.TestCheckResourceAttr("humio_repository.test", "allow_data_deletion", "false"),
 				resource.TestCheckResourceAttr("humio_repository.test", "retention.#", "1"), // TODO: Figure out if we want to require this set by the user. If not, how can we ensure this is not put in state?
@@ -88,7 +89,7 @@ func TestAccRepositoryBasicToFull(t *testing.T) {
 		{
 			Config: repositoryBasic,
 			Check: resource.ComposeTestCheckFunc(
-				resource.TestCheckResourceAttr("humio_repository.test", "name", "repository-test"),
+				resource.TestCheckResourceAttr("humio_repository.test", "name", testAccPrefix+"repository"),
 				resource.TestCheckResourceAttr("humio_repository.test", "description", ""),
 				resource.TestCheckResourceAttr("humio_repository.test", "allow_data_deletion", "false"),
 				resource.TestCheckResourceAttr("humio_repository.test", "retention.#", "1"), // TODO: Figure out if we want to require this set by the user. If not, how can we ensure this is not put in state?
@@ -103,7 +104,7 @@ func TestAccRepositoryBasicToFull(t *testing.T) {
 		{
 			Config: repositoryFull,
 			Check: resource.ComposeTestCheckFunc(
-				resource.TestCheckResourceAttr("humio_repository.test", "name", "repository-test"),
+				resource.TestCheckResourceAttr("humio_repository.test", "name", testAccPrefix+"repository"),
 				resource.TestCheckResourceAttr("humio_repository.test", "description", "some description"),
 				resource.TestCheckResourceAttr("humio_repository.test", "allow_data_deletion", "true"),
 				resource.TestCheckResourceAttr("humio_repository.test", "retention.#", "1"),
@@ -120,7 +121,7 @@ func TestAccRepositoryBasicToFull(t *testing.T) {
 		{
 			Config: repositoryFull,
 			Check: resource.ComposeTestCheckFunc(
-				resource.TestCheckResourceAttr("humio_repository.test", "name", "repository-test"),
+				resource.TestCheckResourceAttr("humio_repository.test", "name", testAccPrefix+"repository"),
 				resource.TestCheckResourceAttr("humio_repository.test", "description", "some description"),
 				resource.TestCheckResourceAttr("humio_repository.test", "allow_data_deletion", "true"),
 				resource.TestCheckResourceAttr("humio_repository.test", "retention.#", "1"),
@@ -140,7 +141,7 @@ func TestAccRepositoryFull(t *testing.T) {
 		{
 			Config: repositoryFull,
 			Check: resource.ComposeTestCheckFunc(
-				resource.TestCheckResourceAttr("humio_repository.test", "name", "repository-test"),
+				resource.TestCheckResourceAttr("humio_repository.test", "name", testAccPrefix+"repository"),
 				resource.TestCheckResourceAttr("humio_repository.test", "description", "some description"),
 				resource.TestCheckResourceAttr("humio_repository.test", "allow_data_deletion", "true"),
 				resource.TestCheckResourceAttr("humio_repository.test", "retention.#", "1"),
@@ -153,14 +154,14 @@ func TestAccRepositoryFull(t *testing.T) {
 }
 
 func testAccCheckRepositoryDestroy(s *terraform.State) error {
-	conn := testAccProviders["humio"].Meta().(*humio.Client)
+	conn := testAccProviders["humio"].Meta().(*apiClient)
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "humio_repository" {
 			continue
 		}
 		// TODO: Use rs.Primary.ID to figure out if repository exists, and not just list all repositories.
-		resp, err := conn.Repositories().List()
+		resp, err := conn.listRepositories(context.Background())
 		if err == nil {
 			if len(resp) > 4 { // only consider repositories not built in by default
 				return fmt.Errorf("repositories still exist: %#+v", resp)
@@ -196,14 +197,14 @@ resource "humio_repository" "test" {
 
 const repositoryBasic = `
 resource "humio_repository" "test" {
-    name = "repository-test"
+    name = "` + testAccPrefix + `repository"
     retention {}
 }
 `
 
 const repositoryFull = `
 resource "humio_repository" "test" {
-    name                = "repository-test"
+    name                = "` + testAccPrefix + `repository"
     description         = "some description"
     allow_data_deletion = true
     retention {
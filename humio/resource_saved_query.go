@@ -0,0 +1,234 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/structure"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+
+	humio "github.com/humio/cli/api"
+)
+
+func resourceSavedQuery() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceSavedQueryCreate,
+		ReadContext:   resourceSavedQueryRead,
+		UpdateContext: resourceSavedQueryUpdate,
+		DeleteContext: resourceSavedQueryDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Timeouts: defaultTimeouts(),
+
+		Schema: map[string]*schema.Schema{
+			"repository": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"query": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"start": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  "24h",
+			},
+			"end": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  "now",
+			},
+			"is_live": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"widget_type": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"options": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "{}",
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringIsJSON),
+				DiffSuppressFunc: structure.SuppressJsonDiff,
+			},
+		},
+	}
+}
+
+func resourceSavedQueryCreate(ctx context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+	s, err := savedQueryFromResourceData(d)
+	if err != nil {
+		return diag.Errorf("could not obtain saved query from resource data: %s", err)
+	}
+
+	err = client.(*apiClient).call(ctx, func() error {
+		return client.(*apiClient).createSavedQuery(
+			ctx,
+			d.Get("repository").(string),
+			&s,
+		)
+	})
+	if err != nil {
+		return diag.Errorf("could not create saved query: %s", err)
+	}
+	d.SetId(fmt.Sprintf("%s+%s", d.Get("repository"), d.Get("name")))
+
+	return resourceSavedQueryRead(ctx, d, client)
+}
+
+func resourceSavedQueryRead(ctx context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+	// If we don't have a repository when importing, we parse it from the ID.
+	if _, ok := d.GetOk("repository"); !ok {
+		parts := parseRepositoryAndID(d.Id())
+		//we check that we have parsed the id into the correct number of segments
+		if parts[0] == "" || parts[1] == "" {
+			return diag.Errorf("error importing humio_saved_query. Please make sure the ID is in the form REPOSITORYNAME+SAVEDQUERYNAME (i.e. myRepoName+mySavedQueryName")
+		}
+		err := d.Set("repository", parts[0])
+		if err != nil {
+			return diag.Errorf("error setting repository for resource %s: %s", d.Id(), err)
+		}
+		err = d.Set("name", parts[1])
+		if err != nil {
+			return diag.Errorf("error setting name for resource %s: %s", d.Id(), err)
+		}
+	}
+
+	var s *savedQuery
+	err := client.(*apiClient).retry(ctx, "get saved query", func() error {
+		var err error
+		s, err = client.(*apiClient).getSavedQuery(
+			ctx,
+			d.Get("repository").(string),
+			d.Get("name").(string),
+		)
+		return err
+	})
+	if removeFromStateIfNotFound(d, "humio_saved_query", err) {
+		return nil
+	}
+	if err != nil {
+		return diag.Errorf("could not get saved query: %s", err)
+	}
+	return resourceDataFromSavedQuery(s, d)
+}
+
+func resourceDataFromSavedQuery(s *savedQuery, d *schema.ResourceData) diag.Diagnostics {
+	err := d.Set("name", s.Name)
+	if err != nil {
+		return diag.Errorf("error setting name for resource %s: %s", d.Id(), err)
+	}
+	err = d.Set("query", s.Query.QueryString)
+	if err != nil {
+		return diag.Errorf("error setting query for resource %s: %s", d.Id(), err)
+	}
+	err = d.Set("start", s.Query.Start)
+	if err != nil {
+		return diag.Errorf("error setting start for resource %s: %s", d.Id(), err)
+	}
+	err = d.Set("end", s.Query.End)
+	if err != nil {
+		return diag.Errorf("error setting end for resource %s: %s", d.Id(), err)
+	}
+	err = d.Set("is_live", s.Query.IsLive)
+	if err != nil {
+		return diag.Errorf("error setting is_live for resource %s: %s", d.Id(), err)
+	}
+	err = d.Set("widget_type", s.WidgetType)
+	if err != nil {
+		return diag.Errorf("error setting widget_type for resource %s: %s", d.Id(), err)
+	}
+	err = d.Set("options", s.Options)
+	if err != nil {
+		return diag.Errorf("error setting options for resource %s: %s", d.Id(), err)
+	}
+	return nil
+}
+
+func resourceSavedQueryUpdate(ctx context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+	s, err := savedQueryFromResourceData(d)
+	if err != nil {
+		return diag.Errorf("could not obtain saved query from resource data: %s", err)
+	}
+	repository := d.Get("repository").(string)
+
+	err = client.(*apiClient).retry(ctx, "update saved query", func() error {
+		existing, err := client.(*apiClient).getSavedQuery(ctx, repository, s.Name)
+		if err != nil {
+			return err
+		}
+		s.ID = existing.ID
+		return client.(*apiClient).updateSavedQuery(ctx, repository, &s)
+	})
+	if err != nil {
+		return diag.Errorf("could not update saved query: %s", err)
+	}
+	return resourceSavedQueryRead(ctx, d, client)
+}
+
+func resourceSavedQueryDelete(ctx context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+	repository := d.Get("repository").(string)
+
+	var s *savedQuery
+	err := client.(*apiClient).retry(ctx, "get saved query", func() error {
+		var err error
+		s, err = client.(*apiClient).getSavedQuery(ctx, repository, d.Get("name").(string))
+		return err
+	})
+	if isNotFoundError(err) {
+		return nil
+	}
+	if err != nil {
+		return diag.Errorf("could not get saved query: %s", err)
+	}
+
+	err = client.(*apiClient).call(ctx, func() error {
+		return client.(*apiClient).deleteSavedQuery(ctx, repository, s.ID)
+	})
+	if err != nil {
+		return diag.Errorf("could not delete saved query: %s", err)
+	}
+	return nil
+}
+
+func savedQueryFromResourceData(d *schema.ResourceData) (savedQuery, error) {
+	return savedQuery{
+		Name: d.Get("name").(string),
+		Query: humio.HumioQuery{
+			QueryString: d.Get("query").(string),
+			Start:       d.Get("start").(string),
+			End:         d.Get("end").(string),
+			IsLive:      d.Get("is_live").(bool),
+		},
+		WidgetType: d.Get("widget_type").(string),
+		Options:    d.Get("options").(string),
+	}, nil
+}
@@ -0,0 +1,159 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	humio "github.com/humio/cli/api"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccSavedQueryRequiredFields(t *testing.T) {
+	config := savedQueryEmpty
+	accTestCase(t, []resource.TestStep{
+		{Config: config, ExpectError: regexp.MustCompile(`The argument "repository" is required, but no definition was found.`)},
+		{Config: config, ExpectError: regexp.MustCompile(`The argument "name" is required, but no definition was found.`)},
+		{Config: config, ExpectError: regexp.MustCompile(`The argument "query" is required, but no definition was found.`)},
+	}, nil)
+}
+
+func TestAccSavedQueryInvalidInputs(t *testing.T) {
+	config := savedQueryInvalidInputs
+	accTestCase(t, []resource.TestStep{
+		{Config: config, ExpectError: regexp.MustCompile(`Inappropriate value for attribute "repository"`)},
+		{Config: config, ExpectError: regexp.MustCompile(`Inappropriate value for attribute "is_live"`)},
+		{Config: config, ExpectError: regexp.MustCompile(`"options" contains an invalid JSON`)},
+	}, nil)
+}
+
+func TestAccSavedQueryBasicToFull(t *testing.T) {
+	accTestCase(t, []resource.TestStep{
+		{
+			Config: savedQueryBasic,
+			Check: resource.ComposeTestCheckFunc(
+				resource.TestCheckResourceAttr("humio_saved_query.test", "repository", "sandbox"),
+				resource.TestCheckResourceAttr("humio_saved_query.test", "name", testAccPrefix+"saved-query"),
+				resource.TestCheckResourceAttr("humio_saved_query.test", "query", "count()"),
+				resource.TestCheckResourceAttr("humio_saved_query.test", "start", "24h"),
+				resource.TestCheckResourceAttr("humio_saved_query.test", "end", "now"),
+				resource.TestCheckResourceAttr("humio_saved_query.test", "is_live", "false"),
+			),
+		},
+		{
+			Config: savedQueryFull,
+			Check: resource.ComposeTestCheckFunc(
+				resource.TestCheckResourceAttr("humio_saved_query.test", "query", "loglevel=ERROR | timechart()"),
+				resource.TestCheckResourceAttr("humio_saved_query.test", "start", "7d"),
+				resource.TestCheckResourceAttr("humio_saved_query.test", "end", "1d"),
+				resource.TestCheckResourceAttr("humio_saved_query.test", "is_live", "true"),
+				resource.TestCheckResourceAttr("humio_saved_query.test", "widget_type", "time-chart"),
+			),
+		},
+		{
+			ResourceName:      "humio_saved_query.test",
+			ImportState:       true,
+			ImportStateId:     "sandbox+" + testAccPrefix + "saved-query",
+			ImportStateVerify: true,
+		},
+	}, testAccCheckSavedQueryDestroy)
+}
+
+func testAccCheckSavedQueryDestroy(s *terraform.State) error {
+	conn := testAccProviders["humio"].Meta().(*apiClient)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "humio_saved_query" {
+			continue
+		}
+		_, err := conn.getSavedQuery(context.Background(), rs.Primary.Attributes["repository"], rs.Primary.Attributes["name"])
+		if err == nil {
+			return fmt.Errorf("saved query still exists: %s", rs.Primary.ID)
+		}
+		if !isNotFoundError(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+const savedQueryEmpty = `
+resource "humio_saved_query" "test" {}
+`
+
+const savedQueryInvalidInputs = `
+resource "humio_saved_query" "test" {
+	repository = ["invalid"]
+	name       = "` + testAccPrefix + `saved-query"
+	query      = "count()"
+	is_live    = ["invalid"]
+	options    = "{invalid"
+}
+`
+
+const savedQueryBasic = `
+resource "humio_saved_query" "test" {
+	repository = "sandbox"
+	name       = "` + testAccPrefix + `saved-query"
+	query      = "count()"
+}
+`
+
+const savedQueryFull = `
+resource "humio_saved_query" "test" {
+	repository  = "sandbox"
+	name        = "` + testAccPrefix + `saved-query"
+	query       = "loglevel=ERROR | timechart()"
+	start       = "7d"
+	end         = "1d"
+	is_live     = true
+	widget_type = "time-chart"
+	options     = jsonencode({
+		"interpolation" = "monotone"
+		"series"        = {}
+	})
+}
+`
+
+var wantSavedQuery = savedQuery{
+	Name: "errors",
+	Query: humio.HumioQuery{
+		QueryString: "loglevel=ERROR | timechart()",
+		Start:       "7d",
+		End:         "now",
+		IsLive:      true,
+	},
+	WidgetType: "time-chart",
+	Options:    `{"interpolation":"monotone"}`,
+}
+
+func TestEncodeDecodeSavedQueryResource(t *testing.T) {
+	res := resourceSavedQuery()
+	data := res.TestResourceData()
+	resourceDataFromSavedQuery(&wantSavedQuery, data)
+	got, err := savedQueryFromResourceData(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cmp.Equal(wantSavedQuery, got) {
+		t.Error(cmp.Diff(wantSavedQuery, got))
+	}
+}
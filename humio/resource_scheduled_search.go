@@ -0,0 +1,275 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// Humio only accepts time zones given as an offset from UTC, e.g. UTC, UTC-01 or UTC+12:45.
+var rxScheduledSearchTimeZone = regexp.MustCompile(`^UTC([+-]\d{1,2}(:\d{2})?)?$`)
+
+func resourceScheduledSearch() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceScheduledSearchCreate,
+		ReadContext:   resourceScheduledSearchRead,
+		UpdateContext: resourceScheduledSearchUpdate,
+		DeleteContext: resourceScheduledSearchDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Timeouts:      defaultTimeouts(),
+		CustomizeDiff: customizeDiffRequireServerVersion("humio_scheduled_search", minScheduledSearchesVersion, "humio_alert"),
+
+		Schema: map[string]*schema.Schema{
+			"repository": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"description": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"query": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"start": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"end": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  "now",
+			},
+			"schedule": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ValidateDiagFunc: validateCronExpression,
+			},
+			"time_zone": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  "UTC",
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringMatch(
+					rxScheduledSearchTimeZone,
+					"must be UTC or an offset from UTC, e.g. UTC-01 or UTC+12:45",
+				)),
+			},
+			"backfill_limit": {
+				Type:             schema.TypeInt,
+				Optional:         true,
+				Default:          0,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.IntAtLeast(0)),
+			},
+			"enabled": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+			"actions": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"labels": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+func resourceScheduledSearchCreate(ctx context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+	s, err := scheduledSearchFromResourceData(d)
+	if err != nil {
+		return diag.Errorf("could not obtain scheduled search from resource data: %s", err)
+	}
+
+	err = client.(*apiClient).call(ctx, func() error {
+		return client.(*apiClient).createScheduledSearch(
+			ctx,
+			d.Get("repository").(string),
+			&s,
+		)
+	})
+	if err != nil {
+		return diag.Errorf("could not create scheduled search: %s", err)
+	}
+	d.SetId(fmt.Sprintf("%s+%s", d.Get("repository"), d.Get("name")))
+
+	return resourceScheduledSearchRead(ctx, d, client)
+}
+
+func resourceScheduledSearchRead(ctx context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+	// If we don't have a repository when importing, we parse it from the ID.
+	if _, ok := d.GetOk("repository"); !ok {
+		parts := parseRepositoryAndID(d.Id())
+		//we check that we have parsed the id into the correct number of segments
+		if parts[0] == "" || parts[1] == "" {
+			return diag.Errorf("error importing humio_scheduled_search. Please make sure the ID is in the form REPOSITORYNAME+SCHEDULEDSEARCHNAME (i.e. myRepoName+myScheduledSearchName")
+		}
+		err := d.Set("repository", parts[0])
+		if err != nil {
+			return diag.Errorf("error setting repository for resource %s: %s", d.Id(), err)
+		}
+		err = d.Set("name", parts[1])
+		if err != nil {
+			return diag.Errorf("error setting name for resource %s: %s", d.Id(), err)
+		}
+	}
+
+	var s *scheduledSearch
+	err := client.(*apiClient).retry(ctx, "get scheduled search", func() error {
+		var err error
+		s, err = client.(*apiClient).getScheduledSearch(
+			ctx,
+			d.Get("repository").(string),
+			d.Get("name").(string),
+		)
+		return err
+	})
+	if removeFromStateIfNotFound(d, "humio_scheduled_search", err) {
+		return nil
+	}
+	if err != nil {
+		return diag.Errorf("could not get scheduled search: %s", err)
+	}
+	return resourceDataFromScheduledSearch(s, d)
+}
+
+func resourceDataFromScheduledSearch(s *scheduledSearch, d *schema.ResourceData) diag.Diagnostics {
+	err := d.Set("name", s.Name)
+	if err != nil {
+		return diag.Errorf("error setting name for resource %s: %s", d.Id(), err)
+	}
+	err = d.Set("description", s.Description)
+	if err != nil {
+		return diag.Errorf("error setting description for resource %s: %s", d.Id(), err)
+	}
+	err = d.Set("query", s.QueryString)
+	if err != nil {
+		return diag.Errorf("error setting query for resource %s: %s", d.Id(), err)
+	}
+	err = d.Set("start", s.Start)
+	if err != nil {
+		return diag.Errorf("error setting start for resource %s: %s", d.Id(), err)
+	}
+	err = d.Set("end", s.End)
+	if err != nil {
+		return diag.Errorf("error setting end for resource %s: %s", d.Id(), err)
+	}
+	err = d.Set("schedule", s.Schedule)
+	if err != nil {
+		return diag.Errorf("error setting schedule for resource %s: %s", d.Id(), err)
+	}
+	err = d.Set("time_zone", s.TimeZone)
+	if err != nil {
+		return diag.Errorf("error setting time_zone for resource %s: %s", d.Id(), err)
+	}
+	err = d.Set("backfill_limit", s.BackfillLimit)
+	if err != nil {
+		return diag.Errorf("error setting backfill_limit for resource %s: %s", d.Id(), err)
+	}
+	err = d.Set("enabled", s.Enabled)
+	if err != nil {
+		return diag.Errorf("error setting enabled for resource %s: %s", d.Id(), err)
+	}
+	err = d.Set("actions", s.Actions)
+	if err != nil {
+		return diag.Errorf("error setting actions for resource %s: %s", d.Id(), err)
+	}
+	err = d.Set("labels", s.Labels)
+	if err != nil {
+		return diag.Errorf("error setting labels for resource %s: %s", d.Id(), err)
+	}
+	return nil
+}
+
+func resourceScheduledSearchUpdate(ctx context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+	s, err := scheduledSearchFromResourceData(d)
+	if err != nil {
+		return diag.Errorf("could not obtain scheduled search from resource data: %s", err)
+	}
+	repository := d.Get("repository").(string)
+
+	err = client.(*apiClient).retry(ctx, "update scheduled search", func() error {
+		existing, err := client.(*apiClient).getScheduledSearch(ctx, repository, s.Name)
+		if err != nil {
+			return err
+		}
+		s.ID = existing.ID
+		return client.(*apiClient).updateScheduledSearch(ctx, repository, &s)
+	})
+	if err != nil {
+		return diag.Errorf("could not update scheduled search: %s", err)
+	}
+	return resourceScheduledSearchRead(ctx, d, client)
+}
+
+func resourceScheduledSearchDelete(ctx context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+	repository := d.Get("repository").(string)
+
+	var s *scheduledSearch
+	err := client.(*apiClient).retry(ctx, "get scheduled search", func() error {
+		var err error
+		s, err = client.(*apiClient).getScheduledSearch(ctx, repository, d.Get("name").(string))
+		return err
+	})
+	if isNotFoundError(err) {
+		return nil
+	}
+	if err != nil {
+		return diag.Errorf("could not get scheduled search: %s", err)
+	}
+
+	err = client.(*apiClient).call(ctx, func() error {
+		return client.(*apiClient).deleteScheduledSearch(ctx, repository, s.ID)
+	})
+	if err != nil {
+		return diag.Errorf("could not delete scheduled search: %s", err)
+	}
+	return nil
+}
+
+func scheduledSearchFromResourceData(d *schema.ResourceData) (scheduledSearch, error) {
+	return scheduledSearch{
+		Name:          d.Get("name").(string),
+		Description:   d.Get("description").(string),
+		QueryString:   d.Get("query").(string),
+		Start:         d.Get("start").(string),
+		End:           d.Get("end").(string),
+		Schedule:      d.Get("schedule").(string),
+		TimeZone:      d.Get("time_zone").(string),
+		BackfillLimit: d.Get("backfill_limit").(int),
+		Enabled:       d.Get("enabled").(bool),
+		Actions:       convertInterfaceListToStringSlice(d.Get("actions").([]interface{})),
+		Labels:        convertInterfaceListToStringSlice(d.Get("labels").([]interface{})),
+	}, nil
+}
@@ -0,0 +1,181 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccScheduledSearchRequiredFields(t *testing.T) {
+	config := scheduledSearchEmpty
+	accTestCase(t, []resource.TestStep{
+		{Config: config, ExpectError: regexp.MustCompile(`The argument "repository" is required, but no definition was found.`)},
+		{Config: config, ExpectError: regexp.MustCompile(`The argument "name" is required, but no definition was found.`)},
+		{Config: config, ExpectError: regexp.MustCompile(`The argument "query" is required, but no definition was found.`)},
+		{Config: config, ExpectError: regexp.MustCompile(`The argument "start" is required, but no definition was found.`)},
+		{Config: config, ExpectError: regexp.MustCompile(`The argument "schedule" is required, but no definition was found.`)},
+	}, nil)
+}
+
+func TestAccScheduledSearchInvalidInputs(t *testing.T) {
+	config := scheduledSearchInvalidInputs
+	accTestCase(t, []resource.TestStep{
+		{Config: config, ExpectError: regexp.MustCompile(`0 25 \* \* \* is not a valid cron expression: invalid hour "25"`)},
+		{Config: config, ExpectError: regexp.MustCompile(`must be UTC or an offset from UTC`)},
+		{Config: config, ExpectError: regexp.MustCompile(`expected backfill_limit to be at least \(0\), got -1`)},
+	}, nil)
+}
+
+func TestAccScheduledSearchBasicToFull(t *testing.T) {
+	accTestCase(t, []resource.TestStep{
+		{
+			Config: scheduledSearchBasic,
+			Check: resource.ComposeTestCheckFunc(
+				resource.TestCheckResourceAttr("humio_scheduled_search.test", "repository", "sandbox"),
+				resource.TestCheckResourceAttr("humio_scheduled_search.test", "name", testAccPrefix+"scheduled-search"),
+				resource.TestCheckResourceAttr("humio_scheduled_search.test", "end", "now"),
+				resource.TestCheckResourceAttr("humio_scheduled_search.test", "schedule", "0 8 * * *"),
+				resource.TestCheckResourceAttr("humio_scheduled_search.test", "time_zone", "UTC"),
+				resource.TestCheckResourceAttr("humio_scheduled_search.test", "backfill_limit", "0"),
+				resource.TestCheckResourceAttr("humio_scheduled_search.test", "enabled", "true"),
+			),
+		},
+		{
+			Config: scheduledSearchFull,
+			Check: resource.ComposeTestCheckFunc(
+				resource.TestCheckResourceAttr("humio_scheduled_search.test", "description", "Weekly error report"),
+				resource.TestCheckResourceAttr("humio_scheduled_search.test", "start", "7d"),
+				resource.TestCheckResourceAttr("humio_scheduled_search.test", "end", "1h"),
+				resource.TestCheckResourceAttr("humio_scheduled_search.test", "schedule", "0 8 * * MON"),
+				resource.TestCheckResourceAttr("humio_scheduled_search.test", "time_zone", "UTC+01:00"),
+				resource.TestCheckResourceAttr("humio_scheduled_search.test", "backfill_limit", "3"),
+				resource.TestCheckResourceAttr("humio_scheduled_search.test", "enabled", "false"),
+				resource.TestCheckResourceAttr("humio_scheduled_search.test", "actions.#", "1"),
+				resource.TestCheckResourceAttr("humio_scheduled_search.test", "labels.#", "2"),
+			),
+		},
+		{
+			ResourceName:      "humio_scheduled_search.test",
+			ImportState:       true,
+			ImportStateId:     "sandbox+" + testAccPrefix + "scheduled-search",
+			ImportStateVerify: true,
+		},
+	}, testAccCheckScheduledSearchDestroy)
+}
+
+func testAccCheckScheduledSearchDestroy(s *terraform.State) error {
+	conn := testAccProviders["humio"].Meta().(*apiClient)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "humio_scheduled_search" {
+			continue
+		}
+		_, err := conn.getScheduledSearch(context.Background(), rs.Primary.Attributes["repository"], rs.Primary.Attributes["name"])
+		if err == nil {
+			return fmt.Errorf("scheduled search still exists: %s", rs.Primary.ID)
+		}
+		if !isNotFoundError(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+const scheduledSearchEmpty = `
+resource "humio_scheduled_search" "test" {}
+`
+
+const scheduledSearchInvalidInputs = `
+resource "humio_scheduled_search" "test" {
+	repository     = "sandbox"
+	name           = "` + testAccPrefix + `scheduled-search"
+	query          = "count()"
+	start          = "1d"
+	schedule       = "0 25 * * *"
+	time_zone      = "Europe/Copenhagen"
+	backfill_limit = -1
+}
+`
+
+const scheduledSearchBasic = `
+resource "humio_scheduled_search" "test" {
+	repository = "sandbox"
+	name       = "` + testAccPrefix + `scheduled-search"
+	query      = "count()"
+	start      = "1d"
+	schedule   = "0 8 * * *"
+}
+`
+
+const scheduledSearchFull = `
+resource "humio_notifier" "test" {
+	repository = "sandbox"
+	name       = "` + testAccPrefix + `scheduled-search"
+	entity     = "EmailNotifier"
+	email {
+		recipients = ["test@example.com"]
+	}
+}
+
+resource "humio_scheduled_search" "test" {
+	repository     = "sandbox"
+	name           = "` + testAccPrefix + `scheduled-search"
+	description    = "Weekly error report"
+	query          = "loglevel=ERROR | count()"
+	start          = "7d"
+	end            = "1h"
+	schedule       = "0 8 * * MON"
+	time_zone      = "UTC+01:00"
+	backfill_limit = 3
+	enabled        = false
+	actions        = [humio_notifier.test.notifier_id]
+	labels         = ["report", "weekly"]
+}
+`
+
+var wantScheduledSearch = scheduledSearch{
+	Name:          "weekly-errors",
+	Description:   "Weekly error report",
+	QueryString:   "loglevel=ERROR | count()",
+	Start:         "7d",
+	End:           "now",
+	Schedule:      "0 8 * * MON",
+	TimeZone:      "UTC+01:00",
+	BackfillLimit: 3,
+	Enabled:       true,
+	Actions:       []string{"abc123"},
+	Labels:        []string{"report", "weekly"},
+}
+
+func TestEncodeDecodeScheduledSearchResource(t *testing.T) {
+	res := resourceScheduledSearch()
+	data := res.TestResourceData()
+	resourceDataFromScheduledSearch(&wantScheduledSearch, data)
+	got, err := scheduledSearchFromResourceData(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cmp.Equal(wantScheduledSearch, got) {
+		t.Error(cmp.Diff(wantScheduledSearch, got))
+	}
+}
@@ -0,0 +1,197 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"context"
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+
+	humio "github.com/humio/cli/api"
+)
+
+func resourceUser() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceUserCreate,
+		ReadContext:   resourceUserRead,
+		UpdateContext: resourceUserUpdate,
+		DeleteContext: resourceUserDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Timeouts: defaultTimeouts(),
+
+		Schema: map[string]*schema.Schema{
+			"username": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"full_name": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"email": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"company": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"country_code": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringMatch(
+					regexp.MustCompile(`^[A-Z]{2}$`),
+					"must be a two-letter ISO 3166-1 country code in upper case",
+				)),
+			},
+			"is_root": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+		},
+	}
+}
+
+func resourceUserCreate(ctx context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+	user, err := userFromResourceData(d)
+	if err != nil {
+		return diag.Errorf("could not obtain user from resource data: %s", err)
+	}
+
+	err = client.(*apiClient).call(ctx, func() error {
+		return client.(*apiClient).addUser(
+			ctx,
+			user.Username,
+			userChangeSet(user),
+		)
+	})
+	if err != nil {
+		return diag.Errorf("could not create user: %s", err)
+	}
+	d.SetId(user.Username)
+
+	return resourceUserRead(ctx, d, client)
+}
+
+func resourceUserRead(ctx context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+	var user humio.User
+	err := client.(*apiClient).retry(ctx, "get user", func() error {
+		var err error
+		user, err = client.(*apiClient).getUser(ctx, d.Id())
+		return err
+	})
+	if err == nil && user.Username == "" {
+		err = newNotFoundError("user %s", d.Id())
+	}
+	if removeFromStateIfNotFound(d, "humio_user", err) {
+		return nil
+	}
+	if err != nil {
+		return diag.Errorf("could not get user: %s", err)
+	}
+	return resourceDataFromUser(&user, d)
+}
+
+func resourceDataFromUser(u *humio.User, d *schema.ResourceData) diag.Diagnostics {
+	err := d.Set("username", u.Username)
+	if err != nil {
+		return diag.Errorf("error setting username for resource %s: %s", d.Id(), err)
+	}
+	err = d.Set("full_name", u.FullName)
+	if err != nil {
+		return diag.Errorf("error setting full_name for resource %s: %s", d.Id(), err)
+	}
+	err = d.Set("email", u.Email)
+	if err != nil {
+		return diag.Errorf("error setting email for resource %s: %s", d.Id(), err)
+	}
+	err = d.Set("company", u.Company)
+	if err != nil {
+		return diag.Errorf("error setting company for resource %s: %s", d.Id(), err)
+	}
+	err = d.Set("country_code", u.CountryCode)
+	if err != nil {
+		return diag.Errorf("error setting country_code for resource %s: %s", d.Id(), err)
+	}
+	err = d.Set("is_root", u.IsRoot)
+	if err != nil {
+		return diag.Errorf("error setting is_root for resource %s: %s", d.Id(), err)
+	}
+	return nil
+}
+
+func resourceUserUpdate(ctx context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+	user, err := userFromResourceData(d)
+	if err != nil {
+		return diag.Errorf("could not obtain user from resource data: %s", err)
+	}
+
+	err = client.(*apiClient).retry(ctx, "update user", func() error {
+		return client.(*apiClient).updateUser(
+			ctx,
+			user.Username,
+			userChangeSet(user),
+		)
+	})
+	if err != nil {
+		return diag.Errorf("could not update user: %s", err)
+	}
+	return resourceUserRead(ctx, d, client)
+}
+
+func resourceUserDelete(ctx context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+	user, err := userFromResourceData(d)
+	if err != nil {
+		return diag.Errorf("could not obtain user from resource data: %s", err)
+	}
+
+	err = client.(*apiClient).call(ctx, func() error {
+		return client.(*apiClient).removeUser(ctx, user.Username)
+	})
+	if err != nil {
+		return diag.Errorf("could not delete user: %s", err)
+	}
+	return nil
+}
+
+func userFromResourceData(d *schema.ResourceData) (humio.User, error) {
+	return humio.User{
+		Username:    d.Get("username").(string),
+		FullName:    d.Get("full_name").(string),
+		Email:       d.Get("email").(string),
+		Company:     d.Get("company").(string),
+		CountryCode: d.Get("country_code").(string),
+		IsRoot:      d.Get("is_root").(bool),
+	}, nil
+}
+
+// userChangeSet sets every managed field, so values removed from the configuration are cleared in Humio as well.
+// The picture is left alone, as it is not managed by Terraform.
+func userChangeSet(u humio.User) humio.UserChangeSet {
+	return humio.UserChangeSet{
+		IsRoot:      &u.IsRoot,
+		FullName:    &u.FullName,
+		Email:       &u.Email,
+		Company:     &u.Company,
+		CountryCode: &u.CountryCode,
+	}
+}
@@ -0,0 +1,152 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	humio "github.com/humio/cli/api"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccUserRequiredFields(t *testing.T) {
+	config := userEmpty
+	accTestCase(t, []resource.TestStep{
+		{Config: config, ExpectError: regexp.MustCompile(`The argument "username" is required, but no definition was found.`)},
+	}, nil)
+}
+
+func TestAccUserInvalidInputs(t *testing.T) {
+	config := userInvalidInputs
+	accTestCase(t, []resource.TestStep{
+		{Config: config, ExpectError: regexp.MustCompile(`Inappropriate value for attribute "username"`)},
+		{Config: config, ExpectError: regexp.MustCompile(`Inappropriate value for attribute "full_name"`)},
+		{Config: config, ExpectError: regexp.MustCompile(`Inappropriate value for attribute "is_root"`)},
+	}, nil)
+}
+
+func TestAccUserInvalidCountryCode(t *testing.T) {
+	accTestCase(t, []resource.TestStep{
+		{Config: userInvalidCountryCode, ExpectError: regexp.MustCompile(`must be a two-letter ISO 3166-1 country code`)},
+	}, nil)
+}
+
+func TestAccUserBasicToFull(t *testing.T) {
+	accTestCase(t, []resource.TestStep{
+		{
+			Config: userBasic,
+			Check: resource.ComposeTestCheckFunc(
+				resource.TestCheckResourceAttr("humio_user.test", "username", testAccPrefix+"user@example.com"),
+				resource.TestCheckResourceAttr("humio_user.test", "full_name", ""),
+				resource.TestCheckResourceAttr("humio_user.test", "is_root", "false"),
+			),
+		},
+		{
+			Config: userFull,
+			Check: resource.ComposeTestCheckFunc(
+				resource.TestCheckResourceAttr("humio_user.test", "username", testAccPrefix+"user@example.com"),
+				resource.TestCheckResourceAttr("humio_user.test", "full_name", "Test User"),
+				resource.TestCheckResourceAttr("humio_user.test", "email", testAccPrefix+"user@example.com"),
+				resource.TestCheckResourceAttr("humio_user.test", "company", "Example"),
+				resource.TestCheckResourceAttr("humio_user.test", "country_code", "DK"),
+				resource.TestCheckResourceAttr("humio_user.test", "is_root", "true"),
+			),
+		},
+		{
+			ResourceName:      "humio_user.test",
+			ImportState:       true,
+			ImportStateVerify: true,
+		},
+	}, testAccCheckUserDestroy)
+}
+
+func testAccCheckUserDestroy(s *terraform.State) error {
+	conn := testAccProviders["humio"].Meta().(*apiClient)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "humio_user" {
+			continue
+		}
+		user, err := conn.getUser(context.Background(), rs.Primary.ID)
+		if err == nil && user.Username != "" {
+			return fmt.Errorf("user still exists: %s", rs.Primary.ID)
+		}
+	}
+	return nil
+}
+
+const userEmpty = `
+resource "humio_user" "test" {}
+`
+
+const userInvalidInputs = `
+resource "humio_user" "test" {
+	username  = ["invalid"]
+	full_name = ["invalid"]
+	is_root   = ["invalid"]
+}
+`
+
+const userInvalidCountryCode = `
+resource "humio_user" "test" {
+	username     = "` + testAccPrefix + `user@example.com"
+	country_code = "Denmark"
+}
+`
+
+const userBasic = `
+resource "humio_user" "test" {
+	username = "` + testAccPrefix + `user@example.com"
+}
+`
+
+const userFull = `
+resource "humio_user" "test" {
+	username     = "` + testAccPrefix + `user@example.com"
+	full_name    = "Test User"
+	email        = "` + testAccPrefix + `user@example.com"
+	company      = "Example"
+	country_code = "DK"
+	is_root      = true
+}
+`
+
+var wantUser = humio.User{
+	Username:    "jane@example.com",
+	FullName:    "Jane Doe",
+	Email:       "jane@example.com",
+	Company:     "Example",
+	CountryCode: "DK",
+	IsRoot:      true,
+}
+
+func TestEncodeDecodeUserResource(t *testing.T) {
+	res := resourceUser()
+	data := res.TestResourceData()
+	resourceDataFromUser(&wantUser, data)
+	got, err := userFromResourceData(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cmp.Equal(wantUser, got) {
+		t.Error(cmp.Diff(wantUser, got))
+	}
+}
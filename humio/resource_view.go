@@ -0,0 +1,197 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	humio "github.com/humio/cli/api"
+)
+
+func resourceView() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceViewCreate,
+		ReadContext:   resourceViewRead,
+		UpdateContext: resourceViewUpdate,
+		DeleteContext: resourceViewDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Timeouts: defaultTimeouts(),
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"description": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  "",
+			},
+			"repository_connection": {
+				Type:     schema.TypeSet,
+				Required: true,
+				MinItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"repository": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"filter": {
+							Type:     schema.TypeString,
+							Optional: true,
+							Default:  "",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func resourceViewCreate(ctx context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+	view, err := viewFromResourceData(d)
+	if err != nil {
+		return diag.Errorf("could not obtain view from resource data: %s", err)
+	}
+
+	err = client.(*apiClient).call(ctx, func() error {
+		return client.(*apiClient).createView(ctx, &view)
+	})
+	if err != nil {
+		return diag.Errorf("could not create view: %s", err)
+	}
+	d.SetId(view.Name)
+
+	return resourceViewRead(ctx, d, client)
+}
+
+func resourceViewRead(ctx context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+	var v *view
+	err := client.(*apiClient).retry(ctx, "get view", func() error {
+		var err error
+		v, err = client.(*apiClient).getView(ctx, d.Id())
+		return err
+	})
+	if removeFromStateIfNotFound(d, "humio_view", err) {
+		return nil
+	}
+	if err != nil {
+		return diag.Errorf("could not get view: %s", err)
+	}
+	return resourceDataFromView(v, d)
+}
+
+func resourceDataFromView(v *view, d *schema.ResourceData) diag.Diagnostics {
+	err := d.Set("name", v.Name)
+	if err != nil {
+		return diag.Errorf("error setting name for resource %s: %s", d.Id(), err)
+	}
+	err = d.Set("description", v.Description)
+	if err != nil {
+		return diag.Errorf("error setting description for resource %s: %s", d.Id(), err)
+	}
+	if err := d.Set("repository_connection", connectionsFromView(v)); err != nil {
+		return diag.Errorf("error setting connection settings for resource %s: %s", d.Id(), err)
+	}
+	return nil
+}
+
+func connectionsFromView(v *view) []tfMap {
+	var s []tfMap
+	for _, connection := range v.Connections {
+		s = append(s, tfMap{
+			"repository": connection.RepoName,
+			"filter":     connection.Filter,
+		})
+	}
+	return s
+}
+
+func resourceViewUpdate(ctx context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+	view, err := viewFromResourceData(d)
+	if err != nil {
+		return diag.Errorf("could not obtain view from resource data: %s", err)
+	}
+
+	if d.HasChange("description") {
+		err = client.(*apiClient).retry(ctx, "update description for view", func() error {
+			return client.(*apiClient).updateSearchDomainDescription(
+				ctx,
+				view.Name,
+				view.Description,
+			)
+		})
+		if err != nil {
+			return diag.Errorf("could not update description for view: %s", err)
+		}
+	}
+	if d.HasChange("repository_connection") {
+		err = client.(*apiClient).retry(ctx, "update connections for view", func() error {
+			return client.(*apiClient).updateViewConnections(
+				ctx,
+				view.Name,
+				view.Connections,
+			)
+		})
+		if err != nil {
+			return diag.Errorf("could not update connections for view: %s", err)
+		}
+	}
+
+	return resourceViewRead(ctx, d, client)
+}
+
+func resourceViewDelete(ctx context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+	view, err := viewFromResourceData(d)
+	if err != nil {
+		return diag.Errorf("could not obtain view from resource data: %s", err)
+	}
+
+	err = client.(*apiClient).call(ctx, func() error {
+		return client.(*apiClient).deleteView(
+			ctx,
+			view.Name,
+			"Deleted by Terraform",
+		)
+	})
+	if err != nil {
+		return diag.Errorf("could not delete view: %s", err)
+	}
+	return nil
+}
+
+func viewFromResourceData(d *schema.ResourceData) (view, error) {
+	var connections []humio.ViewConnection
+	for _, item := range d.Get("repository_connection").(*schema.Set).List() {
+		connection := item.(tfMap)
+		connections = append(connections, humio.ViewConnection{
+			RepoName: connection["repository"].(string),
+			Filter:   connection["filter"].(string),
+		})
+	}
+
+	return view{
+		Name:        d.Get("name").(string),
+		Description: d.Get("description").(string),
+		Connections: connections,
+	}, nil
+}
@@ -0,0 +1,167 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	humio "github.com/humio/cli/api"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccViewRequiredFields(t *testing.T) {
+	config := viewEmpty
+	accTestCase(t, []resource.TestStep{
+		{Config: config, ExpectError: regexp.MustCompile(`The argument "name" is required, but no definition was found.`)},
+		{Config: config, ExpectError: regexp.MustCompile(`At least 1 "repository_connection" blocks are required.`)},
+	}, nil)
+}
+
+func TestAccViewInvalidInputs(t *testing.T) {
+	config := viewInvalidInputs
+	accTestCase(t, []resource.TestStep{
+		{Config: config, ExpectError: regexp.MustCompile(`Inappropriate value for attribute "name"`)},
+		{Config: config, ExpectError: regexp.MustCompile(`Inappropriate value for attribute "description"`)},
+	}, nil)
+}
+
+func TestAccViewBasicToFull(t *testing.T) {
+	accTestCase(t, []resource.TestStep{
+		{
+			Config: viewBasic,
+			Check: resource.ComposeTestCheckFunc(
+				resource.TestCheckResourceAttr("humio_view.test", "name", testAccPrefix+"view"),
+				resource.TestCheckResourceAttr("humio_view.test", "description", ""),
+				resource.TestCheckResourceAttr("humio_view.test", "repository_connection.#", "1"),
+			),
+		},
+		{
+			Config: viewFull,
+			Check: resource.ComposeTestCheckFunc(
+				resource.TestCheckResourceAttr("humio_view.test", "name", testAccPrefix+"view"),
+				resource.TestCheckResourceAttr("humio_view.test", "description", "some description"),
+				resource.TestCheckResourceAttr("humio_view.test", "repository_connection.#", "2"),
+				resource.TestCheckResourceAttr("humio_alert.test", "repository", testAccPrefix+"view"),
+			),
+		},
+	}, testAccCheckViewDestroy)
+}
+
+func TestAccViewImport(t *testing.T) {
+	accTestCase(t, []resource.TestStep{
+		{
+			Config: viewBasic,
+		},
+		{
+			ResourceName:      "humio_view.test",
+			ImportState:       true,
+			ImportStateVerify: true,
+		},
+	}, testAccCheckViewDestroy)
+}
+
+func testAccCheckViewDestroy(s *terraform.State) error {
+	conn := testAccProviders["humio"].Meta().(*apiClient)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "humio_view" {
+			continue
+		}
+		_, err := conn.getView(context.Background(), rs.Primary.ID)
+		if err == nil {
+			return fmt.Errorf("view still exists: %s", rs.Primary.ID)
+		}
+		if !isNotFoundError(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+const viewEmpty = `
+resource "humio_view" "test" {}
+`
+
+const viewInvalidInputs = `
+resource "humio_view" "test" {
+    name        = ["invalid"]
+    description = ["invalid"]
+    repository_connection {
+        repository = "sandbox"
+    }
+}
+`
+
+const viewBasic = `
+resource "humio_view" "test" {
+    name = "` + testAccPrefix + `view"
+    repository_connection {
+        repository = "sandbox"
+    }
+}
+`
+
+const viewFull = `
+resource "humio_view" "test" {
+    name        = "` + testAccPrefix + `view"
+    description = "some description"
+    repository_connection {
+        repository = "sandbox"
+        filter     = "loglevel=ERROR"
+    }
+    repository_connection {
+        repository = "humio"
+        filter     = "#kind=logs"
+    }
+}
+
+resource "humio_alert" "test" {
+	repository           = humio_view.test.name
+	name                 = "` + testAccPrefix + `alert-view"
+	throttle_time_millis = 3600000
+	start                = "24h"
+	query                = "count()"
+}
+`
+
+var wantView = view{
+	Name:        "test-view",
+	Description: "errors across repositories",
+	Connections: []humio.ViewConnection{
+		{RepoName: "sandbox", Filter: "loglevel=ERROR"},
+		{RepoName: "humio", Filter: ""},
+	},
+}
+
+func TestEncodeDecodeViewResource(t *testing.T) {
+	res := resourceView()
+	data := res.TestResourceData()
+	resourceDataFromView(&wantView, data)
+	got, err := viewFromResourceData(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sortConnections := cmpopts.SortSlices(func(a, b humio.ViewConnection) bool { return a.RepoName < b.RepoName })
+	if !cmp.Equal(wantView, got, sortConnections) {
+		t.Error(cmp.Diff(wantView, got, sortConnections))
+	}
+}
@@ -0,0 +1,265 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+var updateSchemaSnapshot = flag.Bool("update-schema", false, "update the schema snapshot in testdata/schema.golden")
+
+// schemaSnapshotPath is the golden file holding the schema of the provider and its resources, one attribute per line.
+var schemaSnapshotPath = filepath.Join("testdata", "schema.golden")
+
+// TestSchemaSnapshot compares the schema of the provider and its resources with the golden file, so changes to it
+// which break existing configurations or state are noticed. Run the test with -update-schema to accept the changes.
+func TestSchemaSnapshot(t *testing.T) {
+	got := schemaSnapshot(Provider())
+	if *updateSchemaSnapshot {
+		if err := ioutil.WriteFile(schemaSnapshotPath, []byte(strings.Join(got, "\n")+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	raw, err := ioutil.ReadFile(schemaSnapshotPath)
+	if err != nil {
+		t.Fatalf("could not read schema snapshot, create it with -update-schema: %s", err)
+	}
+	want := strings.Split(strings.TrimSuffix(string(raw), "\n"), "\n")
+	if cmp.Equal(want, got) {
+		return
+	}
+	if breaking := breakingSchemaChanges(want, got); len(breaking) > 0 {
+		t.Errorf("the schema has changes breaking existing configurations or state:\n  %s", strings.Join(breaking, "\n  "))
+	}
+	t.Errorf("the schema differs from %s, update it with -update-schema if the changes are intended (-snapshot +schema):\n%s", schemaSnapshotPath, cmp.Diff(want, got))
+}
+
+// schemaSnapshot returns a line for each attribute of the provider and its resources, sorted by path. The defaults
+// of the provider are taken from an empty environment, as they are read from environment variables.
+func schemaSnapshot(p *schema.Provider) []string {
+	env := os.Environ()
+	os.Clearenv()
+	defer func() {
+		for _, kv := range env {
+			parts := strings.SplitN(kv, "=", 2)
+			os.Setenv(parts[0], parts[1])
+		}
+	}()
+
+	lines := schemaMapSnapshot("provider", p.Schema)
+	for name, r := range p.ResourcesMap {
+		lines = append(lines, schemaMapSnapshot(name, r.Schema)...)
+	}
+	sort.Slice(lines, func(i, j int) bool {
+		return strings.SplitN(lines[i], ": ", 2)[0] < strings.SplitN(lines[j], ": ", 2)[0]
+	})
+	return lines
+}
+
+func schemaMapSnapshot(path string, m map[string]*schema.Schema) []string {
+	var lines []string
+	for name, s := range m {
+		attrPath := path + "." + name
+		lines = append(lines, attrPath+": "+strings.Join(schemaSnapshotFields(s), ", "))
+		if r, ok := s.Elem.(*schema.Resource); ok {
+			lines = append(lines, schemaMapSnapshot(attrPath, r.Schema)...)
+		}
+	}
+	return lines
+}
+
+// schemaSnapshotFields returns the type of s, followed by the properties of s which matter to configurations and
+// state.
+func schemaSnapshotFields(s *schema.Schema) []string {
+	fields := []string{s.Type.String()}
+	switch elem := s.Elem.(type) {
+	case *schema.Schema:
+		fields[0] += " of " + elem.Type.String()
+	case *schema.Resource:
+		fields[0] += " of block"
+	}
+	for _, flag := range []struct {
+		name string
+		set  bool
+	}{
+		{"Required", s.Required},
+		{"Optional", s.Optional},
+		{"Computed", s.Computed},
+		{"ForceNew", s.ForceNew},
+		{"Sensitive", s.Sensitive},
+	} {
+		if flag.set {
+			fields = append(fields, flag.name)
+		}
+	}
+	if s.MinItems > 0 {
+		fields = append(fields, fmt.Sprintf("MinItems: %d", s.MinItems))
+	}
+	if s.MaxItems > 0 {
+		fields = append(fields, fmt.Sprintf("MaxItems: %d", s.MaxItems))
+	}
+	if s.Default != nil {
+		fields = append(fields, "Default: "+snapshotValue(s.Default))
+	}
+	if s.DefaultFunc != nil {
+		value, err := s.DefaultFunc()
+		if err != nil {
+			fields = append(fields, "DefaultFunc: error "+err.Error())
+		} else {
+			fields = append(fields, "DefaultFunc: "+snapshotValue(value))
+		}
+	}
+	return fields
+}
+
+func snapshotValue(v interface{}) string {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(raw)
+}
+
+// breakingSchemaChanges describes the changes from the snapshot want to the snapshot got which break existing
+// configurations or state: removed attributes, new required attributes, and attributes changing type, becoming
+// required, forcing replacement or changing their default.
+func breakingSchemaChanges(want, got []string) []string {
+	wantFields, gotFields := parseSchemaSnapshot(want), parseSchemaSnapshot(got)
+	var breaking []string
+	for _, path := range sortedSnapshotPaths(wantFields) {
+		old := wantFields[path]
+		new, ok := gotFields[path]
+		if !ok {
+			breaking = append(breaking, path+" was removed")
+			continue
+		}
+		if old[0] != new[0] {
+			breaking = append(breaking, fmt.Sprintf("%s changed type from %s to %s", path, old[0], new[0]))
+		}
+		for _, flag := range []string{"Required", "ForceNew"} {
+			if !hasSnapshotField(old, flag) && hasSnapshotField(new, flag) {
+				breaking = append(breaking, fmt.Sprintf("%s became %s", path, flag))
+			}
+		}
+		if oldDefault, newDefault := snapshotDefault(old), snapshotDefault(new); oldDefault != newDefault {
+			breaking = append(breaking, fmt.Sprintf("%s changed default from %q to %q", path, oldDefault, newDefault))
+		}
+	}
+	for _, path := range sortedSnapshotPaths(gotFields) {
+		if _, ok := wantFields[path]; !ok && hasSnapshotField(gotFields[path], "Required") && parentInSnapshot(wantFields, path) {
+			breaking = append(breaking, path+" was added as Required")
+		}
+	}
+	return breaking
+}
+
+func parseSchemaSnapshot(lines []string) map[string][]string {
+	fields := map[string][]string{}
+	for _, line := range lines {
+		parts := strings.SplitN(line, ": ", 2)
+		if len(parts) == 2 {
+			fields[parts[0]] = strings.Split(parts[1], ", ")
+		}
+	}
+	return fields
+}
+
+func sortedSnapshotPaths(fields map[string][]string) []string {
+	paths := make([]string, 0, len(fields))
+	for path := range fields {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+func hasSnapshotField(fields []string, field string) bool {
+	for _, f := range fields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+func snapshotDefault(fields []string) string {
+	for _, f := range fields {
+		if strings.HasPrefix(f, "Default: ") || strings.HasPrefix(f, "DefaultFunc: ") {
+			return f
+		}
+	}
+	return ""
+}
+
+// parentInSnapshot reports whether the resource or block holding the attribute at path is in the snapshot, so new
+// required attributes of new resources and blocks are not reported as breaking.
+func parentInSnapshot(fields map[string][]string, path string) bool {
+	parent := path[:strings.LastIndex(path, ".")]
+	if !strings.Contains(parent, ".") {
+		for p := range fields {
+			if strings.HasPrefix(p, parent+".") {
+				return true
+			}
+		}
+		return false
+	}
+	_, ok := fields[parent]
+	return ok
+}
+
+func TestBreakingSchemaChanges(t *testing.T) {
+	want := []string{
+		"humio_example.kept: TypeString, Optional",
+		"humio_example.mode: TypeString, Optional, Default: \"a\"",
+		"humio_example.name: TypeString, Optional",
+		"humio_example.removed: TypeString, Optional",
+		"humio_example.size: TypeInt, Optional",
+		"provider.addr: TypeString, Optional",
+	}
+	got := []string{
+		"humio_example.added: TypeString, Optional",
+		"humio_example.kept: TypeString, Optional, Sensitive",
+		"humio_example.mode: TypeString, Optional, Default: \"b\"",
+		"humio_example.name: TypeString, Required, ForceNew",
+		"humio_example.needed: TypeString, Required",
+		"humio_example.size: TypeString, Optional",
+		"humio_new.name: TypeString, Required",
+		"provider.addr: TypeString, Optional",
+	}
+	wantBreaking := []string{
+		`humio_example.mode changed default from "Default: \"a\"" to "Default: \"b\""`,
+		"humio_example.name became Required",
+		"humio_example.name became ForceNew",
+		"humio_example.removed was removed",
+		"humio_example.size changed type from TypeInt to TypeString",
+		"humio_example.needed was added as Required",
+	}
+	if got := breakingSchemaChanges(want, got); !cmp.Equal(wantBreaking, got) {
+		t.Error(cmp.Diff(wantBreaking, got))
+	}
+}
@@ -0,0 +1,597 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	humio "github.com/humio/cli/api"
+)
+
+func TestMain(m *testing.M) {
+	resource.TestMain(m)
+}
+
+// testAccPrefix starts the name of every object created by the acceptance tests. The sweepers only delete objects
+// whose names start with it, so they never touch anything else on a shared cluster. The tests build their names from
+// it, except for the package name, which is set in testdata/package/manifest.yaml.
+const testAccPrefix = "tf-acc-test-"
+
+// repositoryScopedSweepers are the sweepers of objects living in a repository or view, which are swept before the
+// repositories and views holding them.
+var repositoryScopedSweepers = []string{
+	"humio_action",
+	"humio_alert",
+	"humio_dashboard",
+	"humio_file",
+	"humio_group_role_assignment",
+	"humio_ingest_token",
+	"humio_notifier",
+	"humio_package",
+	"humio_parser",
+	"humio_saved_query",
+	"humio_scheduled_search",
+}
+
+// testSweepers holds a sweeper for every resource type, named after the type.
+var testSweepers = []*resource.Sweeper{
+	{Name: "humio_alert", F: sweepAlerts},
+	{Name: "humio_scheduled_search", F: sweepScheduledSearches},
+	{Name: "humio_action", Dependencies: []string{"humio_alert", "humio_scheduled_search"}, F: sweepActions},
+	{Name: "humio_notifier", Dependencies: []string{"humio_alert", "humio_scheduled_search"}, F: sweepNotifiers},
+	{Name: "humio_ingest_token", Dependencies: []string{"humio_alert"}, F: sweepIngestTokens},
+	{Name: "humio_parser", Dependencies: []string{"humio_alert", "humio_ingest_token"}, F: sweepParsers},
+	{Name: "humio_dashboard", F: sweepDashboards},
+	{Name: "humio_file", F: sweepFiles},
+	{Name: "humio_package", F: sweepPackages},
+	{Name: "humio_saved_query", F: sweepSavedQueries},
+	{Name: "humio_group_role_assignment", F: sweepGroupRoleAssignments},
+	{Name: "humio_group", Dependencies: []string{"humio_group_role_assignment"}, F: sweepGroups},
+	{Name: "humio_role", Dependencies: []string{"humio_group_role_assignment"}, F: sweepRoles},
+	{Name: "humio_view", Dependencies: repositoryScopedSweepers, F: sweepViews},
+	{Name: "humio_repository", Dependencies: append([]string{"humio_view"}, repositoryScopedSweepers...), F: sweepRepositories},
+	{Name: "humio_user", F: sweepUsers},
+}
+
+func init() {
+	for _, sweeper := range testSweepers {
+		resource.AddTestSweepers(sweeper.Name, sweeper)
+	}
+}
+
+func isTestAccName(name string) bool {
+	return strings.HasPrefix(name, testAccPrefix)
+}
+
+// sweeperClient returns a client for the cluster given by the same environment variables as the acceptance tests.
+// Sweepers only run against a real cluster, as every test starts a new fake Humio server.
+func sweeperClient() (*apiClient, error) {
+	if os.Getenv("HUMIO_ADDR") == "" {
+		return nil, fmt.Errorf("HUMIO_ADDR must be set to run sweepers")
+	}
+	p := Provider()
+	diags := p.Configure(context.Background(), terraform.NewResourceConfigRaw(map[string]interface{}{}))
+	if diags.HasError() {
+		return nil, fmt.Errorf("could not configure provider: %v", diags)
+	}
+	return p.Meta().(*apiClient), nil
+}
+
+// listSearchDomains returns the repositories and views in the cluster.
+func (c *apiClient) listSearchDomains(ctx context.Context) ([]humio.ViewListItem, error) {
+	var q struct {
+		SearchDomains []humio.ViewListItem `graphql:"searchDomains"`
+	}
+
+	err := c.query(ctx, &q, nil)
+	return q.SearchDomains, err
+}
+
+// listUsers returns the users in the cluster.
+func (c *apiClient) listUsers(ctx context.Context) ([]humio.User, error) {
+	var q struct {
+		Users []humio.User `graphql:"accounts"`
+	}
+
+	err := c.query(ctx, &q, nil)
+	return q.Users, err
+}
+
+// sweptSearchDomains returns the repositories and views that may contain objects created by the acceptance tests: the
+// sandbox repository and the repositories and views created by the tests.
+func sweptSearchDomains(client *apiClient) ([]string, error) {
+	searchDomains, err := client.listSearchDomains(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("could not list repositories and views: %s", err)
+	}
+	names := []string{"sandbox"}
+	for _, searchDomain := range searchDomains {
+		if isTestAccName(searchDomain.Name) {
+			names = append(names, searchDomain.Name)
+		}
+	}
+	return names, nil
+}
+
+// sweep calls remove for each object created by the acceptance tests in each repository or view that may contain
+// them, as listed by list. It carries on after failures and returns an error listing them.
+func sweep(region, objectType string, list func(client *apiClient, repository string) ([]string, error), remove func(client *apiClient, repository, name string) error) error {
+	client, err := sweeperClient()
+	if err != nil {
+		return err
+	}
+	repositories, err := sweptSearchDomains(client)
+	if err != nil {
+		return err
+	}
+
+	var failures []string
+	for _, repository := range repositories {
+		names, err := list(client, repository)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("could not list %ss in repository %s: %s", objectType, repository, err))
+			continue
+		}
+		for _, name := range names {
+			if !isTestAccName(name) {
+				continue
+			}
+			log.Printf("[INFO] Deleting %s %s in repository %s", objectType, name, repository)
+			if err := remove(client, repository, name); err != nil {
+				failures = append(failures, fmt.Sprintf("could not delete %s %s in repository %s: %s", objectType, name, repository, err))
+			}
+		}
+	}
+	return sweepFailures(failures)
+}
+
+func sweepFailures(failures []string) error {
+	if len(failures) > 0 {
+		return fmt.Errorf("%s", strings.Join(failures, "\n"))
+	}
+	return nil
+}
+
+func sweepAlerts(region string) error {
+	return sweep(region, "alert", func(client *apiClient, repository string) ([]string, error) {
+		alerts, err := client.listAlerts(context.Background(), repository)
+		var names []string
+		for _, alert := range alerts {
+			names = append(names, alert.Name)
+		}
+		return names, err
+	}, func(client *apiClient, repository, name string) error {
+		return client.deleteAlert(context.Background(), repository, name)
+	})
+}
+
+func sweepNotifiers(region string) error {
+	return sweep(region, "notifier", func(client *apiClient, repository string) ([]string, error) {
+		notifiers, err := client.listNotifiers(context.Background(), repository)
+		var names []string
+		for _, notifier := range notifiers {
+			names = append(names, notifier.Name)
+		}
+		return names, err
+	}, func(client *apiClient, repository, name string) error {
+		return client.deleteNotifier(context.Background(), repository, name)
+	})
+}
+
+func sweepIngestTokens(region string) error {
+	return sweep(region, "ingest token", func(client *apiClient, repository string) ([]string, error) {
+		tokens, err := client.listIngestTokens(context.Background(), repository)
+		var names []string
+		for _, token := range tokens {
+			names = append(names, token.Name)
+		}
+		return names, err
+	}, func(client *apiClient, repository, name string) error {
+		return client.removeIngestToken(context.Background(), repository, name)
+	})
+}
+
+func sweepParsers(region string) error {
+	return sweep(region, "parser", func(client *apiClient, repository string) ([]string, error) {
+		parsers, err := client.listParsers(context.Background(), repository)
+		var names []string
+		for _, parser := range parsers {
+			if !parser.IsBuiltIn {
+				names = append(names, parser.Name)
+			}
+		}
+		return names, err
+	}, func(client *apiClient, repository, name string) error {
+		return client.removeParser(context.Background(), repository, name)
+	})
+}
+
+func sweepActions(region string) error {
+	return sweep(region, "action", func(client *apiClient, repository string) ([]string, error) {
+		actions, err := client.listActions(context.Background(), repository)
+		var names []string
+		for _, a := range actions {
+			names = append(names, a.Name)
+		}
+		return names, err
+	}, func(client *apiClient, repository, name string) error {
+		a, err := client.getAction(context.Background(), repository, name)
+		if err != nil {
+			return err
+		}
+		return client.deleteAction(context.Background(), repository, a.ID)
+	})
+}
+
+func sweepScheduledSearches(region string) error {
+	return sweep(region, "scheduled search", func(client *apiClient, repository string) ([]string, error) {
+		searches, err := client.listScheduledSearches(context.Background(), repository)
+		var names []string
+		for _, s := range searches {
+			names = append(names, s.Name)
+		}
+		return names, err
+	}, func(client *apiClient, repository, name string) error {
+		s, err := client.getScheduledSearch(context.Background(), repository, name)
+		if err != nil {
+			return err
+		}
+		return client.deleteScheduledSearch(context.Background(), repository, s.ID)
+	})
+}
+
+func sweepSavedQueries(region string) error {
+	return sweep(region, "saved query", func(client *apiClient, repository string) ([]string, error) {
+		savedQueries, err := client.listSavedQueries(context.Background(), repository)
+		var names []string
+		for _, s := range savedQueries {
+			names = append(names, s.Name)
+		}
+		return names, err
+	}, func(client *apiClient, repository, name string) error {
+		s, err := client.getSavedQuery(context.Background(), repository, name)
+		if err != nil {
+			return err
+		}
+		return client.deleteSavedQuery(context.Background(), repository, s.ID)
+	})
+}
+
+func sweepDashboards(region string) error {
+	return sweep(region, "dashboard", func(client *apiClient, repository string) ([]string, error) {
+		dashboards, err := client.listDashboards(context.Background(), repository)
+		var names []string
+		for _, d := range dashboards {
+			names = append(names, d.Name)
+		}
+		return names, err
+	}, func(client *apiClient, repository, name string) error {
+		d, err := client.getDashboard(context.Background(), repository, name)
+		if err != nil {
+			return err
+		}
+		return client.deleteDashboard(context.Background(), d.ID)
+	})
+}
+
+func sweepFiles(region string) error {
+	return sweep(region, "file", func(client *apiClient, repository string) ([]string, error) {
+		return client.listFiles(context.Background(), repository)
+	}, func(client *apiClient, repository, name string) error {
+		return client.removeFile(context.Background(), repository, name)
+	})
+}
+
+func sweepPackages(region string) error {
+	return sweep(region, "package", func(client *apiClient, repository string) ([]string, error) {
+		installed, err := client.listInstalledPackages(context.Background(), repository)
+		var names []string
+		for _, p := range installed {
+			names = append(names, p.Name)
+		}
+		return names, err
+	}, func(client *apiClient, repository, name string) error {
+		return client.uninstallPackage(context.Background(), repository, name)
+	})
+}
+
+// sweepGroupRoleAssignments revokes the roles granted to groups created by the acceptance tests, the roles created by
+// them and the roles granted on their repositories and views.
+func sweepGroupRoleAssignments(region string) error {
+	client, err := sweeperClient()
+	if err != nil {
+		return err
+	}
+	groups, err := client.listGroups(context.Background(), "")
+	if err != nil {
+		return fmt.Errorf("could not list groups: %s", err)
+	}
+	roles, err := client.listRoles(context.Background())
+	if err != nil {
+		return fmt.Errorf("could not list roles: %s", err)
+	}
+	isTestRole := map[string]bool{}
+	for _, r := range roles {
+		isTestRole[r.ID] = isTestAccName(r.DisplayName)
+	}
+
+	var failures []string
+	for _, g := range groups {
+		assignments, err := client.getGroupRoles(context.Background(), g.ID)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("could not list roles of group %s: %s", g.DisplayName, err))
+			continue
+		}
+		for _, assignment := range assignments {
+			if !isTestAccName(g.DisplayName) && !isTestRole[assignment.RoleID] && !isTestAccName(assignment.Repository) {
+				continue
+			}
+			log.Printf("[INFO] Revoking role %s from group %s in repository %s", assignment.RoleID, g.DisplayName, assignment.Repository)
+			if err := client.unassignRoleFromGroup(context.Background(), g.ID, assignment); err != nil {
+				failures = append(failures, fmt.Sprintf("could not revoke role %s from group %s in repository %s: %s", assignment.RoleID, g.DisplayName, assignment.Repository, err))
+			}
+		}
+	}
+	return sweepFailures(failures)
+}
+
+func sweepGroups(region string) error {
+	client, err := sweeperClient()
+	if err != nil {
+		return err
+	}
+	groups, err := client.listGroups(context.Background(), testAccPrefix)
+	if err != nil {
+		return fmt.Errorf("could not list groups: %s", err)
+	}
+
+	var failures []string
+	for _, g := range groups {
+		if !isTestAccName(g.DisplayName) {
+			continue
+		}
+		log.Printf("[INFO] Deleting group %s", g.DisplayName)
+		if err := client.removeGroup(context.Background(), g.ID); err != nil {
+			failures = append(failures, fmt.Sprintf("could not delete group %s: %s", g.DisplayName, err))
+		}
+	}
+	return sweepFailures(failures)
+}
+
+func sweepRoles(region string) error {
+	client, err := sweeperClient()
+	if err != nil {
+		return err
+	}
+	roles, err := client.listRoles(context.Background())
+	if err != nil {
+		return fmt.Errorf("could not list roles: %s", err)
+	}
+
+	var failures []string
+	for _, r := range roles {
+		if !isTestAccName(r.DisplayName) {
+			continue
+		}
+		log.Printf("[INFO] Deleting role %s", r.DisplayName)
+		if err := client.removeRole(context.Background(), r.ID); err != nil {
+			failures = append(failures, fmt.Sprintf("could not delete role %s: %s", r.DisplayName, err))
+		}
+	}
+	return sweepFailures(failures)
+}
+
+func sweepViews(region string) error {
+	client, err := sweeperClient()
+	if err != nil {
+		return err
+	}
+	views, err := client.listSearchDomains(context.Background())
+	if err != nil {
+		return fmt.Errorf("could not list views: %s", err)
+	}
+	// Search domains include repositories, which are left to sweepRepositories.
+	repositories, err := client.listRepositories(context.Background())
+	if err != nil {
+		return fmt.Errorf("could not list repositories: %s", err)
+	}
+	isRepository := map[string]bool{}
+	for _, repository := range repositories {
+		isRepository[repository.Name] = true
+	}
+
+	var failures []string
+	for _, view := range views {
+		if !isTestAccName(view.Name) || isRepository[view.Name] {
+			continue
+		}
+		log.Printf("[INFO] Deleting view %s", view.Name)
+		if err := client.deleteView(context.Background(), view.Name, "Deleted by acceptance test sweeper"); err != nil {
+			failures = append(failures, fmt.Sprintf("could not delete view %s: %s", view.Name, err))
+		}
+	}
+	return sweepFailures(failures)
+}
+
+func sweepRepositories(region string) error {
+	client, err := sweeperClient()
+	if err != nil {
+		return err
+	}
+	repositories, err := client.listRepositories(context.Background())
+	if err != nil {
+		return fmt.Errorf("could not list repositories: %s", err)
+	}
+
+	var failures []string
+	for _, repository := range repositories {
+		if !isTestAccName(repository.Name) {
+			continue
+		}
+		log.Printf("[INFO] Deleting repository %s", repository.Name)
+		if err := client.deleteRepository(context.Background(), repository.Name, "Deleted by acceptance test sweeper", true); err != nil {
+			failures = append(failures, fmt.Sprintf("could not delete repository %s: %s", repository.Name, err))
+		}
+	}
+	return sweepFailures(failures)
+}
+
+func sweepUsers(region string) error {
+	client, err := sweeperClient()
+	if err != nil {
+		return err
+	}
+	users, err := client.listUsers(context.Background())
+	if err != nil {
+		return fmt.Errorf("could not list users: %s", err)
+	}
+
+	var failures []string
+	for _, user := range users {
+		if !isTestAccName(user.Username) {
+			continue
+		}
+		log.Printf("[INFO] Deleting user %s", user.Username)
+		if err := client.removeUser(context.Background(), user.Username); err != nil {
+			failures = append(failures, fmt.Sprintf("could not delete user %s: %s", user.Username, err))
+		}
+	}
+	return sweepFailures(failures)
+}
+
+func TestIsTestAccName(t *testing.T) {
+	for name, want := range map[string]bool{
+		"tf-acc-test-alert":            true,
+		"tf-acc-test-notifier-slack":   true,
+		"tf-acc-test-group-renamed":    true,
+		"tf-acc-test-file.csv":         true,
+		"tf-acc-test-user@example.com": true,
+		"sandbox":                      false,
+		"perf-test":                    false,
+		"load-test-eu":                 false,
+		"alert-test":                   false,
+		"my-tf-acc-test-repository":    false,
+	} {
+		if got := isTestAccName(name); got != want {
+			t.Errorf("isTestAccName(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
+
+// TestSweeperDependencies checks that every resource type has a sweeper, and that every dependency names a sweeper.
+func TestSweeperDependencies(t *testing.T) {
+	sweepers := map[string]bool{}
+	for _, sweeper := range testSweepers {
+		sweepers[sweeper.Name] = true
+	}
+	for resourceType := range Provider().ResourcesMap {
+		if !sweepers[resourceType] {
+			t.Errorf("no sweeper for %s", resourceType)
+		}
+	}
+	for _, sweeper := range testSweepers {
+		for _, dependency := range sweeper.Dependencies {
+			if !sweepers[dependency] {
+				t.Errorf("sweeper %s depends on unknown sweeper %s", sweeper.Name, dependency)
+			}
+		}
+	}
+}
+
+func TestSweepers(t *testing.T) {
+	server := newFakeHumioServer()
+	defer server.Close()
+	defer setTestEnv("HUMIO_ADDR", server.URL)()
+	defer setTestEnv("HUMIO_API_TOKEN", fakeHumioToken)()
+	client, err := sweeperClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, repository := range []string{"tf-acc-test-repository", "production", "perf-test"} {
+		if err := client.createRepository(context.Background(), repository); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, name := range []string{"tf-acc-test-parser", "accesslogs"} {
+		if err := client.addParser(context.Background(), "sandbox", &humio.Parser{Name: name, Script: "kvParse()"}, false); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, name := range []string{"tf-acc-test-ingest-token", "shipper"} {
+		if _, err := client.addIngestToken(context.Background(), "sandbox", name, "tf-acc-test-parser"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, name := range []string{"tf-acc-test-notifier-email", "oncall"} {
+		notifier := humio.Notifier{
+			Entity:     humio.NotifierTypeEmail,
+			Name:       name,
+			Properties: map[string]interface{}{"recipients": []string{"ops@example.com"}},
+		}
+		if err := client.createNotifier(context.Background(), "sandbox", &notifier); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, name := range []string{"tf-acc-test-alert", "errors"} {
+		alert := humio.Alert{Name: name, Query: humio.HumioQuery{QueryString: "loglevel=ERROR", Start: "1h"}}
+		if err := client.createAlert(context.Background(), "sandbox", &alert); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The sweepers of the types implemented by the fake Humio server, in dependency order.
+	for _, sweeper := range []func(string) error{sweepAlerts, sweepNotifiers, sweepIngestTokens, sweepParsers, sweepRepositories} {
+		if err := sweeper(""); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	repositories, _ := client.listRepositories(context.Background())
+	parsers, _ := client.listParsers(context.Background(), "sandbox")
+	tokens, _ := client.listIngestTokens(context.Background(), "sandbox")
+	notifiers, _ := client.listNotifiers(context.Background(), "sandbox")
+	alerts, _ := client.listAlerts(context.Background(), "sandbox")
+	var remaining []string
+	for _, r := range repositories {
+		remaining = append(remaining, r.Name)
+	}
+	for _, p := range parsers {
+		if !p.IsBuiltIn {
+			remaining = append(remaining, p.Name)
+		}
+	}
+	for _, i := range tokens {
+		remaining = append(remaining, i.Name)
+	}
+	for _, n := range notifiers {
+		remaining = append(remaining, n.Name)
+	}
+	for _, a := range alerts {
+		remaining = append(remaining, a.Name)
+	}
+	want := []string{"perf-test", "production", "sandbox", "accesslogs", "shipper", "oncall", "errors"}
+	if !cmp.Equal(want, remaining) {
+		t.Errorf("unexpected objects left after sweeping:\n%s", cmp.Diff(want, remaining))
+	}
+}
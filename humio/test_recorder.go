@@ -0,0 +1,296 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// testRecordingEnvVar selects whether acceptance tests record the requests they send to Humio and the responses to
+// them in a cassette, or replay the responses from the cassette instead of contacting Humio.
+const testRecordingEnvVar = "HUMIO_TEST_RECORDING"
+
+const (
+	recordingModeRecord = "record"
+	recordingModeReplay = "replay"
+)
+
+// cassettePath returns the file holding the cassette of the test with the given name.
+func cassettePath(testName string) string {
+	return filepath.Join("testdata", "cassettes", strings.ReplaceAll(testName, "/", "_")+".json")
+}
+
+// cassette holds the requests a test sent to Humio and the responses to them, in the order they were sent.
+type cassette struct {
+	Interactions []interaction `json:"interactions"`
+}
+
+type interaction struct {
+	Request  recordedRequest  `json:"request"`
+	Response recordedResponse `json:"response"`
+}
+
+type recordedRequest struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+	Body   string `json:"body,omitempty"`
+}
+
+type recordedResponse struct {
+	Status      int    `json:"status"`
+	ContentType string `json:"content_type,omitempty"`
+	Body        string `json:"body,omitempty"`
+}
+
+// recorder records the requests sent to Humio and the responses to them in a cassette, or replays the responses from
+// a cassette without sending the requests.
+//
+// The API token and the secrets found in responses are replaced with placeholders in the cassette. Replayed responses
+// hold the placeholders, so the requests sent while replaying hold them too, wherever the recorded requests held the
+// secrets. Values of keys which the request log redacts are redacted from recorded requests as well, and from the
+// requests sent while replaying before they are matched against the recorded ones.
+type recorder struct {
+	mode string
+
+	mu       sync.Mutex
+	cassette cassette
+	used     []bool
+	secrets  map[string]string
+	scrubber *strings.Replacer
+}
+
+// newRecorder returns a recorder recording a new cassette.
+func newRecorder() *recorder {
+	return &recorder{
+		mode:     recordingModeRecord,
+		secrets:  map[string]string{},
+		scrubber: strings.NewReplacer(),
+	}
+}
+
+// loadCassette returns a recorder replaying the cassette in the file at path.
+func loadCassette(path string) (*recorder, error) {
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	r := &recorder{mode: recordingModeReplay}
+	if err := json.Unmarshal(raw, &r.cassette); err != nil {
+		return nil, fmt.Errorf("could not parse cassette %s: %s", path, err)
+	}
+	r.used = make([]bool, len(r.cassette.Interactions))
+	return r, nil
+}
+
+// save writes the recorded cassette to the file at path.
+func (r *recorder) save(path string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	raw, err := json.MarshalIndent(r.cassette, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, append(raw, '\n'), 0644)
+}
+
+// transport returns an http.RoundTripper recording the requests sent with base, or replaying them, depending on the
+// mode of the recorder. It can be used as wrapAPITransport.
+func (r *recorder) transport(base http.RoundTripper) http.RoundTripper {
+	return &recorderTransport{recorder: r, base: base}
+}
+
+type recorderTransport struct {
+	recorder *recorder
+	base     http.RoundTripper
+}
+
+func (t *recorderTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	if t.recorder.mode == recordingModeReplay {
+		return t.recorder.replay(req, body)
+	}
+
+	if token := strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer "); token != req.Header.Get("Authorization") {
+		t.recorder.addSecret(token, "SCRUBBED-API-TOKEN")
+	}
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(respBody))
+	t.recorder.record(req, body, resp, respBody)
+	return resp, nil
+}
+
+// readRequestBody reads the body of req and replaces it with a copy, so it can still be sent.
+func readRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	body, err := ioutil.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+// addSecret scrubs secret from the cassette from now on, replacing it with a placeholder starting with prefix.
+func (r *recorder) addSecret(secret, prefix string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.addSecretLocked(secret, prefix)
+}
+
+func (r *recorder) addSecretLocked(secret, prefix string) {
+	if secret == "" {
+		return
+	}
+	if _, ok := r.secrets[secret]; ok {
+		return
+	}
+	r.secrets[secret] = fmt.Sprintf("%s-%d", prefix, len(r.secrets)+1)
+	pairs := make([]string, 0, 2*len(r.secrets))
+	// Replace longer secrets first, in case a secret contains another.
+	for _, secret := range sortedSecrets(r.secrets) {
+		pairs = append(pairs, secret, r.secrets[secret])
+	}
+	r.scrubber = strings.NewReplacer(pairs...)
+}
+
+func sortedSecrets(secrets map[string]string) []string {
+	sorted := make([]string, 0, len(secrets))
+	for secret := range secrets {
+		sorted = append(sorted, secret)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if len(sorted[i]) != len(sorted[j]) {
+			return len(sorted[i]) > len(sorted[j])
+		}
+		return sorted[i] < sorted[j]
+	})
+	return sorted
+}
+
+func (r *recorder) record(req *http.Request, body []byte, resp *http.Response, respBody []byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var v interface{}
+	if json.Unmarshal(respBody, &v) == nil {
+		for _, secret := range findSecrets(v, nil) {
+			r.addSecretLocked(secret, "SCRUBBED-TOKEN")
+		}
+	}
+	r.cassette.Interactions = append(r.cassette.Interactions, interaction{
+		Request: recordedRequest{
+			Method: req.Method,
+			Path:   r.scrubber.Replace(req.URL.RequestURI()),
+			Body:   normalizeRecordedBody([]byte(r.scrubber.Replace(string(body)))),
+		},
+		Response: recordedResponse{
+			Status:      resp.StatusCode,
+			ContentType: resp.Header.Get("Content-Type"),
+			Body:        r.scrubber.Replace(string(respBody)),
+		},
+	})
+}
+
+// findSecrets appends the values of redactedKeys found in v to secrets, such as the tokens of ingest tokens, which
+// Humio generates. Their values are scrubbed from everything stored in a cassette.
+func findSecrets(v interface{}, secrets []string) []string {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if s, ok := value.(string); ok && isRedactedKey(key) {
+				secrets = append(secrets, s)
+				continue
+			}
+			secrets = findSecrets(value, secrets)
+		}
+	case []interface{}:
+		for _, value := range v {
+			secrets = findSecrets(value, secrets)
+		}
+	}
+	return secrets
+}
+
+// normalizeRecordedBody redacts the secrets the request log redacts from a JSON request body, and returns any other
+// body as it is.
+func normalizeRecordedBody(body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+	if !json.Valid(body) {
+		return string(body)
+	}
+	return redactJSON(body)
+}
+
+func (r *recorder) replay(req *http.Request, body []byte) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	path := req.URL.RequestURI()
+	normalized := normalizeRecordedBody(body)
+	for i, recorded := range r.cassette.Interactions {
+		if r.used[i] || recorded.Request.Method != req.Method || recorded.Request.Path != path || recorded.Request.Body != normalized {
+			continue
+		}
+		r.used[i] = true
+		header := http.Header{}
+		if recorded.Response.ContentType != "" {
+			header.Set("Content-Type", recorded.Response.ContentType)
+		}
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", recorded.Response.Status, http.StatusText(recorded.Response.Status)),
+			StatusCode:    recorded.Response.Status,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          ioutil.NopCloser(strings.NewReader(recorded.Response.Body)),
+			ContentLength: int64(len(recorded.Response.Body)),
+			Request:       req,
+		}, nil
+	}
+	if normalized != "" {
+		path += " with body " + normalized
+	}
+	return nil, fmt.Errorf("no recorded response to %s %s, record the test again with %s=%s", req.Method, path, testRecordingEnvVar, recordingModeRecord)
+}
@@ -0,0 +1,149 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	humio "github.com/humio/cli/api"
+)
+
+// recordedSession configures the provider for addr with transports wrapped by rec, adds an ingest token and a
+// notifier sending to the ingest token, and returns the ingest token and the notifiers of the repository.
+func recordedSession(t *testing.T, rec *recorder, addr, token string) (*humio.IngestToken, []humio.Notifier, error) {
+	wrapAPITransport = rec.transport
+	defer func() {
+		wrapAPITransport = nil
+	}()
+
+	p := Provider()
+	diags := p.Configure(context.Background(), terraform.NewResourceConfigRaw(map[string]interface{}{
+		"addr":      addr,
+		"api_token": token,
+	}))
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	client := p.Meta().(*apiClient)
+
+	ingestToken, err := client.addIngestToken(context.Background(), "sandbox", testAccPrefix+"ingest-token", "json")
+	if err != nil {
+		return nil, nil, err
+	}
+	err = client.createNotifier(context.Background(), "sandbox", &humio.Notifier{
+		Entity:     humio.NotifierTypeHumioRepo,
+		Name:       testAccPrefix + "notifier",
+		Properties: map[string]interface{}{"ingestToken": ingestToken.Token},
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	notifiers, err := client.listNotifiers(context.Background(), "sandbox")
+	if err != nil {
+		return nil, nil, err
+	}
+	return ingestToken, notifiers, nil
+}
+
+func TestRecorder(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cassettes")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "TestRecorder.json")
+
+	server := newFakeHumioServer()
+	rec := newRecorder()
+	recordedToken, recordedNotifiers, err := recordedSession(t, rec, server.URL, fakeHumioToken)
+	server.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := rec.save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{fakeHumioToken, recordedToken.Token} {
+		if strings.Contains(string(raw), secret) {
+			t.Errorf("cassette contains secret %q:\n%s", secret, raw)
+		}
+	}
+
+	replayer, err := loadCassette(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	replayedToken, replayedNotifiers, err := recordedSession(t, replayer, "https://humio.invalid/", "SCRUBBED-API-TOKEN-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if replayedToken.Token != "SCRUBBED-TOKEN-2" {
+		t.Errorf("replayed ingest token is %q, want the placeholder SCRUBBED-TOKEN-2", replayedToken.Token)
+	}
+	recordedToken.Token = replayedToken.Token
+	if !cmp.Equal(recordedToken, replayedToken) {
+		t.Error(cmp.Diff(recordedToken, replayedToken))
+	}
+	recordedNotifiers[0].Properties["ingestToken"] = replayedToken.Token
+	if !cmp.Equal(recordedNotifiers, replayedNotifiers) {
+		t.Error(cmp.Diff(recordedNotifiers, replayedNotifiers))
+	}
+
+	// Each recorded response is replayed once.
+	req, err := http.NewRequest(http.MethodGet, "https://humio.invalid/api/v1/status", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := replayer.transport(nil).RoundTrip(req); err == nil || !strings.Contains(err.Error(), "no recorded response") {
+		t.Errorf("replaying a request again returned %v", err)
+	}
+}
+
+func TestCassettePath(t *testing.T) {
+	if got, want := cassettePath("TestAccNotifier/email"), filepath.Join("testdata", "cassettes", "TestAccNotifier_email.json"); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestFindSecrets(t *testing.T) {
+	var v interface{}
+	response := `{"data": {"searchDomain": {"notifiers": [
+		{"name": "opsgenie", "properties": {"apiUrl": "https://api.opsgenie.com", "genieKey": "secretgeniekey"}},
+		{"name": "humiorepo", "properties": {"ingestToken": "secrettoken"}}
+	]}}}`
+	if err := json.Unmarshal([]byte(response), &v); err != nil {
+		t.Fatal(err)
+	}
+	got := findSecrets(v, nil)
+	sort.Strings(got)
+	if want := []string{"secretgeniekey", "secrettoken"}; !cmp.Equal(want, got) {
+		t.Errorf("got secrets %q, want %q", got, want)
+	}
+}
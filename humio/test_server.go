@@ -0,0 +1,778 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// fakeHumioToken is the API token accepted by the fake Humio server.
+const fakeHumioToken = "fake-humio-token"
+
+// fakeHumioVersion is the version reported by the fake Humio server, recent enough for every resource it implements.
+const fakeHumioVersion = "1.24.0"
+
+// fakeHumioResources are the resource types the fake Humio server implements the API of.
+var fakeHumioResources = map[string]bool{
+	"humio_alert":        true,
+	"humio_ingest_token": true,
+	"humio_notifier":     true,
+	"humio_parser":       true,
+	"humio_repository":   true,
+}
+
+// fakeHumioBuiltInParsers are the parsers available in every repository of the fake Humio server.
+var fakeHumioBuiltInParsers = []string{"accesslog", "json", "kv", "syslog"}
+
+// fakeHumio is an in-memory implementation of the parts of the Humio REST and GraphQL APIs used for repositories,
+// parsers, ingest tokens, notifiers and alerts. It starts out with an empty repository named sandbox, which the
+// acceptance tests expect to exist.
+type fakeHumio struct {
+	mu           sync.Mutex
+	repositories map[string]*fakeRepository
+	lastID       int
+}
+
+type fakeRepository struct {
+	name                      string
+	description               string
+	timeBasedRetention        float64
+	ingestSizeBasedRetention  float64
+	storageSizeBasedRetention float64
+	parsers                   map[string]*fakeParser
+	ingestTokens              map[string]*fakeIngestToken
+	// rest holds the objects managed through the REST API by collection, e.g. alerts, and ID.
+	rest map[string]map[string]map[string]interface{}
+}
+
+type fakeParser struct {
+	name       string
+	sourceCode string
+	testData   []interface{}
+	tagFields  []interface{}
+}
+
+type fakeIngestToken struct {
+	name   string
+	token  string
+	parser string
+}
+
+// newFakeHumioServer starts a fake Humio server. The caller must close it.
+func newFakeHumioServer() *httptest.Server {
+	f := &fakeHumio{repositories: map[string]*fakeRepository{}}
+	f.addRepository("sandbox")
+	return httptest.NewServer(f)
+}
+
+func (f *fakeHumio) addRepository(name string) *fakeRepository {
+	repo := &fakeRepository{
+		name:         name,
+		parsers:      map[string]*fakeParser{},
+		ingestTokens: map[string]*fakeIngestToken{},
+		rest: map[string]map[string]map[string]interface{}{
+			"alertnotifiers": {},
+			"alerts":         {},
+		},
+	}
+	f.repositories[name] = repo
+	return repo
+}
+
+func (f *fakeHumio) nextID() string {
+	f.lastID++
+	return fmt.Sprintf("fake%06d", f.lastID)
+}
+
+func (f *fakeHumio) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/api/v1/status" {
+		writeFakeJSON(w, http.StatusOK, map[string]string{"status": "OK", "version": fakeHumioVersion})
+		return
+	}
+	if r.Header.Get("Authorization") != "Bearer "+fakeHumioToken {
+		http.Error(w, "invalid API token", http.StatusUnauthorized)
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch {
+	case r.URL.Path == "/graphql" && r.Method == http.MethodPost:
+		f.serveGraphQL(w, r)
+	case strings.HasPrefix(r.URL.Path, "/api/v1/repositories/"):
+		f.serveREST(w, r)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func writeFakeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// serveREST implements the alert and notifier endpoints. The Humio API client stops the process if it cannot decode a
+// response, so errors are returned as an empty JSON object, and listing a missing repository returns no objects.
+func (f *fakeHumio) serveREST(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/v1/repositories/"), "/"), "/")
+	if len(parts) < 2 || len(parts) > 3 {
+		http.NotFound(w, r)
+		return
+	}
+	repo := f.repositories[parts[0]]
+	var collection map[string]map[string]interface{}
+	if repo != nil {
+		collection = repo.rest[parts[1]]
+	}
+
+	if len(parts) == 2 {
+		switch r.Method {
+		case http.MethodGet:
+			ids := make([]string, 0, len(collection))
+			for id := range collection {
+				ids = append(ids, id)
+			}
+			sort.Strings(ids)
+			objects := make([]map[string]interface{}, len(ids))
+			for i, id := range ids {
+				objects[i] = collection[id]
+			}
+			writeFakeJSON(w, http.StatusOK, objects)
+		case http.MethodPost:
+			if collection == nil {
+				writeFakeJSON(w, http.StatusNotFound, struct{}{})
+				return
+			}
+			var object map[string]interface{}
+			if err := json.NewDecoder(r.Body).Decode(&object); err != nil {
+				writeFakeJSON(w, http.StatusBadRequest, struct{}{})
+				return
+			}
+			object["id"] = f.nextID()
+			collection[object["id"].(string)] = object
+			writeFakeJSON(w, http.StatusCreated, object)
+		default:
+			writeFakeJSON(w, http.StatusMethodNotAllowed, struct{}{})
+		}
+		return
+	}
+
+	id := parts[2]
+	if _, ok := collection[id]; !ok {
+		writeFakeJSON(w, http.StatusNotFound, struct{}{})
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		writeFakeJSON(w, http.StatusOK, collection[id])
+	case http.MethodPut:
+		var object map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&object); err != nil {
+			writeFakeJSON(w, http.StatusBadRequest, struct{}{})
+			return
+		}
+		object["id"] = id
+		collection[id] = object
+		writeFakeJSON(w, http.StatusOK, object)
+	case http.MethodDelete:
+		delete(collection, id)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeFakeJSON(w, http.StatusMethodNotAllowed, struct{}{})
+	}
+}
+
+func (f *fakeHumio) serveGraphQL(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Query     string
+		Variables map[string]interface{}
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	data, err := f.executeGraphQL(req.Query, req.Variables)
+	if err != nil {
+		writeFakeJSON(w, http.StatusOK, map[string]interface{}{
+			"data":   nil,
+			"errors": []map[string]string{{"message": err.Error()}},
+		})
+		return
+	}
+	writeFakeJSON(w, http.StatusOK, map[string]interface{}{"data": data})
+}
+
+// graphQLResolver computes the value of a field taking arguments.
+type graphQLResolver func(args map[string]interface{}) (interface{}, error)
+
+func (f *fakeHumio) executeGraphQL(query string, variables map[string]interface{}) (interface{}, error) {
+	p := &graphQLParser{src: query, variables: variables}
+	mutation, fields, err := p.parseOperation()
+	if err != nil {
+		return nil, err
+	}
+	root := f.queryRoot()
+	if mutation {
+		root = f.mutationRoot()
+	}
+	return projectGraphQL(root, fields)
+}
+
+// projectGraphQL returns the fields of v selected by fields, calling resolvers with the arguments of their field.
+func projectGraphQL(v interface{}, fields []graphQLField) (interface{}, error) {
+	if len(fields) == 0 {
+		return v, nil
+	}
+	switch v := v.(type) {
+	case nil:
+		return nil, nil
+	case []map[string]interface{}:
+		out := make([]interface{}, len(v))
+		for i, item := range v {
+			var err error
+			if out[i], err = projectGraphQL(item, fields); err != nil {
+				return nil, err
+			}
+		}
+		return out, nil
+	case map[string]interface{}:
+		out := map[string]interface{}{}
+		for _, field := range fields {
+			value, ok := v[field.name]
+			if !ok {
+				return nil, fmt.Errorf("Cannot query field %q on type %q", field.name, v["__typename"])
+			}
+			if resolve, ok := value.(graphQLResolver); ok {
+				var err error
+				if value, err = resolve(field.args); err != nil {
+					return nil, err
+				}
+			}
+			projected, err := projectGraphQL(value, field.selections)
+			if err != nil {
+				return nil, err
+			}
+			out[field.name] = projected
+		}
+		return out, nil
+	default:
+		return nil, fmt.Errorf("cannot select fields of %T", v)
+	}
+}
+
+func (f *fakeHumio) queryRoot() map[string]interface{} {
+	return map[string]interface{}{
+		"__typename": "Query",
+		"repository": graphQLResolver(func(args map[string]interface{}) (interface{}, error) {
+			repo, err := f.repository(args, "name")
+			if err != nil {
+				return nil, err
+			}
+			return repo.object(), nil
+		}),
+		"repositories": graphQLResolver(func(args map[string]interface{}) (interface{}, error) {
+			return f.repositoryObjects(), nil
+		}),
+		// The fake server has no views, so the search domains are the repositories.
+		"searchDomains": graphQLResolver(func(args map[string]interface{}) (interface{}, error) {
+			return f.repositoryObjects(), nil
+		}),
+	}
+}
+
+func (f *fakeHumio) repositoryObjects() []map[string]interface{} {
+	names := make([]string, 0, len(f.repositories))
+	for name := range f.repositories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	repos := make([]map[string]interface{}, len(names))
+	for i, name := range names {
+		repos[i] = f.repositories[name].object()
+	}
+	return repos
+}
+
+func (f *fakeHumio) mutationRoot() map[string]interface{} {
+	result := func(typename string) map[string]interface{} {
+		return map[string]interface{}{"__typename": typename}
+	}
+	return map[string]interface{}{
+		"__typename": "Mutation",
+		"createRepository": graphQLResolver(func(args map[string]interface{}) (interface{}, error) {
+			name := stringArg(args, "name")
+			if _, ok := f.repositories[name]; ok || name == "" {
+				return nil, fmt.Errorf("repository %q already exists", name)
+			}
+			return map[string]interface{}{
+				"__typename": "CreateRepositoryMutation",
+				"repository": f.addRepository(name).object(),
+			}, nil
+		}),
+		"deleteSearchDomain": graphQLResolver(func(args map[string]interface{}) (interface{}, error) {
+			repo, err := f.repository(args, "name")
+			if err != nil {
+				return nil, err
+			}
+			delete(f.repositories, repo.name)
+			return result("BooleanResultType"), nil
+		}),
+		"updateDescriptionForSearchDomain": graphQLResolver(func(args map[string]interface{}) (interface{}, error) {
+			repo, err := f.repository(args, "name")
+			if err != nil {
+				return nil, err
+			}
+			repo.description = stringArg(args, "newDescription")
+			return result("UpdateDescriptionMutation"), nil
+		}),
+		"updateRetention": graphQLResolver(func(args map[string]interface{}) (interface{}, error) {
+			repo, err := f.repository(args, "repositoryName")
+			if err != nil {
+				return nil, err
+			}
+			for name, retention := range map[string]*float64{
+				"timeBasedRetention":        &repo.timeBasedRetention,
+				"ingestSizeBasedRetention":  &repo.ingestSizeBasedRetention,
+				"storageSizeBasedRetention": &repo.storageSizeBasedRetention,
+			} {
+				if v, ok := args[name]; ok {
+					*retention, _ = v.(float64)
+				}
+			}
+			return result("UpdateRetentionMutation"), nil
+		}),
+		"createParser": graphQLResolver(func(args map[string]interface{}) (interface{}, error) {
+			input, _ := args["input"].(map[string]interface{})
+			repo, err := f.repository(input, "repositoryName")
+			if err != nil {
+				return nil, err
+			}
+			name := stringArg(input, "name")
+			if _, ok := repo.parsers[name]; ok && input["force"] != true {
+				return nil, fmt.Errorf("parser %q already exists", name)
+			}
+			testData, _ := input["testData"].([]interface{})
+			tagFields, _ := input["tagFields"].([]interface{})
+			repo.parsers[name] = &fakeParser{
+				name:       name,
+				sourceCode: stringArg(input, "sourceCode"),
+				testData:   testData,
+				tagFields:  tagFields,
+			}
+			return result("CreateParserMutation"), nil
+		}),
+		"removeParser": graphQLResolver(func(args map[string]interface{}) (interface{}, error) {
+			input, _ := args["input"].(map[string]interface{})
+			repo, err := f.repository(input, "repositoryName")
+			if err != nil {
+				return nil, err
+			}
+			name := stringArg(input, "name")
+			if _, ok := repo.parsers[name]; !ok {
+				return nil, fmt.Errorf("parser %q not found", name)
+			}
+			delete(repo.parsers, name)
+			return result("RemoveParserMutation"), nil
+		}),
+		"addIngestToken": graphQLResolver(func(args map[string]interface{}) (interface{}, error) {
+			repo, err := f.repository(args, "repositoryName")
+			if err != nil {
+				return nil, err
+			}
+			name := stringArg(args, "name")
+			if _, ok := repo.ingestTokens[name]; ok {
+				return nil, fmt.Errorf("ingest token %q already exists", name)
+			}
+			if err := repo.checkParser(stringArg(args, "parser")); err != nil {
+				return nil, err
+			}
+			token := &fakeIngestToken{name: name, token: f.nextID(), parser: stringArg(args, "parser")}
+			repo.ingestTokens[name] = token
+			return map[string]interface{}{
+				"__typename":  "AddIngestTokenMutation",
+				"ingestToken": token.object(),
+			}, nil
+		}),
+		"assignIngestToken": graphQLResolver(func(args map[string]interface{}) (interface{}, error) {
+			repo, err := f.repository(args, "repositoryName")
+			if err != nil {
+				return nil, err
+			}
+			token, ok := repo.ingestTokens[stringArg(args, "tokenName")]
+			if !ok {
+				return nil, fmt.Errorf("ingest token %q not found", stringArg(args, "tokenName"))
+			}
+			if err := repo.checkParser(stringArg(args, "parserName")); err != nil {
+				return nil, err
+			}
+			token.parser = stringArg(args, "parserName")
+			return result("AssignIngestTokenMutation"), nil
+		}),
+		"removeIngestToken": graphQLResolver(func(args map[string]interface{}) (interface{}, error) {
+			repo, err := f.repository(args, "repositoryName")
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := repo.ingestTokens[stringArg(args, "name")]; !ok {
+				return nil, fmt.Errorf("ingest token %q not found", stringArg(args, "name"))
+			}
+			delete(repo.ingestTokens, stringArg(args, "name"))
+			return result("BooleanResultType"), nil
+		}),
+	}
+}
+
+// repository returns the repository named by the argument key of args.
+func (f *fakeHumio) repository(args map[string]interface{}, key string) (*fakeRepository, error) {
+	name := stringArg(args, key)
+	repo, ok := f.repositories[name]
+	if !ok {
+		return nil, fmt.Errorf("repository %q not found", name)
+	}
+	return repo, nil
+}
+
+func (r *fakeRepository) object() map[string]interface{} {
+	parsers := make([]map[string]interface{}, 0, len(r.parsers)+len(fakeHumioBuiltInParsers))
+	for _, name := range fakeHumioBuiltInParsers {
+		parsers = append(parsers, map[string]interface{}{"__typename": "Parser", "name": name, "isBuiltIn": true})
+	}
+	for _, name := range sortedKeys(r.parsers) {
+		parsers = append(parsers, map[string]interface{}{"__typename": "Parser", "name": name, "isBuiltIn": false})
+	}
+	tokens := make([]map[string]interface{}, 0, len(r.ingestTokens))
+	for _, name := range sortedKeys(r.ingestTokens) {
+		tokens = append(tokens, r.ingestTokens[name].object())
+	}
+
+	return map[string]interface{}{
+		"__typename":                "Repository",
+		"name":                      r.name,
+		"description":               r.description,
+		"timeBasedRetention":        nullIfZero(r.timeBasedRetention),
+		"ingestSizeBasedRetention":  nullIfZero(r.ingestSizeBasedRetention),
+		"storageSizeBasedRetention": nullIfZero(r.storageSizeBasedRetention),
+		"compressedByteSize":        0,
+		"parsers":                   parsers,
+		"parser": graphQLResolver(func(args map[string]interface{}) (interface{}, error) {
+			p, ok := r.parsers[stringArg(args, "name")]
+			if !ok {
+				return nil, nil
+			}
+			return map[string]interface{}{
+				"__typename": "Parser",
+				"name":       p.name,
+				"sourceCode": p.sourceCode,
+				"testData":   p.testData,
+				"tagFields":  p.tagFields,
+			}, nil
+		}),
+		"ingestTokens": tokens,
+	}
+}
+
+// checkParser returns an error unless name is empty or the name of a parser in the repository.
+func (r *fakeRepository) checkParser(name string) error {
+	if _, ok := r.parsers[name]; ok || name == "" {
+		return nil
+	}
+	for _, builtIn := range fakeHumioBuiltInParsers {
+		if name == builtIn {
+			return nil
+		}
+	}
+	return fmt.Errorf("parser %q not found", name)
+}
+
+func (t *fakeIngestToken) object() map[string]interface{} {
+	var parser interface{}
+	if t.parser != "" {
+		parser = map[string]interface{}{"__typename": "Parser", "name": t.parser}
+	}
+	return map[string]interface{}{
+		"__typename": "IngestToken",
+		"name":       t.name,
+		"token":      t.token,
+		"parser":     parser,
+	}
+}
+
+func sortedKeys(m interface{}) []string {
+	var keys []string
+	switch m := m.(type) {
+	case map[string]*fakeParser:
+		for key := range m {
+			keys = append(keys, key)
+		}
+	case map[string]*fakeIngestToken:
+		for key := range m {
+			keys = append(keys, key)
+		}
+	case map[string]interface{}:
+		for key := range m {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func nullIfZero(f float64) interface{} {
+	if f == 0 {
+		return nil
+	}
+	return f
+}
+
+func stringArg(args map[string]interface{}, key string) string {
+	s, _ := args[key].(string)
+	return s
+}
+
+// graphQLField is a field selected by a GraphQL operation, with its arguments resolved against the variables.
+type graphQLField struct {
+	name       string
+	args       map[string]interface{}
+	selections []graphQLField
+}
+
+// graphQLParser parses the subset of GraphQL sent by the provider: a single query or mutation of fields with
+// arguments, without fragments, aliases or directives.
+type graphQLParser struct {
+	src       string
+	pos       int
+	variables map[string]interface{}
+}
+
+func (p *graphQLParser) parseOperation() (bool, []graphQLField, error) {
+	mutation := false
+	p.skipIgnored()
+	if p.peek() != '{' {
+		switch keyword := p.name(); keyword {
+		case "query":
+		case "mutation":
+			mutation = true
+		default:
+			return false, nil, p.errorf("unsupported operation %q", keyword)
+		}
+		p.skipIgnored()
+		if isNameStart(p.peek()) {
+			p.name()
+			p.skipIgnored()
+		}
+		if p.peek() == '(' {
+			// The variable definitions are skipped, as the variables are taken as given.
+			if err := p.skipBalanced('(', ')'); err != nil {
+				return false, nil, err
+			}
+		}
+	}
+	fields, err := p.parseSelectionSet()
+	if err != nil {
+		return false, nil, err
+	}
+	p.skipIgnored()
+	if p.pos != len(p.src) {
+		return false, nil, p.errorf("unexpected %q", p.src[p.pos:])
+	}
+	return mutation, fields, nil
+}
+
+func (p *graphQLParser) parseSelectionSet() ([]graphQLField, error) {
+	if err := p.expect('{'); err != nil {
+		return nil, err
+	}
+	var fields []graphQLField
+	for {
+		p.skipIgnored()
+		if p.peek() == '}' {
+			p.pos++
+			return fields, nil
+		}
+		field := graphQLField{name: p.name(), args: map[string]interface{}{}}
+		if field.name == "" {
+			return nil, p.errorf("expected field")
+		}
+		p.skipIgnored()
+		if p.peek() == '(' {
+			p.pos++
+			for {
+				p.skipIgnored()
+				if p.peek() == ')' {
+					p.pos++
+					break
+				}
+				name := p.name()
+				if err := p.expect(':'); err != nil {
+					return nil, err
+				}
+				value, err := p.parseValue()
+				if err != nil {
+					return nil, err
+				}
+				field.args[name] = value
+			}
+			p.skipIgnored()
+		}
+		if p.peek() == '{' {
+			selections, err := p.parseSelectionSet()
+			if err != nil {
+				return nil, err
+			}
+			field.selections = selections
+		}
+		fields = append(fields, field)
+	}
+}
+
+func (p *graphQLParser) parseValue() (interface{}, error) {
+	p.skipIgnored()
+	switch c := p.peek(); {
+	case c == '$':
+		p.pos++
+		// Variables are decoded from JSON, so numbers are float64 like literals.
+		return p.variables[p.name()], nil
+	case c == '"':
+		start := p.pos
+		for p.pos++; p.pos < len(p.src) && p.src[p.pos] != '"'; p.pos++ {
+			if p.src[p.pos] == '\\' {
+				p.pos++
+			}
+		}
+		if p.pos >= len(p.src) {
+			return nil, p.errorf("unterminated string")
+		}
+		p.pos++
+		return strconv.Unquote(p.src[start:p.pos])
+	case c == '[':
+		p.pos++
+		list := []interface{}{}
+		for {
+			p.skipIgnored()
+			if p.peek() == ']' {
+				p.pos++
+				return list, nil
+			}
+			value, err := p.parseValue()
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, value)
+		}
+	case c == '{':
+		p.pos++
+		object := map[string]interface{}{}
+		for {
+			p.skipIgnored()
+			if p.peek() == '}' {
+				p.pos++
+				return object, nil
+			}
+			name := p.name()
+			if err := p.expect(':'); err != nil {
+				return nil, err
+			}
+			value, err := p.parseValue()
+			if err != nil {
+				return nil, err
+			}
+			object[name] = value
+		}
+	case c == '-' || c >= '0' && c <= '9':
+		start := p.pos
+		for p.pos++; p.pos < len(p.src) && strings.IndexByte("0123456789.eE+-", p.src[p.pos]) >= 0; p.pos++ {
+		}
+		return strconv.ParseFloat(p.src[start:p.pos], 64)
+	case isNameStart(c):
+		switch name := p.name(); name {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		case "null":
+			return nil, nil
+		default:
+			// Enum values are passed on as strings.
+			return name, nil
+		}
+	default:
+		return nil, p.errorf("expected value")
+	}
+}
+
+func (p *graphQLParser) skipBalanced(open, close byte) error {
+	depth := 0
+	for ; p.pos < len(p.src); p.pos++ {
+		switch p.src[p.pos] {
+		case open:
+			depth++
+		case close:
+			depth--
+			if depth == 0 {
+				p.pos++
+				return nil
+			}
+		}
+	}
+	return p.errorf("expected %q", close)
+}
+
+// skipIgnored skips white space and commas, which are insignificant in GraphQL.
+func (p *graphQLParser) skipIgnored() {
+	for p.pos < len(p.src) && strings.IndexByte(" \t\r\n,", p.src[p.pos]) >= 0 {
+		p.pos++
+	}
+}
+
+func (p *graphQLParser) peek() byte {
+	if p.pos < len(p.src) {
+		return p.src[p.pos]
+	}
+	return 0
+}
+
+func (p *graphQLParser) expect(c byte) error {
+	p.skipIgnored()
+	if p.peek() != c {
+		return p.errorf("expected %q", c)
+	}
+	p.pos++
+	return nil
+}
+
+func (p *graphQLParser) name() string {
+	start := p.pos
+	for p.pos < len(p.src) && (isNameStart(p.src[p.pos]) || p.src[p.pos] >= '0' && p.src[p.pos] <= '9') {
+		p.pos++
+	}
+	return p.src[start:p.pos]
+}
+
+func (p *graphQLParser) errorf(format string, a ...interface{}) error {
+	return fmt.Errorf("Syntax error at offset %d: %s", p.pos, fmt.Sprintf(format, a...))
+}
+
+func isNameStart(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
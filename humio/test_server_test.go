@@ -0,0 +1,320 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	humio "github.com/humio/cli/api"
+)
+
+func newFakeHumioClient(t *testing.T) (*apiClient, func()) {
+	server := newFakeHumioServer()
+	address, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return newAPIClient(address, fakeHumioToken, http.DefaultTransport), server.Close
+}
+
+func TestFakeHumioRepositories(t *testing.T) {
+	client, closeServer := newFakeHumioClient(t)
+	defer closeServer()
+
+	if err := client.createRepository(context.Background(), "repository-test"); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.createRepository(context.Background(), "repository-test"); err == nil {
+		t.Error("creating an existing repository succeeded")
+	}
+	if err := client.updateSearchDomainDescription(context.Background(), "repository-test", "some text"); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.updateRetention(context.Background(), "repository-test", timeBasedRetention, 30, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.updateRetention(context.Background(), "repository-test", storageSizeBasedRetention, 5, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.updateRetention(context.Background(), "repository-test", storageSizeBasedRetention, 0, false); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := client.getRepository(context.Background(), "repository-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := humio.Repository{Name: "repository-test", Description: "some text", RetentionDays: 30}
+	if !cmp.Equal(want, got) {
+		t.Error(cmp.Diff(want, got))
+	}
+
+	list, err := client.listRepositories(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 2 || list[0].Name != "repository-test" || list[1].Name != "sandbox" {
+		t.Errorf("unexpected repositories %v", list)
+	}
+
+	if err := client.deleteRepository(context.Background(), "repository-test", "test", false); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.getRepository(context.Background(), "repository-test"); !isNotFoundError(err) {
+		t.Errorf("getting a deleted repository returned %v, want a not found error", err)
+	}
+}
+
+func TestFakeHumioParsers(t *testing.T) {
+	client, closeServer := newFakeHumioClient(t)
+	defer closeServer()
+
+	parser := humio.Parser{
+		Name:      "parser-test",
+		Script:    "parseJson()",
+		Tests:     []humio.ParserTestCase{{Input: `{"a": 1}`, Output: map[string]string{}}},
+		TagFields: []string{"a"},
+	}
+	if err := client.addParser(context.Background(), "sandbox", &parser, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.addParser(context.Background(), "sandbox", &parser, false); err == nil {
+		t.Error("adding an existing parser without force succeeded")
+	}
+	got, err := client.getParser(context.Background(), "sandbox", "parser-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cmp.Equal(&parser, got) {
+		t.Error(cmp.Diff(&parser, got))
+	}
+
+	if err := client.removeParser(context.Background(), "sandbox", "parser-test"); err != nil {
+		t.Fatal(err)
+	}
+	got, err = client.getParser(context.Background(), "sandbox", "parser-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (&humio.Parser{Tests: []humio.ParserTestCase{}}); !cmp.Equal(want, got) {
+		t.Errorf("getting a removed parser returned %+v, want an empty parser", got)
+	}
+}
+
+func TestFakeHumioIngestTokens(t *testing.T) {
+	client, closeServer := newFakeHumioClient(t)
+	defer closeServer()
+
+	added, err := client.addIngestToken(context.Background(), "sandbox", "ingest-token-test", "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if added.Token == "" || added.AssignedParser != "json" {
+		t.Errorf("unexpected ingest token %+v", added)
+	}
+	if _, err := client.addIngestToken(context.Background(), "sandbox", "other-token", "missing"); err == nil {
+		t.Error("adding an ingest token with a missing parser succeeded")
+	}
+
+	if err := client.assignIngestToken(context.Background(), "sandbox", "ingest-token-test", ""); err != nil {
+		t.Fatal(err)
+	}
+	updated, err := client.listIngestTokens(context.Background(), "sandbox")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []humio.IngestToken{{Name: "ingest-token-test", Token: added.Token}}; !cmp.Equal(want, updated) {
+		t.Error(cmp.Diff(want, updated))
+	}
+
+	if err := client.removeIngestToken(context.Background(), "sandbox", "ingest-token-test"); err != nil {
+		t.Fatal(err)
+	}
+	tokens, err := client.listIngestTokens(context.Background(), "sandbox")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tokens) != 0 {
+		t.Errorf("unexpected ingest tokens %v", tokens)
+	}
+}
+
+func TestFakeHumioNotifiersAndAlerts(t *testing.T) {
+	client, closeServer := newFakeHumioClient(t)
+	defer closeServer()
+
+	notifier := humio.Notifier{
+		Entity:     humio.NotifierTypeEmail,
+		Name:       "notifier-test",
+		Properties: map[string]interface{}{"recipients": []interface{}{"test@example.com"}},
+	}
+	ctx := context.Background()
+	if err := client.createNotifier(ctx, "sandbox", &notifier); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.createNotifier(ctx, "sandbox", &notifier); err == nil {
+		t.Error("creating a notifier with the name of an existing one succeeded")
+	}
+	id, err := client.notifierID(ctx, "sandbox", "notifier-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id == "" {
+		t.Error("added notifier has no ID")
+	}
+	notifier.Properties["subjectTemplate"] = "{alert_name}"
+	if err := client.updateNotifier(ctx, "sandbox", &notifier); err != nil {
+		t.Fatal(err)
+	}
+	notifiers, err := client.listNotifiers(ctx, "sandbox")
+	if err != nil {
+		t.Fatal(err)
+	}
+	notifier.ID = id
+	if want := []humio.Notifier{notifier}; !cmp.Equal(want, notifiers) {
+		t.Error(cmp.Diff(want, notifiers))
+	}
+
+	alert := humio.Alert{
+		Name:               "alert-test",
+		Query:              humio.HumioQuery{QueryString: "loglevel=ERROR", Start: "24h", End: "now", IsLive: true},
+		ThrottleTimeMillis: 3600000,
+		Notifiers:          []string{id},
+		Labels:             []string{"errors"},
+	}
+	if err := client.createAlert(ctx, "sandbox", &alert); err != nil {
+		t.Fatal(err)
+	}
+	alert.Labels = []string{"errors", "critical"}
+	if err := client.updateAlert(ctx, "sandbox", &alert); err != nil {
+		t.Fatal(err)
+	}
+	alerts, err := client.listAlerts(ctx, "sandbox")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(alerts) != 1 {
+		t.Fatalf("unexpected alerts %v", alerts)
+	}
+	alert.ID = alerts[0].ID
+	if !cmp.Equal(alert, alerts[0]) {
+		t.Error(cmp.Diff(alert, alerts[0]))
+	}
+
+	if err := client.deleteAlert(ctx, "sandbox", "alert-test"); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.deleteNotifier(ctx, "sandbox", "notifier-test"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.notifierID(ctx, "sandbox", "notifier-test"); !isNotFoundError(err) {
+		t.Errorf("getting a deleted notifier returned %v, want a not found error", err)
+	}
+}
+
+func TestFakeHumioResources(t *testing.T) {
+	server := newFakeHumioServer()
+	defer server.Close()
+	p := Provider()
+	diags := p.Configure(context.Background(), terraform.NewResourceConfigRaw(map[string]interface{}{
+		"addr":      server.URL,
+		"api_token": fakeHumioToken,
+	}))
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	ctx := context.Background()
+
+	notifier := schema.TestResourceDataRaw(t, resourceNotifier().Schema, map[string]interface{}{
+		"repository": "sandbox",
+		"entity":     humio.NotifierTypeEmail,
+		"name":       "notifier-test",
+		"email":      []interface{}{map[string]interface{}{"recipients": []interface{}{"test@example.com"}}},
+	})
+	if diags := resourceNotifierCreate(ctx, notifier, p.Meta()); diags.HasError() {
+		t.Fatal(diags)
+	}
+	if notifier.Get("notifier_id") == "" {
+		t.Error("created notifier has no notifier_id")
+	}
+
+	alert := schema.TestResourceDataRaw(t, resourceAlert().Schema, map[string]interface{}{
+		"repository":           "sandbox",
+		"name":                 "alert-test",
+		"throttle_time_millis": 3600000,
+		"start":                "24h",
+		"query":                "loglevel=ERROR",
+		"notifiers":            []interface{}{notifier.Get("notifier_id")},
+	})
+	if diags := resourceAlertCreate(ctx, alert, p.Meta()); diags.HasError() {
+		t.Fatal(diags)
+	}
+	if got := alert.Get("notifiers").([]interface{}); len(got) != 1 || got[0] != notifier.Get("notifier_id") {
+		t.Errorf("unexpected notifiers %v", got)
+	}
+
+	if diags := resourceAlertDelete(ctx, alert, p.Meta()); diags.HasError() {
+		t.Fatal(diags)
+	}
+	if diags := resourceNotifierDelete(ctx, notifier, p.Meta()); diags.HasError() {
+		t.Fatal(diags)
+	}
+	if diags := resourceNotifierRead(ctx, notifier, p.Meta()); diags.HasError() || notifier.Id() != "" {
+		t.Errorf("reading a deleted notifier returned %v with ID %q, want it removed from state", diags, notifier.Id())
+	}
+}
+
+func TestFakeHumioRejectsInvalidToken(t *testing.T) {
+	server := newFakeHumioServer()
+	defer server.Close()
+	address, _ := url.Parse(server.URL)
+	client := newAPIClient(address, "invalid", http.DefaultTransport)
+
+	if _, err := client.getRepository(context.Background(), "sandbox"); err == nil {
+		t.Error("request with an invalid token succeeded")
+	}
+	var status humio.StatusResponse
+	if err := client.restJSON(context.Background(), http.MethodGet, "/api/v1/status", nil, &status); err != nil {
+		t.Fatal(err)
+	}
+	if status.Version != fakeHumioVersion {
+		t.Errorf("got version %q, want %q", status.Version, fakeHumioVersion)
+	}
+}
+
+func TestFakeHumioUnsupportedResource(t *testing.T) {
+	tests := []struct {
+		config string
+		want   string
+	}{
+		{config: alertFull, want: ""},
+		{config: repositoryBasic, want: ""},
+		{config: `resource "humio_view" "test" {}`, want: "humio_view"},
+		{config: "data \"humio_user\" \"test\" {}\nresource \"humio_parser\" \"test\" {}", want: "humio_user"},
+	}
+	for _, test := range tests {
+		got := fakeHumioUnsupportedResource([]resource.TestStep{{Config: test.config}})
+		if got != test.want {
+			t.Errorf("fakeHumioUnsupportedResource(%q) = %q, want %q", test.config, got, test.want)
+		}
+	}
+}
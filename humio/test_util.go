@@ -16,6 +16,8 @@ package humio
 
 import (
 	"os"
+	"os/exec"
+	"regexp"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
@@ -39,13 +41,120 @@ func testAccPreCheck(t *testing.T) {
 	}
 }
 
+// accTestCase runs steps against the Humio cluster given by HUMIO_ADDR and HUMIO_API_TOKEN. Without HUMIO_ADDR the
+// steps run against a fake Humio server started for the test, which does not require TF_ACC as long as a Terraform
+// binary is available.
+//
+// With HUMIO_TEST_RECORDING=record the requests sent to the cluster and the responses to them are recorded in a
+// cassette under testdata/cassettes when the test passes. With HUMIO_TEST_RECORDING=replay the steps run against the
+// responses in the cassette of the test instead of a cluster, and the test is skipped if it has no cassette.
 func accTestCase(t *testing.T, steps []resource.TestStep, checkDestroyFunc resource.TestCheckFunc) {
-	resource.Test(t, resource.TestCase{
+	testCase := resource.TestCase{
 		CheckDestroy: checkDestroyFunc,
 		PreCheck: func() {
 			testAccPreCheck(t)
 		},
 		Providers: testAccProviders,
 		Steps:     steps,
-	})
+	}
+
+	var rec *recorder
+	switch mode := os.Getenv(testRecordingEnvVar); mode {
+	case "":
+	case recordingModeRecord:
+		if os.Getenv("HUMIO_ADDR") == "" {
+			t.Fatalf("HUMIO_ADDR must be set to record %s", t.Name())
+		}
+		rec = newRecorder()
+	case recordingModeReplay:
+		var err error
+		rec, err = loadCassette(cassettePath(t.Name()))
+		if os.IsNotExist(err) {
+			t.Skipf("no cassette recorded for %s in %s", t.Name(), cassettePath(t.Name()))
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		requireTerraform(t, &testCase)
+
+		defer setTestEnv("HUMIO_ADDR", "https://humio.invalid/")()
+		defer setTestEnv("HUMIO_API_TOKEN", "SCRUBBED-API-TOKEN-1")()
+	default:
+		t.Fatalf("%s must be %q or %q, got %q", testRecordingEnvVar, recordingModeRecord, recordingModeReplay, mode)
+	}
+	if rec != nil {
+		wrapAPITransport = rec.transport
+		defer func() {
+			wrapAPITransport = nil
+		}()
+	}
+
+	if os.Getenv("HUMIO_ADDR") == "" {
+		if resourceType := fakeHumioUnsupportedResource(steps); resourceType != "" {
+			t.Skipf("the fake Humio server does not implement %s, set HUMIO_ADDR to run this test against a cluster", resourceType)
+		}
+		requireTerraform(t, &testCase)
+
+		server := newFakeHumioServer()
+		defer server.Close()
+		defer setTestEnv("HUMIO_ADDR", server.URL)()
+		defer setTestEnv("HUMIO_API_TOKEN", fakeHumioToken)()
+	}
+
+	resource.Test(t, testCase)
+
+	if rec != nil && rec.mode == recordingModeRecord && !t.Failed() {
+		if err := rec.save(cassettePath(t.Name())); err != nil {
+			t.Fatalf("could not save cassette: %s", err)
+		}
+	}
+}
+
+// requireTerraform runs testCase as a unit test if a Terraform binary is available, and skips the test unless TF_ACC
+// is set otherwise.
+func requireTerraform(t *testing.T, testCase *resource.TestCase) {
+	if terraformAvailable() {
+		testCase.IsUnitTest = true
+		return
+	}
+	if os.Getenv(resource.TestEnvVar) == "" {
+		t.Skip("no terraform binary found, set TF_ACC_TERRAFORM_PATH or TF_ACC to run acceptance tests")
+	}
+}
+
+var rxTestConfigType = regexp.MustCompile(`(?m)^\s*(?:resource|data)\s+"(humio_\w+)"`)
+
+// fakeHumioUnsupportedResource returns the first resource or data source type used by steps which the fake Humio
+// server does not implement, or an empty string if it implements all of them.
+func fakeHumioUnsupportedResource(steps []resource.TestStep) string {
+	for _, step := range steps {
+		for _, match := range rxTestConfigType.FindAllStringSubmatch(step.Config, -1) {
+			if !fakeHumioResources[match[1]] {
+				return match[1]
+			}
+		}
+	}
+	return ""
+}
+
+// terraformAvailable reports whether the test framework can find a Terraform binary without downloading one.
+func terraformAvailable() bool {
+	if os.Getenv("TF_ACC_TERRAFORM_PATH") != "" {
+		return true
+	}
+	_, err := exec.LookPath("terraform")
+	return err == nil
+}
+
+// setTestEnv sets the environment variable key to value and returns a function restoring its previous value.
+func setTestEnv(key, value string) func() {
+	old, ok := os.LookupEnv(key)
+	os.Setenv(key, value)
+	return func() {
+		if ok {
+			os.Setenv(key, old)
+		} else {
+			os.Unsetenv(key)
+		}
+	}
 }
@@ -0,0 +1,102 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// pemFromResourceData returns the PEM given in either <name>_pem or the file at <name>_file.
+func pemFromResourceData(r *schema.ResourceData, name string) (string, error) {
+	if v, ok := r.GetOk(name + "_pem"); ok {
+		return v.(string), nil
+	}
+	if path, ok := r.GetOk(name + "_file"); ok {
+		content, err := ioutil.ReadFile(path.(string))
+		if err != nil {
+			return "", fmt.Errorf("could not read %s_file: %w", name, err)
+		}
+		return string(content), nil
+	}
+	return "", nil
+}
+
+func (c *transportConfig) tlsConfig() (*tls.Config, error) {
+	config := &tls.Config{
+		ServerName:         c.serverName,
+		InsecureSkipVerify: c.insecureSkipVerify,
+	}
+
+	if c.caCertificatePEM != "" {
+		certificates, err := parseCertificateBundle([]byte(c.caCertificatePEM))
+		if err != nil {
+			return nil, fmt.Errorf("invalid CA certificate: %w", err)
+		}
+		config.RootCAs = x509.NewCertPool()
+		for _, certificate := range certificates {
+			config.RootCAs.AddCert(certificate)
+		}
+	}
+
+	switch {
+	case c.clientCertificatePEM != "" && c.clientKeyPEM != "":
+		certificate, err := tls.X509KeyPair([]byte(c.clientCertificatePEM), []byte(c.clientKeyPEM))
+		if err != nil {
+			return nil, fmt.Errorf("invalid client certificate: %w", err)
+		}
+		config.Certificates = []tls.Certificate{certificate}
+	case c.clientCertificatePEM != "":
+		return nil, errors.New("a client certificate was given without a client key")
+	case c.clientKeyPEM != "":
+		return nil, errors.New("a client key was given without a client certificate")
+	}
+
+	return config, nil
+}
+
+// parseCertificateBundle parses every certificate in a PEM bundle. Unlike x509.CertPool.AppendCertsFromPEM, it fails
+// on blocks that are not valid certificates and on anything else in the bundle, rather than silently skipping them.
+func parseCertificateBundle(bundle []byte) ([]*x509.Certificate, error) {
+	var certificates []*x509.Certificate
+	rest := bytes.TrimSpace(bundle)
+	for len(rest) > 0 {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			return nil, fmt.Errorf("certificate %d is not PEM encoded", len(certificates)+1)
+		}
+		if block.Type != "CERTIFICATE" {
+			return nil, fmt.Errorf("certificate %d is a %s, not a CERTIFICATE", len(certificates)+1, block.Type)
+		}
+		certificate, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("certificate %d could not be parsed: %w", len(certificates)+1, err)
+		}
+		certificates = append(certificates, certificate)
+		rest = bytes.TrimSpace(rest)
+	}
+	if len(certificates) == 0 {
+		return nil, errors.New("no certificates found")
+	}
+	return certificates, nil
+}
@@ -0,0 +1,203 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+// testCertificate returns a certificate signed by parent, or a self-signed CA certificate if parent is nil, along with
+// its PEM encoded certificate and key.
+func testCertificate(t *testing.T, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey, string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: "terraform-provider-humio"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		parent, parentKey = template, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certificate, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certificatePEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	return certificate, key, string(certificatePEM), string(keyPEM)
+}
+
+func TestParseCertificateBundle(t *testing.T) {
+	_, _, first, key := testCertificate(t, nil, nil)
+	_, _, second, _ := testCertificate(t, nil, nil)
+
+	tests := []struct {
+		name   string
+		bundle string
+		want   int
+		err    string
+	}{
+		{name: "single", bundle: first, want: 1},
+		{name: "multiple", bundle: first + "\n" + second, want: 2},
+		{name: "comments", bundle: "# Humio CA\n" + first, want: 1},
+		{name: "empty", bundle: "\n", err: "no certificates found"},
+		{name: "trailing garbage", bundle: first + "garbage", err: "certificate 2 is not PEM encoded"},
+		{name: "private key", bundle: first + key, err: "certificate 2 is a EC PRIVATE KEY"},
+		{name: "corrupt", bundle: strings.Replace(second, "MII", "MIA", 1), err: "certificate 1 could not be parsed"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := parseCertificateBundle([]byte(test.bundle))
+			if test.err != "" {
+				if err == nil || !strings.Contains(err.Error(), test.err) {
+					t.Errorf("expected error %q, got %v", test.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != test.want {
+				t.Errorf("expected %d certificates, got %d", test.want, len(got))
+			}
+		})
+	}
+}
+
+func TestProviderMutualTLS(t *testing.T) {
+	clientCA, clientCAKey, _, _ := testCertificate(t, nil, nil)
+	_, _, clientCertificatePEM, clientKeyPEM := testCertificate(t, clientCA, clientCAKey)
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(clientCA)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"status": "OK", "version": "1.24.0"}`))
+	}))
+	server.TLS = &tls.Config{
+		ClientAuth: tls.RequireAndVerifyClientCert,
+		ClientCAs:  clientCAs,
+	}
+	server.StartTLS()
+	defer server.Close()
+	serverCAPEM := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}))
+
+	tests := []struct {
+		name   string
+		config map[string]interface{}
+		err    string
+	}{
+		{
+			name: "client certificate",
+			config: map[string]interface{}{
+				"ca_certificate_pem":     serverCAPEM,
+				"client_certificate_pem": clientCertificatePEM,
+				"client_key_pem":         clientKeyPEM,
+				"tls_server_name":        "example.com",
+			},
+		},
+		{
+			name: "insecure",
+			config: map[string]interface{}{
+				"client_certificate_pem": clientCertificatePEM,
+				"client_key_pem":         clientKeyPEM,
+				"insecure_skip_verify":   true,
+			},
+		},
+		{
+			name: "unknown CA",
+			config: map[string]interface{}{
+				"client_certificate_pem": clientCertificatePEM,
+				"client_key_pem":         clientKeyPEM,
+			},
+			err: "certificate",
+		},
+		{
+			name: "wrong server name",
+			config: map[string]interface{}{
+				"ca_certificate_pem":     serverCAPEM,
+				"client_certificate_pem": clientCertificatePEM,
+				"client_key_pem":         clientKeyPEM,
+				"tls_server_name":        "humio.example.org",
+			},
+			err: "humio.example.org",
+		},
+		{
+			name: "missing client certificate",
+			config: map[string]interface{}{
+				"ca_certificate_pem": serverCAPEM,
+			},
+			err: "Could not get the version",
+		},
+		{
+			name: "missing client key",
+			config: map[string]interface{}{
+				"ca_certificate_pem":     serverCAPEM,
+				"client_certificate_pem": clientCertificatePEM,
+			},
+			err: "without a client key",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.config["addr"] = server.URL
+			test.config["api_token"] = "secret"
+			test.config["retry_max_attempts"] = 1
+			diags := Provider().Configure(context.Background(), terraform.NewResourceConfigRaw(test.config))
+			if test.err == "" {
+				if diags.HasError() {
+					t.Fatal(diags)
+				}
+				return
+			}
+			if !diags.HasError() {
+				t.Fatalf("expected error %q", test.err)
+			}
+			if msg := diags[0].Summary + diags[0].Detail; !strings.Contains(msg, test.err) {
+				t.Errorf("expected error %q, got %q", test.err, msg)
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"golang.org/x/net/http/httpproxy"
+)
+
+// wrapAPITransport, when set, wraps the transport the provider sends requests to Humio with. It is nil outside of the
+// tests, which use it to record the requests and responses of acceptance tests, and to replay them later.
+var wrapAPITransport func(http.RoundTripper) http.RoundTripper
+
+// transportConfig holds the provider settings for the connection to Humio.
+type transportConfig struct {
+	caCertificatePEM     string
+	clientCertificatePEM string
+	clientKeyPEM         string
+	serverName           string
+	insecureSkipVerify   bool
+
+	proxyURL      string
+	proxyUsername string
+	proxyPassword string
+	noProxy       string
+}
+
+func transportConfigFromResourceData(r *schema.ResourceData) (*transportConfig, error) {
+	var err error
+	config := &transportConfig{
+		serverName:         r.Get("tls_server_name").(string),
+		insecureSkipVerify: r.Get("insecure_skip_verify").(bool),
+		proxyURL:           r.Get("proxy_url").(string),
+		proxyUsername:      r.Get("proxy_username").(string),
+		proxyPassword:      r.Get("proxy_password").(string),
+		noProxy:            r.Get("no_proxy").(string),
+	}
+	if config.caCertificatePEM, err = pemFromResourceData(r, "ca_certificate"); err != nil {
+		return nil, err
+	}
+	if config.clientCertificatePEM, err = pemFromResourceData(r, "client_certificate"); err != nil {
+		return nil, err
+	}
+	if config.clientKeyPEM, err = pemFromResourceData(r, "client_key"); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// newTransport returns the transport used for requests to Humio, based on http.DefaultTransport.
+func newTransport(config *transportConfig) (*http.Transport, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	tlsConfig, err := config.tlsConfig()
+	if err != nil {
+		return nil, err
+	}
+	transport.TLSClientConfig = tlsConfig
+
+	proxy, err := config.proxy()
+	if err != nil {
+		return nil, err
+	}
+	if proxy != nil {
+		transport.Proxy = proxy
+	}
+	return transport, nil
+}
+
+// proxy returns the proxy function for the proxy settings, or nil to use the proxy given by the HTTPS_PROXY, HTTP_PROXY
+// and NO_PROXY environment variables.
+func (c *transportConfig) proxy() (func(*http.Request) (*url.URL, error), error) {
+	if c.proxyURL == "" {
+		if c.proxyUsername != "" || c.proxyPassword != "" {
+			return nil, errors.New("proxy_username and proxy_password require proxy_url")
+		}
+		if c.noProxy != "" {
+			return nil, errors.New("no_proxy requires proxy_url, set NO_PROXY instead to exclude hosts from the proxy given by HTTPS_PROXY or HTTP_PROXY")
+		}
+		return nil, nil
+	}
+
+	proxyURL, err := url.Parse(c.proxyURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy_url: %w", err)
+	}
+	if c.proxyUsername != "" {
+		proxyURL.User = url.UserPassword(c.proxyUsername, c.proxyPassword)
+	}
+
+	config := &httpproxy.Config{
+		HTTPProxy:  proxyURL.String(),
+		HTTPSProxy: proxyURL.String(),
+		NoProxy:    c.noProxy,
+	}
+	proxyFunc := config.ProxyFunc()
+	return func(req *http.Request) (*url.URL, error) {
+		return proxyFunc(req.URL)
+	}, nil
+}
@@ -0,0 +1,131 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	humio "github.com/humio/cli/api"
+)
+
+var rxServerVersion = regexp.MustCompile(`^(\d+)\.(\d+)\.(\d+)`)
+
+// The first Humio versions supporting features managed by the provider.
+var (
+	// minActionsVersion is the first version with the Actions GraphQL API, which replaced notifiers.
+	minActionsVersion = serverVersion{1, 24, 0}
+	// minAlertLabelsVersion is the first version accepting labels on alerts.
+	minAlertLabelsVersion = serverVersion{1, 19, 0}
+	// minScheduledSearchesVersion is the first version with scheduled searches.
+	minScheduledSearchesVersion = serverVersion{1, 22, 0}
+)
+
+// serverVersion is the major, minor and patch version of a Humio cluster.
+type serverVersion [3]int
+
+// parseServerVersion parses versions as reported by the Humio status endpoint, e.g. 1.24.3--build-1234--sha-abc.
+func parseServerVersion(s string) (serverVersion, error) {
+	m := rxServerVersion.FindStringSubmatch(s)
+	if m == nil {
+		return serverVersion{}, fmt.Errorf("could not parse Humio version %q", s)
+	}
+	var v serverVersion
+	for i := range v {
+		v[i], _ = strconv.Atoi(m[i+1])
+	}
+	return v, nil
+}
+
+func (v serverVersion) atLeast(other serverVersion) bool {
+	for i := range v {
+		if v[i] != other[i] {
+			return v[i] > other[i]
+		}
+	}
+	return true
+}
+
+func (v serverVersion) String() string {
+	return fmt.Sprintf("%d.%d.%d", v[0], v[1], v[2])
+}
+
+// known reports whether the version was detected. It is unknown when the version check is skipped.
+func (v serverVersion) known() bool {
+	return v != serverVersion{}
+}
+
+// detectServerVersion looks up the version of the Humio cluster. It is called once when the provider is configured, so
+// resources can compare c.version against the version they require.
+func (c *apiClient) detectServerVersion(ctx context.Context) error {
+	return c.retry(ctx, "get Humio version", func() error {
+		var status humio.StatusResponse
+		if err := c.restJSON(ctx, http.MethodGet, "/api/v1/status", nil, &status); err != nil {
+			return err
+		}
+		version, err := parseServerVersion(status.Version)
+		c.version = version
+		return err
+	})
+}
+
+// requireServerVersion returns an error diagnostic if the Humio cluster is older than min, the first version
+// supporting resourceType.
+func requireServerVersion(client interface{}, resourceType string, min serverVersion, alternative string) diag.Diagnostics {
+	if err := checkServerVersion(client, resourceType, min, alternative); err != nil {
+		return diag.Diagnostics{diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  fmt.Sprintf("%s is not supported by this Humio cluster", resourceType),
+			Detail:   err.Error(),
+		}}
+	}
+	return nil
+}
+
+func checkServerVersion(client interface{}, feature string, min serverVersion, alternative string) error {
+	version := client.(*apiClient).version
+	if !version.known() || version.atLeast(min) {
+		return nil
+	}
+	msg := fmt.Sprintf("%s requires Humio >= %s, but the cluster at %s runs Humio %s.", feature, min, client.(*apiClient).address, version)
+	if alternative != "" {
+		msg += fmt.Sprintf(" Use %s with this cluster, or upgrade Humio.", alternative)
+	}
+	return errors.New(msg)
+}
+
+// customizeDiffRequireServerVersion fails the plan of resourceType when the Humio cluster is older than min.
+func customizeDiffRequireServerVersion(resourceType string, min serverVersion, alternative string) schema.CustomizeDiffFunc {
+	return func(ctx context.Context, d *schema.ResourceDiff, client interface{}) error {
+		return checkServerVersion(client, resourceType, min, alternative)
+	}
+}
+
+// customizeDiffRequireFieldServerVersion fails the plan when field is set but the Humio cluster is older than min, the
+// first version supporting it.
+func customizeDiffRequireFieldServerVersion(resourceType, field string, min serverVersion) schema.CustomizeDiffFunc {
+	return func(ctx context.Context, d *schema.ResourceDiff, client interface{}) error {
+		if _, ok := d.GetOk(field); !ok {
+			return nil
+		}
+		return checkServerVersion(client, fmt.Sprintf("%s field %s", resourceType, field), min, "")
+	}
+}
@@ -0,0 +1,156 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestParseServerVersion(t *testing.T) {
+	tests := []struct {
+		in   string
+		want serverVersion
+		err  bool
+	}{
+		{in: "1.24.0", want: serverVersion{1, 24, 0}},
+		{in: "1.18.2--build-195624--sha-7b8b2bc3ae", want: serverVersion{1, 18, 2}},
+		{in: "dev", err: true},
+		{in: "", err: true},
+	}
+	for _, test := range tests {
+		got, err := parseServerVersion(test.in)
+		if (err != nil) != test.err {
+			t.Errorf("parseServerVersion(%q): unexpected error %v", test.in, err)
+		}
+		if got != test.want {
+			t.Errorf("parseServerVersion(%q) = %s, want %s", test.in, got, test.want)
+		}
+	}
+}
+
+func TestServerVersionAtLeast(t *testing.T) {
+	tests := []struct {
+		v, min serverVersion
+		want   bool
+	}{
+		{serverVersion{1, 24, 0}, serverVersion{1, 24, 0}, true},
+		{serverVersion{1, 24, 1}, serverVersion{1, 24, 0}, true},
+		{serverVersion{2, 0, 0}, serverVersion{1, 24, 0}, true},
+		{serverVersion{1, 23, 9}, serverVersion{1, 24, 0}, false},
+		{serverVersion{0, 99, 0}, serverVersion{1, 24, 0}, false},
+	}
+	for _, test := range tests {
+		if got := test.v.atLeast(test.min); got != test.want {
+			t.Errorf("%s.atLeast(%s) = %v, want %v", test.v, test.min, got, test.want)
+		}
+	}
+}
+
+func TestRequireServerVersion(t *testing.T) {
+	address, _ := url.Parse("https://humio.example.org")
+	c := &apiClient{address: address, version: serverVersion{1, 18, 2}}
+
+	diags := requireServerVersion(c, "humio_action", minActionsVersion, "humio_notifier")
+	if !diags.HasError() {
+		t.Fatal("expected an error for an old Humio version")
+	}
+	if detail := diags[0].Detail; !strings.Contains(detail, ">= 1.24.0") || !strings.Contains(detail, "1.18.2") || !strings.Contains(detail, "humio_notifier") {
+		t.Errorf("unexpected detail %q", detail)
+	}
+	if diags := requireServerVersion(c, "humio_notifier", serverVersion{1, 0, 0}, ""); diags.HasError() {
+		t.Errorf("unexpected error %v", diags)
+	}
+
+	c.version = serverVersion{}
+	if diags := requireServerVersion(c, "humio_action", minActionsVersion, "humio_notifier"); diags.HasError() {
+		t.Errorf("expected no error when the version check is skipped, got %v", diags)
+	}
+}
+
+func TestRequireFieldServerVersion(t *testing.T) {
+	address, _ := url.Parse("https://humio.example.org")
+	c := &apiClient{address: address, version: serverVersion{1, 18, 2}}
+
+	err := checkServerVersion(c, "humio_alert field labels", minAlertLabelsVersion, "")
+	if err == nil || !strings.HasPrefix(err.Error(), "humio_alert field labels requires Humio >= 1.19.0") {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestProviderConfigureDetectsServerVersion(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/status" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`{"status": "OK", "version": "1.18.2--build-195624--sha-7b8b2bc3ae"}`))
+	}))
+	defer server.Close()
+
+	p := Provider()
+	diags := p.Configure(context.Background(), terraform.NewResourceConfigRaw(map[string]interface{}{
+		"addr":      server.URL,
+		"api_token": "secret",
+	}))
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	if got := p.Meta().(*apiClient).version; got != (serverVersion{1, 18, 2}) {
+		t.Errorf("unexpected version %s", got)
+	}
+}
+
+func TestProviderConfigureSkipVersionCheck(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request to %s", r.URL)
+	}))
+	defer server.Close()
+
+	p := Provider()
+	diags := p.Configure(context.Background(), terraform.NewResourceConfigRaw(map[string]interface{}{
+		"addr":               server.URL,
+		"api_token":          "secret",
+		"skip_version_check": true,
+	}))
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	if p.Meta().(*apiClient).version.known() {
+		t.Error("expected the version to be unknown")
+	}
+}
+
+func TestProviderConfigureVersionCheckFails(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	p := Provider()
+	diags := p.Configure(context.Background(), terraform.NewResourceConfigRaw(map[string]interface{}{
+		"addr":      server.URL,
+		"api_token": "secret",
+	}))
+	if !diags.HasError() || !strings.Contains(diags[0].Detail, "skip_version_check") {
+		t.Errorf("expected an error mentioning skip_version_check, got %v", diags)
+	}
+}
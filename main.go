@@ -15,12 +15,28 @@
 package main
 
 import (
+	"context"
+	"flag"
+	"log"
+	"os"
+
 	"github.com/hashicorp/terraform-plugin-sdk/v2/plugin"
 
 	"github.com/humio/terraform-provider-humio/humio"
 )
 
 func main() {
+	var export bool
+	flag.BoolVar(&export, "export", false, "write HCL and import blocks for the objects in the Humio cluster configured with HUMIO_ADDR and HUMIO_API_TOKEN to stdout. Repository names can be given as arguments to limit the export.")
+	flag.Parse()
+
+	if export {
+		if err := humio.Export(context.Background(), os.Stdout, flag.Args()); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
 	plugin.Serve(&plugin.ServeOpts{
 		ProviderFunc: humio.Provider,
 	})